@@ -0,0 +1,89 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Ng1n3/go-todo/internal/service"
+)
+
+func init() {
+	register(&command{
+		name:    "agenda",
+		usage:   "agenda [-filter query] [file...]",
+		summary: "Show todos grouped into overdue, today, tomorrow, this week, later and no date",
+		run:     runAgenda,
+	})
+	register(&command{
+		name:    "calendar",
+		usage:   "calendar [-month YYYY-MM] [-filter query] [file...]",
+		summary: "Show a month calendar marking the days todos are due",
+		run:     runCalendar,
+	})
+}
+
+func runAgenda(app *App, args []string) error {
+	fs := app.flagSet("agenda")
+	query := fs.String("filter", "", "only show todos matching a filter query")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	filter, files, err := app.loadFiltered(*query, fs.Args())
+	if err != nil {
+		return err
+	}
+
+	app.display.ShowAgenda(service.Agenda(files, filter, time.Now()))
+	return nil
+}
+
+func runCalendar(app *App, args []string) error {
+	fs := app.flagSet("calendar")
+	monthFlag := fs.String("month", "", "month to show, as YYYY-MM (default this month)")
+	query := fs.String("filter", "", "only show todos matching a filter query")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	month := now
+	if *monthFlag != "" {
+		var err error
+		if month, err = time.Parse("2006-01", *monthFlag); err != nil {
+			return fmt.Errorf("invalid -month %q: use YYYY-MM", *monthFlag)
+		}
+	}
+
+	filter, files, err := app.loadFiltered(*query, fs.Args())
+	if err != nil {
+		return err
+	}
+
+	app.display.ShowCalendar(service.Calendar(files, filter, month, now), now)
+	return nil
+}
+
+// loadFiltered parses a filter query and loads the named todo files, or all
+// of them when names is empty.
+func (a *App) loadFiltered(query string, names []string) (service.Filter, []service.FileTodos, error) {
+	filter, err := service.ParseFilter(query)
+	if err != nil {
+		return service.Filter{}, nil, err
+	}
+
+	filenames := make([]string, 0, len(names))
+	for _, name := range names {
+		filename, err := a.existingFile(name)
+		if err != nil {
+			return service.Filter{}, nil, err
+		}
+		filenames = append(filenames, filename)
+	}
+
+	files, err := service.NewWorkspace(a.config).Load(filenames)
+	if err != nil {
+		return service.Filter{}, nil, err
+	}
+	return filter, files, nil
+}
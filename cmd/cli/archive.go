@@ -0,0 +1,135 @@
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/Ng1n3/go-todo/internal/config"
+	"github.com/Ng1n3/go-todo/internal/service"
+	"github.com/Ng1n3/go-todo/internal/utils"
+)
+
+func init() {
+	register(&command{
+		name:    "archive",
+		usage:   "archive [-older-than 30d] [-filter query] <file> [todo...]",
+		summary: "Move completed todos, or the given ones, into the file's archive",
+		run:     runArchive,
+	})
+	register(&command{
+		name:    "archived",
+		usage:   "archived [-filter query] <file>",
+		summary: "List the archived todos of a todo file",
+		run:     runArchived,
+	})
+	register(&command{
+		name:    "unarchive",
+		usage:   "unarchive [-filter query] <file> [todo...]",
+		summary: "Move archived todos back into their todo file",
+		run:     runUnarchive,
+	})
+}
+
+func runArchive(app *App, args []string) error {
+	fs := app.flagSet("archive")
+	olderThan := fs.String("older-than", utils.FormatDuration(app.config.ArchiveAfter), "when no todos are given, archive todos completed at least this long ago")
+	query := fs.String("filter", "", "archive the todos matching a filter query")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() < 1 {
+		fs.Usage()
+		return fmt.Errorf("expected a todo file")
+	}
+
+	ts, _, err := app.openFile(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	var results []service.TransferResult
+	if fs.NArg() == 1 && *query == "" {
+		age, parseErr := utils.ParseDuration(*olderThan)
+		if parseErr != nil || age < 0 {
+			return fmt.Errorf("invalid -older-than %q: use e.g. 30d or 0 for all completed todos", *olderThan)
+		}
+		results, err = ts.ArchiveCompleted(age, time.Now())
+	} else {
+		var ids []string
+		if ids, err = selectIDs(ts, fs.Args()[1:], *query); err != nil {
+			return err
+		}
+		results, err = ts.Archive(ids)
+	}
+	if results == nil && err != nil {
+		return err
+	}
+
+	if len(results) == 0 {
+		app.display.ShowInfo("Nothing to archive")
+		return err
+	}
+	app.display.ShowTransfers("move", ts.File(), filepath.Join(config.ArchiveDir, ts.File()), results)
+	return err
+}
+
+func runArchived(app *App, args []string) error {
+	fs := app.flagSet("archived")
+	query := fs.String("filter", "", "only list the archived todos matching a filter query")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("expected exactly one todo file")
+	}
+
+	archive, err := app.openArchive(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	filter, err := service.ParseFilter(*query)
+	if err != nil {
+		return err
+	}
+	return app.display.ShowFileTodos([]service.FileTodos{{File: archive.File(), Todos: archive.ListFiltered(filter)}})
+}
+
+func runUnarchive(app *App, args []string) error {
+	fs := app.flagSet("unarchive")
+	query := fs.String("filter", "", "restore the archived todos matching a filter query")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() < 2 && *query == "" {
+		fs.Usage()
+		return fmt.Errorf("expected a todo file and todos or a -filter")
+	}
+
+	ts, _, err := app.openFile(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	archive, err := ts.OpenArchive()
+	if err != nil {
+		return err
+	}
+
+	ids, err := selectIDs(archive, fs.Args()[1:], *query)
+	if err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		app.display.ShowInfo("No archived todos match")
+		return nil
+	}
+
+	results, err := ts.Unarchive(archive, ids)
+	if results == nil && err != nil {
+		return err
+	}
+	app.display.ShowTransfers("move", archive.File(), ts.File(), results)
+	return err
+}
@@ -0,0 +1,122 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Ng1n3/go-todo/internal/service"
+	"github.com/Ng1n3/go-todo/internal/types"
+	"github.com/Ng1n3/go-todo/internal/utils"
+)
+
+func init() {
+	register(&command{
+		name:    "bulk",
+		usage:   "bulk [-filter query] [-priority p] [-add-label l]... [-remove-label l]... [-shift 3d] [-complete] [-delete] [-dry-run] [-yes] <file> [todo...]",
+		summary: "Update or delete many todos at once, with a preview and a single undo",
+		run:     runBulk,
+	})
+	register(&command{
+		name:    "undo",
+		usage:   "undo [-force] <file>",
+		summary: "Revert the last bulk update of a todo file",
+		run:     runUndo,
+	})
+}
+
+func runBulk(app *App, args []string) error {
+	fs := app.flagSet("bulk")
+	query := fs.String("filter", "", "update the todos matching a filter query")
+	priority := fs.String("priority", "", "set the priority: low, medium or high")
+	var addLabels, removeLabels stringList
+	fs.Var(&addLabels, "add-label", "add a label; repeatable or comma-separated")
+	fs.Var(&removeLabels, "remove-label", "remove a label; repeatable or comma-separated")
+	shift := fs.String("shift", "", "move due dates by whole days, e.g. 3d or -1w")
+	complete := fs.Bool("complete", false, "mark the todos as completed")
+	remove := fs.Bool("delete", false, "delete the todos (they go to the trash)")
+	dryRun := fs.Bool("dry-run", false, "only show what would change")
+	yes := fs.Bool("yes", false, "don't ask for confirmation")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() < 1 {
+		fs.Usage()
+		return fmt.Errorf("expected a todo file")
+	}
+	if fs.NArg() == 1 && *query == "" {
+		return fmt.Errorf("select the todos to update with -filter or by reference")
+	}
+
+	update := service.BulkUpdate{
+		Priority:     types.Priority(*priority),
+		AddLabels:    utils.ValidateLabels(strings.Join(addLabels, ",")),
+		RemoveLabels: utils.ValidateLabels(strings.Join(removeLabels, ",")),
+		Complete:     *complete,
+		Delete:       *remove,
+	}
+	if *shift != "" {
+		offset, err := utils.ParseDuration(*shift)
+		if err != nil {
+			return fmt.Errorf("invalid -shift %q: use e.g. 3d or -1w", *shift)
+		}
+		update.ShiftDue = offset
+	}
+
+	ts, _, err := app.openFile(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	ids, err := selectIDs(ts, fs.Args()[1:], *query)
+	if err != nil {
+		return err
+	}
+
+	changes, err := ts.PreviewBulk(ids, update)
+	if err != nil {
+		return err
+	}
+	app.display.ShowBulkChanges(changes)
+	if *dryRun || len(changes) == 0 {
+		return nil
+	}
+
+	if !*yes {
+		ok, err := app.input.ReadBool(fmt.Sprintf("Apply %q to %d todos? (y/n): ", update, len(changes)))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			app.display.ShowInfo("Nothing changed")
+			return nil
+		}
+	}
+
+	if changes, err = ts.ApplyBulk(ids, update); err != nil {
+		return err
+	}
+	app.display.ShowSuccess(fmt.Sprintf("Changed %d todos; run `undo %s` to revert", len(changes), ts.File()))
+	return nil
+}
+
+func runUndo(app *App, args []string) error {
+	fs := app.flagSet("undo")
+	force := fs.Bool("force", false, "undo even if the file was changed since the bulk update")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("expected a todo file")
+	}
+
+	ts, _, err := app.openFile(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	point, err := ts.UndoBulk(*force)
+	if err != nil {
+		return err
+	}
+	app.display.ShowSuccess(fmt.Sprintf("Reverted %s in %s", point.Description, point.File))
+	return nil
+}
@@ -0,0 +1,233 @@
+// Package cli implements the non-interactive sub-commands of the go-todo
+// binary, such as `remind`. Running the binary without a sub-command starts
+// the full-screen interface instead, or the numbered menu when stdin or stdout
+// isn't a terminal or the interface setting is "menu".
+//
+// Every sub-command registers itself from its own file through register, and
+// receives the shared App holding the configuration and UI helpers.
+package cli
+
+import (
+	stderrors "errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/Ng1n3/go-todo/internal/config"
+	"github.com/Ng1n3/go-todo/internal/errors"
+	"github.com/Ng1n3/go-todo/internal/service"
+	"github.com/Ng1n3/go-todo/internal/ui"
+	"github.com/Ng1n3/go-todo/internal/utils"
+)
+
+type command struct {
+	name    string
+	usage   string
+	summary string
+	run     func(app *App, args []string) error
+}
+
+var commands = make(map[string]*command)
+
+func register(cmd *command) {
+	commands[cmd.name] = cmd
+}
+
+type App struct {
+	config  *config.Config
+	display *ui.Display
+	input   *ui.InputReader
+	stdout  io.Writer
+}
+
+func NewApp(cfg *config.Config) *App {
+	if cfg == nil {
+		cfg = config.Default()
+	}
+	input := ui.NewInputReader()
+	if cfg.Passphrase == nil {
+		cfg.Passphrase = input.PassphrasePrompt()
+	}
+
+	return &App{
+		config:  cfg,
+		display: ui.NewDisplay(),
+		input:   input,
+		stdout:  os.Stdout,
+	}
+}
+
+// openFile opens an existing todo file given by name, with or without the
+// .json extension.
+func (a *App) openFile(name string) (*service.TodoService, string, error) {
+	return a.open(name, false)
+}
+
+// openOrCreateFile opens a todo file that is created on the first save if
+// it doesn't exist yet.
+func (a *App) openOrCreateFile(name string) (*service.TodoService, string, error) {
+	return a.open(name, true)
+}
+
+func (a *App) open(name string, create bool) (*service.TodoService, string, error) {
+	filename, err := a.existingFile(name)
+	if create && stderrors.Is(err, errors.ErrFileNotFound) {
+		filename, err = utils.NormalizeFileName(name)
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	ts, err := service.NewTodoService(a.config.GetFullPath(filename), a.config)
+	if err != nil {
+		return nil, "", err
+	}
+	return ts, filename, nil
+}
+
+// openArchive opens the archive of the named todo file.
+func (a *App) openArchive(name string) (*service.TodoService, error) {
+	ts, _, err := a.openFile(name)
+	if err != nil {
+		return nil, err
+	}
+	return ts.OpenArchive()
+}
+
+// existingFile normalizes the name of a todo file and checks that it exists.
+func (a *App) existingFile(name string) (string, error) {
+	filename, err := utils.NormalizeFileName(name)
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(a.config.GetFullPath(filename)); os.IsNotExist(err) {
+		return "", fmt.Errorf("%s: %w", filename, errors.ErrFileNotFound)
+	}
+	return filename, nil
+}
+
+// Run executes the sub-command named by args[0].
+func (a *App) Run(args []string) error {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		a.usage()
+		return nil
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		a.usage()
+		return fmt.Errorf("unknown command %q", args[0])
+	}
+
+	renderer, err := ui.NewRenderer(a.config.Output)
+	if err != nil {
+		return err
+	}
+	a.display.SetRenderer(renderer)
+
+	if err := a.config.EnsureStorageDir(); err != nil {
+		return fmt.Errorf("failed to create storage directory: %w", err)
+	}
+	return cmd.run(a, args[1:])
+}
+
+func (a *App) usage() {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintln(a.stdout, "Usage: go-todo [global flags] [command] [flags]")
+	fmt.Fprintln(a.stdout, "\nRun without a command to start the full-screen interface. Without a terminal,")
+	fmt.Fprintln(a.stdout, "or with --interface menu, the numbered menu starts instead.")
+	fmt.Fprintln(a.stdout, "\nCommands:")
+	for _, name := range names {
+		fmt.Fprintf(a.stdout, "  %-12s %s\n", name, commands[name].summary)
+	}
+	fmt.Fprintln(a.stdout, "\nRun go-todo -h to list the global flags.")
+}
+
+// flagSet returns a flag set for cmd that reports errors instead of exiting.
+func (a *App) flagSet(cmd string) *flag.FlagSet {
+	fs := flag.NewFlagSet(cmd, flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	fs.Usage = func() {
+		commands[cmd].usageTo(fs.Output())
+		fmt.Fprintln(fs.Output(), "\nFlags:")
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+func (c *command) usageTo(w io.Writer) {
+	fmt.Fprintf(w, "Usage: go-todo %s\n\n%s\n", c.usage, c.summary)
+}
+
+// stringList is a flag.Value collecting every occurrence of a repeatable flag.
+type stringList []string
+
+func (s *stringList) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *stringList) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
+
+// resolveRefs turns todo references given on the command line into IDs. A
+// todo named more than once, e.g. by ID and by row, is listed once.
+func resolveRefs(ts *service.TodoService, refs []string) ([]string, error) {
+	ids := make([]string, 0, len(refs))
+	seen := make(map[string]bool, len(refs))
+	for _, ref := range refs {
+		todo, err := ts.ResolveTodo(ref, nil)
+		if err != nil {
+			var ambiguous *service.AmbiguousRefError
+			if stderrors.As(err, &ambiguous) {
+				matches := make([]string, len(ambiguous.Candidates))
+				for i, c := range ambiguous.Candidates {
+					matches[i] = fmt.Sprintf("%s (%s)", c.ID, c.Task)
+				}
+				return nil, fmt.Errorf("%w; candidates: %s", err, strings.Join(matches, ", "))
+			}
+			return nil, err
+		}
+		if !seen[todo.ID] {
+			seen[todo.ID] = true
+			ids = append(ids, todo.ID)
+		}
+	}
+	return ids, nil
+}
+
+// selectIDs returns the IDs of the todos referred to by refs together with
+// those matching the filter query, if any.
+func selectIDs(ts *service.TodoService, refs []string, query string) ([]string, error) {
+	ids, err := resolveRefs(ts, refs)
+	if err != nil {
+		return nil, err
+	}
+	if query == "" {
+		return ids, nil
+	}
+
+	filter, err := service.ParseFilter(query)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		seen[id] = true
+	}
+	for _, todo := range ts.ListFiltered(filter) {
+		if !seen[todo.ID] {
+			ids = append(ids, todo.ID)
+		}
+	}
+	return ids, nil
+}
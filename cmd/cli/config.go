@@ -0,0 +1,64 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/Ng1n3/go-todo/internal/config"
+)
+
+func init() {
+	register(&command{
+		name:    "config",
+		usage:   "config show",
+		summary: "Show the effective configuration and where each value came from",
+		run:     runConfig,
+	})
+}
+
+// LoadConfig parses the global flags at the start of args, loads the layered
+// configuration and returns it together with the remaining arguments.
+func LoadConfig(args []string) (*config.Config, []string, error) {
+	fs := flag.NewFlagSet("go-todo", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	file := fs.String("config", "", "path of the config file (JSON or TOML)")
+
+	values := make(map[string]*string)
+	for _, s := range config.Describe() {
+		values[s.Key] = fs.String(s.Flag, "", fmt.Sprintf("%s (env %s)", s.Usage, s.Env))
+	}
+
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: go-todo [global flags] [command] [flags]\n\nGlobal flags:\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return nil, nil, err
+	}
+
+	flags := make(map[string]string)
+	fs.Visit(func(f *flag.Flag) {
+		for key, value := range values {
+			if config.FlagName(key) == f.Name {
+				flags[key] = *value
+			}
+		}
+	})
+
+	cfg, err := config.Load(config.LoadOptions{File: *file, Flags: flags})
+	if err != nil {
+		return nil, nil, err
+	}
+	return cfg, fs.Args(), nil
+}
+
+func runConfig(app *App, args []string) error {
+	if len(args) == 0 || args[0] != "show" {
+		commands["config"].usageTo(app.stdout)
+		return fmt.Errorf("unknown config command")
+	}
+
+	app.display.ShowSettings(app.config.Settings())
+	return nil
+}
@@ -0,0 +1,31 @@
+package cli
+
+import (
+	"time"
+
+	"github.com/Ng1n3/go-todo/internal/summary"
+)
+
+func init() {
+	register(&command{
+		name:    "dashboard",
+		usage:   "dashboard",
+		summary: "Show open, done and overdue counts for every todo file",
+		run:     runDashboard,
+	})
+}
+
+func runDashboard(app *App, args []string) error {
+	fs := app.flagSet("dashboard")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	idx, err := summary.Current(app.config)
+	if err != nil {
+		return err
+	}
+
+	app.display.ShowDashboard(idx, time.Now())
+	return nil
+}
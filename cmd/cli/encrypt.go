@@ -0,0 +1,106 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+)
+
+// newPassphraseEnv holds the new passphrase for encrypt and rekey when they
+// run without a terminal.
+const newPassphraseEnv = "GO_TODO_NEW_PASSPHRASE"
+
+func init() {
+	register(&command{
+		name:    "encrypt",
+		usage:   "encrypt <file>",
+		summary: "Encrypt a todo file with a passphrase",
+		run:     runEncrypt,
+	})
+	register(&command{
+		name:    "decrypt",
+		usage:   "decrypt <file>",
+		summary: "Store an encrypted todo file as plain JSON again",
+		run:     runDecrypt,
+	})
+	register(&command{
+		name:    "rekey",
+		usage:   "rekey <file>",
+		summary: "Change the passphrase of an encrypted todo file",
+		run:     runRekey,
+	})
+}
+
+func runEncrypt(app *App, args []string) error {
+	return changePassphrase(app, "encrypt", args, false)
+}
+
+func runRekey(app *App, args []string) error {
+	return changePassphrase(app, "rekey", args, true)
+}
+
+func changePassphrase(app *App, name string, args []string, wantEncrypted bool) error {
+	fs := app.flagSet(name)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("expected exactly one todo file")
+	}
+
+	ts, filename, err := app.openFile(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case wantEncrypted && !ts.Encrypted():
+		return fmt.Errorf("%s is not encrypted; use encrypt", filename)
+	case !wantEncrypted && ts.Encrypted():
+		return fmt.Errorf("%s is already encrypted; use rekey to change its passphrase", filename)
+	}
+
+	passphrase := os.Getenv(newPassphraseEnv)
+	if passphrase == "" {
+		passphrase, err = app.input.ReadNewPassphrase(fmt.Sprintf("New passphrase for %s: ", filename))
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := ts.Encrypt(passphrase); err != nil {
+		return err
+	}
+
+	if wantEncrypted {
+		app.display.ShowSuccess(fmt.Sprintf("Changed the passphrase of %s", filename))
+	} else {
+		app.display.ShowSuccess(fmt.Sprintf("Encrypted %s", filename))
+	}
+	return nil
+}
+
+func runDecrypt(app *App, args []string) error {
+	fs := app.flagSet("decrypt")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("expected exactly one todo file")
+	}
+
+	ts, filename, err := app.openFile(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	if !ts.Encrypted() {
+		return fmt.Errorf("%s is not encrypted", filename)
+	}
+
+	if err := ts.Decrypt(); err != nil {
+		return err
+	}
+	app.display.ShowSuccess(fmt.Sprintf("Decrypted %s", filename))
+	return nil
+}
@@ -0,0 +1,120 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/Ng1n3/go-todo/internal/service"
+	"github.com/Ng1n3/go-todo/internal/stats"
+)
+
+func init() {
+	register(&command{
+		name:    "estimate",
+		usage:   "estimate <file> <todo> <90m|2h|3pt|none>",
+		summary: "Set or clear the estimate of a todo",
+		run:     runEstimate,
+	})
+	register(&command{
+		name:    "estimates",
+		usage:   "estimates [-actual tracked|completion] [-from -8w] [-to today] [-json] [file...]",
+		summary: "Compare estimates with tracked or completion time per label",
+		run:     runEstimates,
+	})
+}
+
+func runEstimate(app *App, args []string) error {
+	fs := app.flagSet("estimate")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 3 {
+		fs.Usage()
+		return fmt.Errorf("expected a todo file, a todo and an estimate")
+	}
+
+	ts, _, err := app.openFile(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	ids, err := resolveRefs(ts, fs.Args()[1:2])
+	if err != nil {
+		return err
+	}
+
+	estimate := fs.Arg(2)
+	if strings.EqualFold(estimate, "none") {
+		estimate = ""
+	}
+	if err := ts.UpdateTodo(ids[0], map[string]any{"estimate": estimate}); err != nil {
+		return err
+	}
+	if err := ts.Save(); err != nil {
+		return err
+	}
+
+	todo, err := ts.GetTodo(ids[0])
+	if err != nil {
+		return err
+	}
+	if todo.Estimate == nil {
+		app.display.ShowSuccess(fmt.Sprintf("Cleared the estimate of %s", todo.ID))
+	} else {
+		app.display.ShowSuccess(fmt.Sprintf("%s is estimated at %s", todo.ID, todo.Estimate))
+	}
+	return nil
+}
+
+func runEstimates(app *App, args []string) error {
+	fs := app.flagSet("estimates")
+	actual := fs.String("actual", "tracked", "compare with tracked time, or with the time from creation to completion")
+	from := fs.String("from", "-8w", "first day of the period, by completion: YYYY-MM-DD, today, or an offset such as -4w")
+	to := fs.String("to", "today", "last day of the period")
+	asJSON := fs.Bool("json", false, "print the report as JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var opts stats.EstimateOptions
+	var err error
+	if opts.Actual, err = stats.ParseActual(*actual); err != nil {
+		return err
+	}
+	if opts.From, err = service.ParseDate(*from); err != nil {
+		return err
+	}
+	if opts.To, err = service.ParseDate(*to); err != nil {
+		return err
+	}
+	if opts.To.Before(opts.From) {
+		return fmt.Errorf("-to %s is before -from %s", *to, *from)
+	}
+
+	names := make([]string, 0, fs.NArg())
+	for _, name := range fs.Args() {
+		filename, err := app.existingFile(name)
+		if err != nil {
+			return err
+		}
+		names = append(names, filename)
+	}
+
+	files, err := service.NewWorkspace(app.config).LoadWithArchives(names)
+	if err != nil {
+		return err
+	}
+
+	report := stats.CompareEstimates(files, opts)
+	if *asJSON {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal report: %w", err)
+		}
+		fmt.Fprintln(app.stdout, string(data))
+		return nil
+	}
+
+	app.display.ShowEstimates(report)
+	return nil
+}
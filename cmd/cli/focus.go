@@ -0,0 +1,142 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/Ng1n3/go-todo/internal/focus"
+	"github.com/Ng1n3/go-todo/internal/service"
+)
+
+func init() {
+	register(&command{
+		name:    "focus",
+		usage:   "focus [-rounds 4] <file> <todo> | focus resume | focus status | focus stop | focus cancel | focus report [-by day|week] [-from -1w] [-to today] [file...]",
+		summary: "Run Pomodoro focus sessions on a todo and report on them",
+		run:     runFocus,
+	})
+}
+
+func runFocus(app *App, args []string) error {
+	workspace := service.NewWorkspace(app.config)
+
+	if len(args) > 0 {
+		switch args[0] {
+		case "report":
+			return runFocusReport(app, args[1:])
+		case "status":
+			current, err := workspace.CurrentFocus()
+			if err != nil {
+				return err
+			}
+			app.display.ShowFocus(current, time.Now())
+			return nil
+		case "resume":
+			return driveFocus(app, workspace)
+		case "stop":
+			stopped, err := workspace.InterruptFocus(time.Now())
+			if err != nil {
+				return err
+			}
+			app.display.ShowSuccess(fmt.Sprintf("Stopped the focus session on %s in %s", stopped.TodoID, stopped.File))
+			return nil
+		case "cancel":
+			cancelled, err := workspace.CancelFocus()
+			if err != nil {
+				return err
+			}
+			app.display.ShowSuccess(fmt.Sprintf("Discarded the focus session on %s in %s", cancelled.TodoID, cancelled.File))
+			return nil
+		}
+	}
+
+	fs := app.flagSet("focus")
+	rounds := fs.Int("rounds", 4, "number of work intervals to run; 0 runs until interrupted")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return fmt.Errorf("expected a todo file and a todo")
+	}
+	if *rounds < 0 {
+		return fmt.Errorf("-rounds must not be negative")
+	}
+
+	ts, _, err := app.openFile(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	ids, err := resolveRefs(ts, fs.Args()[1:])
+	if err != nil {
+		return err
+	}
+	if _, err := workspace.StartFocus(ts, ids[0], *rounds, time.Now()); err != nil {
+		return err
+	}
+	return driveFocus(app, workspace)
+}
+
+// driveFocus runs the focus session in progress until it is done or the
+// user presses Ctrl-C.
+func driveFocus(app *App, workspace *service.Workspace) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	fmt.Fprintln(app.stdout, "Press Ctrl-C to stop the session.")
+	interrupted, err := focus.Run(ctx, workspace, app.stdout)
+	if err != nil {
+		return err
+	}
+	if interrupted {
+		app.display.ShowInfo("Focus session stopped")
+		return nil
+	}
+	app.display.ShowSuccess("Focus session complete")
+	return nil
+}
+
+func runFocusReport(app *App, args []string) error {
+	fs := app.flagSet("focus")
+	by := fs.String("by", "day", "group by day or week")
+	from := fs.String("from", "-1w", "first day of the period: YYYY-MM-DD, today, or an offset such as -4w")
+	to := fs.String("to", "today", "last day of the period")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	opts := focus.Options{}
+	var err error
+	if opts.By, err = focus.ParsePeriod(*by); err != nil {
+		return err
+	}
+	if opts.From, err = service.ParseDate(*from); err != nil {
+		return err
+	}
+	if opts.To, err = service.ParseDate(*to); err != nil {
+		return err
+	}
+	if opts.To.Before(opts.From) {
+		return fmt.Errorf("-to %s is before -from %s", *to, *from)
+	}
+
+	names := make([]string, 0, fs.NArg())
+	for _, name := range fs.Args() {
+		filename, err := app.existingFile(name)
+		if err != nil {
+			return err
+		}
+		names = append(names, filename)
+	}
+
+	files, err := service.NewWorkspace(app.config).LoadWithArchives(names)
+	if err != nil {
+		return err
+	}
+	app.display.ShowFocusReport(focus.Compute(files, opts))
+	return nil
+}
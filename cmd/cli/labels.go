@@ -0,0 +1,184 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Ng1n3/go-todo/internal/service"
+	"github.com/Ng1n3/go-todo/internal/types"
+	"github.com/Ng1n3/go-todo/internal/utils"
+)
+
+func init() {
+	register(&command{
+		name:    "labels",
+		usage:   "labels [list] | labels tree [-filter query] [file...] | labels rename <label> <new> | labels merge <into> <label>... | labels delete [-yes] <label> | labels allow [-clear] <file> [label...]",
+		summary: "List, rename, merge or delete labels across all files, or limit a file's labels",
+		run:     runLabels,
+	})
+}
+
+func runLabels(app *App, args []string) error {
+	workspace := service.NewWorkspace(app.config)
+
+	if len(args) == 0 || (args[0] == "list" && len(args) == 1) {
+		usage, err := workspace.LabelUsage()
+		if err != nil {
+			return err
+		}
+		app.display.ShowLabels(usage)
+		return nil
+	}
+
+	switch {
+	case args[0] == "rename" && len(args) == 3:
+		edits, err := workspace.RenameLabel(args[1], args[2])
+		if err != nil {
+			return err
+		}
+		app.display.ShowLabelEdits(edits)
+		app.display.ShowSuccess(fmt.Sprintf("Renamed %s to %s", args[1], args[2]))
+		return nil
+
+	case args[0] == "merge" && len(args) >= 3:
+		edits, err := workspace.MergeLabels(args[2:], args[1])
+		if err != nil {
+			return err
+		}
+		app.display.ShowLabelEdits(edits)
+		app.display.ShowSuccess(fmt.Sprintf("Merged %s into %s", strings.Join(args[2:], ", "), args[1]))
+		return nil
+
+	case args[0] == "tree":
+		return labelTree(app, workspace, args[1:])
+
+	case args[0] == "delete":
+		return deleteLabel(app, workspace, args[1:])
+
+	case args[0] == "allow":
+		return allowLabels(app, args[1:])
+	}
+
+	commands["labels"].usageTo(app.stdout)
+	return fmt.Errorf("unknown labels command")
+}
+
+func deleteLabel(app *App, workspace *service.Workspace, args []string) error {
+	fs := app.flagSet("labels")
+	yes := fs.Bool("yes", false, "don't ask for confirmation")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("expected a label")
+	}
+	label := fs.Arg(0)
+
+	if !*yes {
+		usage, err := workspace.LabelUsage()
+		if err != nil {
+			return err
+		}
+		count := 0
+		for _, u := range usage {
+			if utils.SameLabel(u.Label, label) {
+				count = u.Open + u.Done
+			}
+		}
+		ok, err := app.input.ReadBool(fmt.Sprintf("Remove %s from %d todos in every file? (y/n): ", label, count))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			app.display.ShowInfo("Nothing changed")
+			return nil
+		}
+	}
+
+	edits, err := workspace.DeleteLabel(label)
+	if err != nil {
+		return err
+	}
+	app.display.ShowLabelEdits(edits)
+	app.display.ShowSuccess(fmt.Sprintf("Deleted %s", label))
+	return nil
+}
+
+func allowLabels(app *App, args []string) error {
+	fs := app.flagSet("labels")
+	clear := fs.Bool("clear", false, "allow any label again")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() < 1 {
+		fs.Usage()
+		return fmt.Errorf("expected a todo file")
+	}
+
+	ts, _, err := app.openFile(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	if fs.NArg() == 1 && !*clear {
+		allowed := ts.AllowedLabels()
+		if len(allowed) == 0 {
+			app.display.ShowInfo(fmt.Sprintf("%s allows any label", ts.File()))
+		} else {
+			app.display.ShowInfo(fmt.Sprintf("%s allows %s", ts.File(), strings.Join(allowed, ", ")))
+		}
+		return nil
+	}
+
+	var labels []string
+	if !*clear {
+		labels = utils.ValidateLabels(strings.Join(fs.Args()[1:], ","))
+	}
+	if err := ts.SetAllowedLabels(labels); err != nil {
+		return err
+	}
+	if err := ts.Save(); err != nil {
+		return err
+	}
+
+	if len(labels) == 0 {
+		app.display.ShowSuccess(fmt.Sprintf("%s allows any label", ts.File()))
+	} else {
+		app.display.ShowSuccess(fmt.Sprintf("%s allows %s", ts.File(), strings.Join(labels, ", ")))
+	}
+	return nil
+}
+
+func labelTree(app *App, workspace *service.Workspace, args []string) error {
+	fs := app.flagSet("labels")
+	query := fs.String("filter", "", "only count the todos matching a filter query")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	filter, err := service.ParseFilter(*query)
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, fs.NArg())
+	for _, name := range fs.Args() {
+		filename, err := app.existingFile(name)
+		if err != nil {
+			return err
+		}
+		names = append(names, filename)
+	}
+	files, err := workspace.Load(names)
+	if err != nil {
+		return err
+	}
+
+	var todos []types.Todo
+	for _, file := range files {
+		todos = append(todos, filter.Apply(file.Todos, time.Now())...)
+	}
+	app.display.ShowLabelTree(service.LabelTree(todos))
+	return nil
+}
@@ -0,0 +1,47 @@
+package cli
+
+import (
+	"time"
+
+	"github.com/Ng1n3/go-todo/internal/service"
+)
+
+func init() {
+	register(&command{
+		name:    "list",
+		usage:   "list [-filter query] [file...]",
+		summary: "List the todos of some or all files, in the format set by --output",
+		run:     runList,
+	})
+}
+
+func runList(app *App, args []string) error {
+	fs := app.flagSet("list")
+	query := fs.String("filter", "", "only list the todos matching a filter query")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	filter, err := service.ParseFilter(*query)
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, fs.NArg())
+	for _, name := range fs.Args() {
+		filename, err := app.existingFile(name)
+		if err != nil {
+			return err
+		}
+		names = append(names, filename)
+	}
+	files, err := service.NewWorkspace(app.config).Load(names)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for i := range files {
+		files[i].Todos = filter.Apply(files[i].Todos, now)
+	}
+	return app.display.ShowFileTodos(files)
+}
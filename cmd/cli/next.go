@@ -0,0 +1,51 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Ng1n3/go-todo/internal/service"
+	"github.com/Ng1n3/go-todo/internal/urgency"
+)
+
+func init() {
+	register(&command{
+		name:    "next",
+		usage:   "next [-n 5] [-filter query] [-blocked] [file...]",
+		summary: "Show the most urgent open todos across files and why",
+		run:     runNext,
+	})
+}
+
+func runNext(app *App, args []string) error {
+	fs := app.flagSet("next")
+	n := fs.Int("n", 5, "number of todos to show; 0 shows all open todos")
+	query := fs.String("filter", "", "only rank the todos matching a filter query")
+	blocked := fs.Bool("blocked", false, "rank blocked todos too")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *n < 0 {
+		return fmt.Errorf("-n must not be negative")
+	}
+	filter, err := service.ParseFilter(*query)
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, fs.NArg())
+	for _, name := range fs.Args() {
+		filename, err := app.existingFile(name)
+		if err != nil {
+			return err
+		}
+		names = append(names, filename)
+	}
+	files, err := service.NewWorkspace(app.config).Load(names)
+	if err != nil {
+		return err
+	}
+
+	app.display.ShowNext(urgency.Next(files, filter, app.config.Urgency, *n, *blocked, time.Now()))
+	return nil
+}
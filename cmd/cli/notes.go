@@ -0,0 +1,190 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/Ng1n3/go-todo/internal/service"
+	"github.com/Ng1n3/go-todo/internal/types"
+	"github.com/Ng1n3/go-todo/internal/ui"
+)
+
+func init() {
+	register(&command{
+		name:    "show",
+		usage:   "show <file> <todo>",
+		summary: "Show a todo with its links and notes",
+		run:     runShow,
+	})
+	register(&command{
+		name:    "notes",
+		usage:   "notes [-set text] <file> <todo>",
+		summary: "Edit the Markdown notes of a todo in $EDITOR",
+		run:     runNotes,
+	})
+	register(&command{
+		name:    "link",
+		usage:   "link add [-kind url|file|todo] [-title text] <file> <todo> <target> | link rm <file> <todo> <n>",
+		summary: "Attach a URL, file path or other todo to a todo, or remove a link",
+		run:     runLink,
+	})
+}
+
+// openTodo opens the todo file named by args[0] and resolves the todo
+// reference in args[1].
+func openTodo(app *App, args []string) (*service.TodoService, types.Todo, error) {
+	ts, _, err := app.openFile(args[0])
+	if err != nil {
+		return nil, types.Todo{}, err
+	}
+	ids, err := resolveRefs(ts, args[1:2])
+	if err != nil {
+		return nil, types.Todo{}, err
+	}
+	todo, err := ts.GetTodo(ids[0])
+	if err != nil {
+		return nil, types.Todo{}, err
+	}
+	return ts, todo, nil
+}
+
+func runShow(app *App, args []string) error {
+	fs := app.flagSet("show")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return fmt.Errorf("expected a todo file and a todo")
+	}
+
+	_, todo, err := openTodo(app, fs.Args())
+	if err != nil {
+		return err
+	}
+	app.display.ShowTodoDetail(todo)
+	return nil
+}
+
+func runNotes(app *App, args []string) error {
+	fs := app.flagSet("notes")
+	var set optionalString
+	fs.Var(&set, "set", "replace the notes with text instead of opening the editor; -set '' clears them")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return fmt.Errorf("expected a todo file and a todo")
+	}
+
+	ts, todo, err := openTodo(app, fs.Args())
+	if err != nil {
+		return err
+	}
+
+	notes := set.value
+	if !set.set {
+		if notes, err = ui.EditText(todo.Notes, todo.ID+".md"); err != nil {
+			return err
+		}
+	}
+	if err := ts.SetNotes(todo.ID, notes); err != nil {
+		return err
+	}
+	if err := ts.Save(); err != nil {
+		return err
+	}
+	app.display.ShowSuccess(fmt.Sprintf("Saved the notes of %s", todo.ID))
+	return nil
+}
+
+func runLink(app *App, args []string) error {
+	if len(args) == 0 {
+		commands["link"].usageTo(app.stdout)
+		return fmt.Errorf("expected add or rm")
+	}
+
+	switch args[0] {
+	case "add":
+		fs := app.flagSet("link")
+		kind := fs.String("kind", "", "url, file or todo (default: guessed from the target)")
+		title := fs.String("title", "", "title to show for the link")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		if fs.NArg() != 3 {
+			fs.Usage()
+			return fmt.Errorf("expected a todo file, a todo and a target")
+		}
+
+		ts, todo, err := openTodo(app, fs.Args())
+		if err != nil {
+			return err
+		}
+		link := types.Link{Kind: types.LinkKind(*kind), Target: fs.Arg(2), Title: *title}
+		if link.Kind == types.LinkTodo {
+			// Other todos may be referred to like on the command line.
+			ids, err := resolveRefs(ts, fs.Args()[2:])
+			if err != nil {
+				return err
+			}
+			link.Target = ids[0]
+		}
+		if err := ts.AddLink(todo.ID, link); err != nil {
+			return err
+		}
+		if err := ts.Save(); err != nil {
+			return err
+		}
+		app.display.ShowSuccess(fmt.Sprintf("Linked %s to %s", todo.ID, link.Target))
+		return nil
+
+	case "rm":
+		fs := app.flagSet("link")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		if fs.NArg() != 3 {
+			fs.Usage()
+			return fmt.Errorf("expected a todo file, a todo and a link number")
+		}
+		n, err := strconv.Atoi(fs.Arg(2))
+		if err != nil {
+			return fmt.Errorf("invalid link number %q", fs.Arg(2))
+		}
+
+		ts, todo, err := openTodo(app, fs.Args())
+		if err != nil {
+			return err
+		}
+		link, err := ts.RemoveLink(todo.ID, n)
+		if err != nil {
+			return err
+		}
+		if err := ts.Save(); err != nil {
+			return err
+		}
+		app.display.ShowSuccess(fmt.Sprintf("Removed the link from %s to %s", todo.ID, link.Target))
+		return nil
+	}
+
+	commands["link"].usageTo(app.stdout)
+	return fmt.Errorf("unknown link command %q", args[0])
+}
+
+// optionalString is a string flag that remembers whether it was given, so
+// an empty value can be told apart from a missing flag.
+type optionalString struct {
+	value string
+	set   bool
+}
+
+func (s *optionalString) String() string {
+	return s.value
+}
+
+func (s *optionalString) Set(value string) error {
+	s.value, s.set = value, true
+	return nil
+}
@@ -0,0 +1,83 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/Ng1n3/go-todo/internal/remind"
+	"github.com/Ng1n3/go-todo/internal/utils"
+)
+
+func init() {
+	register(&command{
+		name:    "remind",
+		usage:   "remind [-offsets 1d,1h] [-interval 1m] [-sink spec]... [-once]",
+		summary: "Watch all todo files and send reminders before todos are due",
+		run:     runRemind,
+	})
+}
+
+func runRemind(app *App, args []string) error {
+	fs := app.flagSet("remind")
+	offsetsFlag := fs.String("offsets", "1d,1h", "comma-separated offsets before the deadline (e.g. 2d,3h,15m)")
+	interval := fs.Duration("interval", time.Minute, "how often to scan the storage directory")
+	once := fs.Bool("once", false, "check once and exit instead of running as a daemon")
+	var sinkSpecs stringList
+	fs.Var(&sinkSpecs, "sink", "notification sink: terminal, log:<path> or exec:<command> (repeatable)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var offsets []time.Duration
+	for _, part := range strings.Split(*offsetsFlag, ",") {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+		offset, err := utils.ParseDuration(part)
+		if err != nil {
+			return fmt.Errorf("invalid offset %q: %w", part, err)
+		}
+		offsets = append(offsets, offset)
+	}
+
+	if len(sinkSpecs) == 0 {
+		sinkSpecs = stringList{"terminal"}
+	}
+	var sinks []remind.Sink
+	for _, spec := range sinkSpecs {
+		sink, err := remind.ParseSink(spec, app.config.FileMode)
+		if err != nil {
+			return err
+		}
+		sinks = append(sinks, sink)
+	}
+
+	daemon, err := remind.NewDaemon(app.config, remind.Options{
+		Offsets:  offsets,
+		Interval: *interval,
+		Sinks:    sinks,
+	})
+	if err != nil {
+		return err
+	}
+
+	if *once {
+		sent, err := daemon.Check()
+		if err != nil {
+			return err
+		}
+		app.display.ShowInfo(fmt.Sprintf("%d reminder(s) sent", sent))
+		return nil
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	app.display.ShowInfo(fmt.Sprintf("Watching %s for due todos (Ctrl+C to stop)", app.config.StorageDir))
+	return daemon.Run(ctx, app.display.ShowError)
+}
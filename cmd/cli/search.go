@@ -0,0 +1,61 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Ng1n3/go-todo/internal/search"
+)
+
+func init() {
+	register(&command{
+		name:    "search",
+		usage:   "search [-limit n] [-rebuild] <query>",
+		summary: "Search task text and labels across all todo files",
+		run:     runSearch,
+	})
+}
+
+func runSearch(app *App, args []string) error {
+	fs := app.flagSet("search")
+	limit := fs.Int("limit", 20, "maximum number of results (0 for all)")
+	rebuild := fs.Bool("rebuild", false, "rebuild the index from scratch")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	query := strings.Join(fs.Args(), " ")
+	if strings.TrimSpace(query) == "" && !*rebuild {
+		fs.Usage()
+		return fmt.Errorf("a search query is required")
+	}
+
+	idx, err := search.Open(app.config)
+	if err != nil {
+		return err
+	}
+
+	changed := true
+	if *rebuild {
+		err = idx.Rebuild()
+	} else {
+		changed, err = idx.Refresh()
+	}
+	if err != nil {
+		return err
+	}
+
+	if changed {
+		if err := idx.Save(); err != nil {
+			return err
+		}
+	}
+
+	if strings.TrimSpace(query) == "" {
+		app.display.ShowSuccess(fmt.Sprintf("Indexed %d todos", idx.Count()))
+		return nil
+	}
+
+	app.display.ShowSearchResults(idx.Search(query, *limit))
+	return nil
+}
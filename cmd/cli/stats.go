@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Ng1n3/go-todo/internal/service"
+	"github.com/Ng1n3/go-todo/internal/stats"
+)
+
+func init() {
+	register(&command{
+		name:    "stats",
+		usage:   "stats [-from -8w] [-to today] [-json] [file...]",
+		summary: "Show created vs completed per week, completion times, overdue rate and more",
+		run:     runStats,
+	})
+}
+
+func runStats(app *App, args []string) error {
+	fs := app.flagSet("stats")
+	from := fs.String("from", "-8w", "first day of the period: YYYY-MM-DD, today, or an offset such as -4w")
+	to := fs.String("to", "today", "last day of the period")
+	asJSON := fs.Bool("json", false, "print the report as JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	opts := stats.Options{Now: time.Now()}
+	var err error
+	if opts.From, err = service.ParseDate(*from); err != nil {
+		return err
+	}
+	if opts.To, err = service.ParseDate(*to); err != nil {
+		return err
+	}
+	if opts.To.Before(opts.From) {
+		return fmt.Errorf("-to %s is before -from %s", *to, *from)
+	}
+
+	names := make([]string, 0, fs.NArg())
+	for _, name := range fs.Args() {
+		filename, err := app.existingFile(name)
+		if err != nil {
+			return err
+		}
+		names = append(names, filename)
+	}
+
+	files, err := service.NewWorkspace(app.config).LoadWithArchives(names)
+	if err != nil {
+		return err
+	}
+
+	report := stats.Compute(files, opts)
+	if *asJSON {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal report: %w", err)
+		}
+		fmt.Fprintln(app.stdout, string(data))
+		return nil
+	}
+
+	app.display.ShowStats(report)
+	return nil
+}
@@ -0,0 +1,138 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/Ng1n3/go-todo/internal/service"
+	"github.com/Ng1n3/go-todo/internal/types"
+)
+
+func init() {
+	register(&command{
+		name:    "status",
+		usage:   "status <file> <todo> <status>",
+		summary: "Move a todo to another status of the file's workflow",
+		run:     runStatus,
+	})
+	register(&command{
+		name:    "board",
+		usage:   "board [-filter query] <file>",
+		summary: "Show the todos of a file as a kanban board, one column per status",
+		run:     runBoard,
+	})
+	register(&command{
+		name:    "workflow",
+		usage:   "workflow show <file> | workflow set <file> <workflow.json> | workflow reset <file>",
+		summary: "Show or change the status workflow of a todo file",
+		run:     runWorkflow,
+	})
+}
+
+func runStatus(app *App, args []string) error {
+	fs := app.flagSet("status")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 3 {
+		fs.Usage()
+		return fmt.Errorf("expected a todo file, a todo and a status")
+	}
+
+	ts, _, err := app.openFile(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	ids, err := resolveRefs(ts, fs.Args()[1:2])
+	if err != nil {
+		return err
+	}
+
+	if err := ts.SetStatus(ids[0], types.ParseStatus(fs.Arg(2))); err != nil {
+		return err
+	}
+	if err := ts.Save(); err != nil {
+		return err
+	}
+
+	todo, err := ts.GetTodo(ids[0])
+	if err != nil {
+		return err
+	}
+	app.display.ShowSuccess(fmt.Sprintf("%s is now %s", todo.ID, todo.Status.Label()))
+	return nil
+}
+
+func runBoard(app *App, args []string) error {
+	fs := app.flagSet("board")
+	query := fs.String("filter", "", "only show todos matching a filter query")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("expected exactly one todo file")
+	}
+
+	ts, _, err := app.openFile(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	filter, err := service.ParseFilter(*query)
+	if err != nil {
+		return err
+	}
+
+	app.display.ShowBoard(ts.Workflow(), ts.ListFiltered(filter))
+	return nil
+}
+
+func runWorkflow(app *App, args []string) error {
+	usage := func() error {
+		commands["workflow"].usageTo(app.stdout)
+		return fmt.Errorf("unknown workflow command")
+	}
+	if len(args) < 2 {
+		return usage()
+	}
+
+	ts, filename, err := app.openFile(args[1])
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case args[0] == "show" && len(args) == 2:
+		app.display.ShowWorkflow(ts.Workflow())
+		return nil
+
+	case args[0] == "set" && len(args) == 3:
+		data, err := os.ReadFile(args[2])
+		if err != nil {
+			return fmt.Errorf("failed to read workflow: %w", err)
+		}
+		var workflow types.Workflow
+		if err := json.Unmarshal(data, &workflow); err != nil {
+			return fmt.Errorf("failed to parse workflow %s: %w", args[2], err)
+		}
+		if err := ts.SetWorkflow(&workflow); err != nil {
+			return err
+		}
+
+	case args[0] == "reset" && len(args) == 2:
+		if err := ts.SetWorkflow(nil); err != nil {
+			return err
+		}
+
+	default:
+		return usage()
+	}
+
+	if err := ts.Save(); err != nil {
+		return err
+	}
+	app.display.ShowSuccess(fmt.Sprintf("Updated the workflow of %s", filename))
+	app.display.ShowWorkflow(ts.Workflow())
+	return nil
+}
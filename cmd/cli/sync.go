@@ -0,0 +1,47 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Ng1n3/go-todo/internal/gitsync"
+)
+
+func init() {
+	register(&command{
+		name:    "sync",
+		usage:   "sync [-remote name] [url-or-path]",
+		summary: "Commit, pull and push the storage directory with a git remote",
+		run:     runSync,
+	})
+}
+
+func runSync(app *App, args []string) error {
+	fs := app.flagSet("sync")
+	remote := fs.String("remote", app.config.GitRemote, "name of the git remote")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 1 {
+		fs.Usage()
+		return fmt.Errorf("expected at most one remote URL or path")
+	}
+
+	result, err := gitsync.Open(app.config.StorageDir).Sync(*remote, fs.Arg(0), app.config)
+	if err != nil {
+		return err
+	}
+
+	if result.Committed {
+		app.display.ShowInfo("Committed local changes")
+	}
+	if len(result.Resolved) > 0 {
+		app.display.ShowInfo(fmt.Sprintf("Merged conflicting todos in %s", strings.Join(result.Resolved, ", ")))
+	}
+	if result.Pushed {
+		app.display.ShowSuccess(fmt.Sprintf("Synced with %s", *remote))
+	} else {
+		app.display.ShowInfo("Nothing to sync yet")
+	}
+	return nil
+}
@@ -0,0 +1,184 @@
+package cli
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Ng1n3/go-todo/internal/errors"
+	"github.com/Ng1n3/go-todo/internal/service"
+	"github.com/Ng1n3/go-todo/internal/types"
+	"github.com/Ng1n3/go-todo/internal/ui"
+)
+
+func init() {
+	register(&command{
+		name:    "template",
+		usage:   "template [list] | template show|edit|rm <name> | template save <name> <template.json> | template apply [-start today] [-var name=value]... <name> <file>",
+		summary: "Manage todo templates and create their todos in a file",
+		run:     runTemplate,
+	})
+}
+
+// templateSkeleton is what `template edit` starts a new template from.
+var templateSkeleton = types.Template{
+	Description: "Release checklist",
+	Variables:   []types.TemplateVariable{{Name: "version", Prompt: "Version to release"}},
+	Todos: []types.TemplateTodo{
+		{Task: "Freeze {{version}}", Due: "+0d", Priority: types.High, Labels: []string{"release"}},
+		{Task: "Publish {{version}}", Due: "+3d", Labels: []string{"release"}, Checklist: []string{"Tag {{version}}", "Write release notes"}},
+	},
+}
+
+func runTemplate(app *App, args []string) error {
+	workspace := service.NewWorkspace(app.config)
+
+	if len(args) == 0 || (args[0] == "list" && len(args) == 1) {
+		templates, err := workspace.Templates()
+		if err != nil {
+			return err
+		}
+		app.display.ShowTemplates(templates)
+		return nil
+	}
+
+	switch {
+	case args[0] == "show" && len(args) == 2:
+		t, err := workspace.Template(args[1])
+		if err != nil {
+			return err
+		}
+		app.display.ShowTemplate(t)
+		return nil
+
+	case args[0] == "edit" && len(args) == 2:
+		return editTemplate(app, workspace, args[1])
+
+	case args[0] == "save" && len(args) == 3:
+		data, err := os.ReadFile(args[2])
+		if err != nil {
+			return fmt.Errorf("failed to read template: %w", err)
+		}
+		var t types.Template
+		if err := json.Unmarshal(data, &t); err != nil {
+			return fmt.Errorf("failed to parse template %s: %w", args[2], err)
+		}
+		t.Name = args[1]
+		if err := workspace.SaveTemplate(&t); err != nil {
+			return err
+		}
+		app.display.ShowSuccess(fmt.Sprintf("Saved template %s with %d todos", t.Name, len(t.Todos)))
+		return nil
+
+	case args[0] == "rm" && len(args) == 2:
+		if err := workspace.DeleteTemplate(args[1]); err != nil {
+			return err
+		}
+		app.display.ShowSuccess(fmt.Sprintf("Deleted template %s", args[1]))
+		return nil
+
+	case args[0] == "apply":
+		return applyTemplate(app, workspace, args[1:])
+	}
+
+	commands["template"].usageTo(app.stdout)
+	return fmt.Errorf("unknown template command")
+}
+
+// editTemplate opens a template as JSON in the editor, starting from an
+// example for a new template, and saves the result.
+func editTemplate(app *App, workspace *service.Workspace, name string) error {
+	t, err := workspace.Template(name)
+	if stderrors.Is(err, errors.ErrTemplateNotFound) {
+		skeleton := templateSkeleton
+		t, err = &skeleton, nil
+	}
+	if err != nil {
+		return err
+	}
+
+	// The name comes from the command line; one typed in the editor is
+	// ignored.
+	t.Name = name
+	data, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal template: %w", err)
+	}
+	edited, err := ui.EditText(string(data)+"\n", "template.json")
+	if err != nil {
+		return err
+	}
+
+	var updated types.Template
+	if err := json.Unmarshal([]byte(edited), &updated); err != nil {
+		return fmt.Errorf("%w: %w", errors.ErrInvalidTemplate, err)
+	}
+	updated.Name = name
+	if err := workspace.SaveTemplate(&updated); err != nil {
+		return err
+	}
+	app.display.ShowSuccess(fmt.Sprintf("Saved template %s with %d todos", updated.Name, len(updated.Todos)))
+	return nil
+}
+
+func applyTemplate(app *App, workspace *service.Workspace, args []string) error {
+	fs := app.flagSet("template")
+	start := fs.String("start", "today", "day the due dates are relative to: YYYY-MM-DD, today, or an offset such as +1w")
+	var vars stringList
+	fs.Var(&vars, "var", "value of a template variable as name=value; repeatable")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return fmt.Errorf("expected a template and a todo file")
+	}
+
+	startDay, err := service.ParseDate(*start)
+	if err != nil {
+		return err
+	}
+	values := make(map[string]string)
+	for _, v := range vars {
+		name, value, ok := strings.Cut(v, "=")
+		if !ok || strings.TrimSpace(name) == "" {
+			return fmt.Errorf("%w: -var %q, use name=value", errors.ErrInvalidInput, v)
+		}
+		values[strings.TrimSpace(name)] = value
+	}
+
+	t, err := workspace.Template(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	// Ask for the variables that weren't given and have no default.
+	for _, v := range t.Placeholders() {
+		if strings.TrimSpace(values[v.Name]) != "" || v.Default != "" {
+			continue
+		}
+		prompt := v.Prompt
+		if prompt == "" {
+			prompt = v.Name
+		}
+		if values[v.Name], err = app.input.ReadString(prompt + ": "); err != nil {
+			return fmt.Errorf("%w: %s", errors.ErrMissingVariable, v.Name)
+		}
+	}
+
+	ts, filename, err := app.openOrCreateFile(fs.Arg(1))
+	if err != nil {
+		return err
+	}
+	created, err := ts.ApplyTemplate(t, values, startDay)
+	if err != nil {
+		return err
+	}
+
+	if err := app.display.ShowFileTodos([]service.FileTodos{{File: filename, Todos: created}}); err != nil {
+		return fmt.Errorf("created %d todos in %s but failed to list them: %w", len(created), filename, err)
+	}
+	app.display.ShowSuccess(fmt.Sprintf("Created %d todos from %s in %s", len(created), t.Name, filename))
+	return nil
+}
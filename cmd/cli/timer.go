@@ -0,0 +1,208 @@
+package cli
+
+import (
+	stderrors "errors"
+	"fmt"
+	"time"
+
+	"github.com/Ng1n3/go-todo/internal/errors"
+	"github.com/Ng1n3/go-todo/internal/service"
+	"github.com/Ng1n3/go-todo/internal/timesheet"
+	"github.com/Ng1n3/go-todo/internal/types"
+	"github.com/Ng1n3/go-todo/internal/utils"
+)
+
+func init() {
+	register(&command{
+		name:    "timer",
+		usage:   "timer [status] | timer start <file> <todo> | timer stop [-note text] | timer cancel",
+		summary: "Time a todo; only one timer runs at a time and it survives restarts",
+		run:     runTimer,
+	})
+	register(&command{
+		name:    "logtime",
+		usage:   "logtime [-start time] [-note text] <file> <todo> <duration>",
+		summary: "Log time spent on a todo, e.g. 1h30m",
+		run:     runLogTime,
+	})
+	register(&command{
+		name:    "timesheet",
+		usage:   "timesheet [-by todo|label|file|day] [-from -1w] [-to today] [-csv] [file...]",
+		summary: "Total the time logged on todos, as a table or CSV",
+		run:     runTimesheet,
+	})
+}
+
+func runTimer(app *App, args []string) error {
+	workspace := service.NewWorkspace(app.config)
+	now := time.Now()
+
+	if len(args) == 0 || (args[0] == "status" && len(args) == 1) {
+		timer, err := workspace.RunningTimer()
+		if err != nil {
+			return err
+		}
+		app.display.ShowTimer(timer, now)
+		return nil
+	}
+
+	switch args[0] {
+	case "start":
+		if len(args) != 3 {
+			break
+		}
+		ts, _, err := app.openFile(args[1])
+		if err != nil {
+			return err
+		}
+		ids, err := resolveRefs(ts, args[2:])
+		if err != nil {
+			return err
+		}
+		timer, err := workspace.StartTimer(ts, ids[0], now)
+		if err != nil {
+			return err
+		}
+		app.display.ShowSuccess(fmt.Sprintf("Started timing %s in %s", timer.TodoID, timer.File))
+		return nil
+
+	case "stop":
+		fs := app.flagSet("timer")
+		note := fs.String("note", "", "note to keep with the time entry")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		if fs.NArg() != 0 {
+			break
+		}
+		timer, entry, err := workspace.StopTimer(*note, now)
+		if stderrors.Is(err, errors.ErrTodoNotFound) || stderrors.Is(err, errors.ErrAmbiguousTodo) {
+			return fmt.Errorf("%w (the timer has been running for %s)", err, timer.Elapsed(now).Round(time.Second))
+		}
+		if err != nil {
+			return err
+		}
+		app.display.ShowSuccess(fmt.Sprintf("Logged %s on %s in %s", entry.Duration().Round(time.Second), timer.TodoID, timer.File))
+		return nil
+
+	case "cancel":
+		if len(args) != 1 {
+			break
+		}
+		timer, err := workspace.CancelTimer()
+		if err != nil {
+			return err
+		}
+		app.display.ShowSuccess(fmt.Sprintf("Discarded %s timed on %s in %s",
+			timer.Elapsed(now).Round(time.Second), timer.TodoID, timer.File))
+		return nil
+	}
+
+	commands["timer"].usageTo(app.stdout)
+	return fmt.Errorf("unknown timer command")
+}
+
+func runLogTime(app *App, args []string) error {
+	fs := app.flagSet("logtime")
+	start := fs.String("start", "", "when the work started: HH:MM today, YYYY-MM-DD or \"YYYY-MM-DD HH:MM\" (default: duration ago)")
+	note := fs.String("note", "", "note to keep with the time entry")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 3 {
+		fs.Usage()
+		return fmt.Errorf("expected a todo file, a todo and a duration")
+	}
+
+	duration, err := utils.ParseDuration(fs.Arg(2))
+	if err != nil || duration <= 0 {
+		return fmt.Errorf("%w: %q", errors.ErrInvalidDuration, fs.Arg(2))
+	}
+
+	now := time.Now()
+	entry := types.TimeEntry{Start: now.Add(-duration), End: now, Note: *note}
+	if *start != "" {
+		if entry.Start, err = parseStart(*start, now); err != nil {
+			return err
+		}
+		entry.End = entry.Start.Add(duration)
+	}
+
+	ts, _, err := app.openFile(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	ids, err := resolveRefs(ts, fs.Args()[1:2])
+	if err != nil {
+		return err
+	}
+	if err := ts.LogTime(ids[0], entry); err != nil {
+		return err
+	}
+	if err := ts.Save(); err != nil {
+		return err
+	}
+
+	app.display.ShowSuccess(fmt.Sprintf("Logged %s on %s", duration, ids[0]))
+	return nil
+}
+
+// parseStart parses the start of a manual time entry in local time.
+func parseStart(value string, now time.Time) (time.Time, error) {
+	if t, err := time.ParseInLocation("15:04", value, time.Local); err == nil {
+		return time.Date(now.Year(), now.Month(), now.Day(), t.Hour(), t.Minute(), 0, 0, time.Local), nil
+	}
+	for _, layout := range []string{"2006-01-02 15:04", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%w: %q, use HH:MM, YYYY-MM-DD or \"YYYY-MM-DD HH:MM\"", errors.ErrInvalidDateFormat, value)
+}
+
+func runTimesheet(app *App, args []string) error {
+	fs := app.flagSet("timesheet")
+	by := fs.String("by", "todo", "group by todo, label, file or day")
+	from := fs.String("from", "-1w", "first day of the period: YYYY-MM-DD, today, or an offset such as -4w")
+	to := fs.String("to", "today", "last day of the period")
+	asCSV := fs.Bool("csv", false, "print the report as CSV")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	opts := timesheet.Options{}
+	var err error
+	if opts.By, err = timesheet.ParseGrouping(*by); err != nil {
+		return err
+	}
+	if opts.From, err = service.ParseDate(*from); err != nil {
+		return err
+	}
+	if opts.To, err = service.ParseDate(*to); err != nil {
+		return err
+	}
+	if opts.To.Before(opts.From) {
+		return fmt.Errorf("-to %s is before -from %s", *to, *from)
+	}
+
+	names := make([]string, 0, fs.NArg())
+	for _, name := range fs.Args() {
+		filename, err := app.existingFile(name)
+		if err != nil {
+			return err
+		}
+		names = append(names, filename)
+	}
+
+	files, err := service.NewWorkspace(app.config).LoadWithArchives(names)
+	if err != nil {
+		return err
+	}
+
+	report := timesheet.Compute(files, opts)
+	if *asCSV {
+		return report.WriteCSV(app.stdout)
+	}
+	app.display.ShowTimesheet(report)
+	return nil
+}
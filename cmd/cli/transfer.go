@@ -0,0 +1,75 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/Ng1n3/go-todo/internal/service"
+)
+
+func init() {
+	register(&command{
+		name:    "move",
+		usage:   "move [-filter query] [-on-conflict rename|skip|overwrite] <from> <to> [todo...]",
+		summary: "Move todos to another todo file, keeping their IDs and timestamps",
+		run: func(app *App, args []string) error {
+			return runTransfer(app, "move", args)
+		},
+	})
+	register(&command{
+		name:    "copy",
+		usage:   "copy [-filter query] [-on-conflict rename|skip|overwrite] <from> <to> [todo...]",
+		summary: "Copy todos to another todo file, keeping their IDs and timestamps",
+		run: func(app *App, args []string) error {
+			return runTransfer(app, "copy", args)
+		},
+	})
+}
+
+func runTransfer(app *App, name string, args []string) error {
+	fs := app.flagSet(name)
+	query := fs.String("filter", "", "select todos with a filter query instead of listing them")
+	onConflict := fs.String("on-conflict", string(service.ConflictRename), "what to do when the ID exists in the target: rename, skip or overwrite")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() < 2 || (fs.NArg() == 2 && *query == "") {
+		fs.Usage()
+		return fmt.Errorf("expected a source file, a target file and todos or a -filter")
+	}
+
+	policy, err := service.ParseConflictPolicy(*onConflict)
+	if err != nil {
+		return err
+	}
+
+	src, _, err := app.openFile(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	dst, _, err := app.openOrCreateFile(fs.Arg(1))
+	if err != nil {
+		return err
+	}
+
+	ids, err := selectIDs(src, fs.Args()[2:], *query)
+	if err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		app.display.ShowInfo("No todos match")
+		return nil
+	}
+
+	transfer := service.CopyTodos
+	if name == "move" {
+		transfer = service.MoveTodos
+	}
+
+	// The todos have been transferred when results come back with an error.
+	results, err := transfer(src, dst, ids, policy)
+	if results == nil && err != nil {
+		return err
+	}
+	app.display.ShowTransfers(name, src.File(), dst.File(), results)
+	return err
+}
@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/Ng1n3/go-todo/internal/service"
+)
+
+func init() {
+	register(&command{
+		name:    "trash",
+		usage:   "trash [list | restore <id>... | empty]",
+		summary: "List, restore or permanently delete deleted todos and todo files",
+		run:     runTrash,
+	})
+}
+
+func runTrash(app *App, args []string) error {
+	workspace := service.NewWorkspace(app.config)
+
+	sub := "list"
+	if len(args) > 0 {
+		sub, args = args[0], args[1:]
+	}
+
+	switch sub {
+	case "list":
+		items, err := workspace.Trash()
+		if err != nil {
+			return err
+		}
+		app.display.ShowTrash(items, app.config.TrashRetention)
+		return nil
+
+	case "restore":
+		if len(args) == 0 {
+			commands["trash"].usageTo(app.stdout)
+			return fmt.Errorf("expected the id of at least one trash item")
+		}
+		for _, id := range args {
+			item, todo, err := workspace.RestoreTrash(id)
+			if err != nil {
+				return fmt.Errorf("failed to restore %s: %w", id, err)
+			}
+			if todo.ID != "" {
+				app.display.ShowSuccess(fmt.Sprintf("Restored todo %s to %s", todo.ID, item.File))
+			} else {
+				app.display.ShowSuccess(fmt.Sprintf("Restored %s", item.File))
+			}
+		}
+		return nil
+
+	case "empty":
+		n, err := workspace.EmptyTrash()
+		if err != nil {
+			return err
+		}
+		app.display.ShowSuccess(fmt.Sprintf("Deleted %d item(s) for good", n))
+		return nil
+
+	default:
+		commands["trash"].usageTo(app.stdout)
+		return fmt.Errorf("unknown trash command %q", sub)
+	}
+}
@@ -1,10 +1,44 @@
 package main
 
 import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/Ng1n3/go-todo/cmd/cli"
 	"github.com/Ng1n3/go-todo/cmd/menu"
+	"github.com/Ng1n3/go-todo/cmd/tui"
+	"github.com/Ng1n3/go-todo/internal/config"
 )
 
 func main() {
-	controller := menu.NewMenuController()
+	cfg, args, err := cli.LoadConfig(os.Args[1:])
+	if err == flag.ErrHelp {
+		return
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(2)
+	}
+
+	if len(args) > 0 {
+		if err := cli.NewApp(cfg).Run(args); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// The full-screen interface needs a terminal; pipes and scripts get the
+	// menu.
+	if cfg.Interface == config.InterfaceTUI && tui.Supported() {
+		if err := tui.Run(cfg); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	controller := menu.NewMenuController(cfg)
 	controller.Start()
 }
@@ -0,0 +1,59 @@
+package menu
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Ng1n3/go-todo/internal/service"
+)
+
+func (mc *MenuController) showAgenda() {
+	view, err := mc.input.ReadChoice("\n1.) Agenda\n2.) Month calendar\n3.) back\nChoice: ", []string{"1", "2", "3"})
+	if err != nil {
+		mc.display.ShowError(err)
+		return
+	}
+	if view == "3" {
+		return
+	}
+
+	query, err := mc.input.ReadString("Filter (e.g. label:work priority:high, empty for all): ")
+	if err != nil {
+		mc.display.ShowError(err)
+		return
+	}
+	filter, err := service.ParseFilter(query)
+	if err != nil {
+		mc.display.ShowError(err)
+		return
+	}
+
+	now := time.Now()
+	month := now
+	if view == "2" {
+		input, err := mc.input.ReadString("Month (YYYY-MM, empty for this month): ")
+		if err != nil {
+			mc.display.ShowError(err)
+			return
+		}
+		if strings.TrimSpace(input) != "" {
+			if month, err = time.Parse("2006-01", strings.TrimSpace(input)); err != nil {
+				mc.display.ShowError(fmt.Errorf("invalid month %q: use YYYY-MM", input))
+				return
+			}
+		}
+	}
+
+	files, err := service.NewWorkspace(mc.config).LoadAll()
+	if err != nil {
+		mc.display.ShowError(err)
+		return
+	}
+
+	if view == "1" {
+		mc.display.ShowAgenda(service.Agenda(files, filter, now))
+	} else {
+		mc.display.ShowCalendar(service.Calendar(files, filter, month, now), now)
+	}
+}
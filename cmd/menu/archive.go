@@ -0,0 +1,99 @@
+package menu
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Ng1n3/go-todo/internal/service"
+	"github.com/Ng1n3/go-todo/internal/utils"
+)
+
+func (mc *MenuController) archiveMenu() {
+	choice, err := mc.input.ReadChoice("\n1.) Archive a Todo\n2.) Archive completed Todos\n3.) List archived Todos\n4.) Restore an archived Todo\n5.) back\nChoice: ", []string{"1", "2", "3", "4", "5"})
+	if err != nil {
+		mc.display.ShowError(err)
+		return
+	}
+
+	switch choice {
+	case "1":
+		mc.showTodos(mc.todoService.ListTodos())
+		todo, err := mc.selectTodo("Enter the id, row number or task of the todo to archive: ")
+		if err != nil {
+			mc.display.ShowError(err)
+			return
+		}
+		results, err := mc.todoService.Archive([]string{todo.ID})
+		mc.showArchived(results, err)
+	case "2":
+		input, err := mc.input.ReadString("Archive todos completed at least how long ago? (e.g. 7d, 0 for all): ")
+		if err != nil {
+			mc.display.ShowError(err)
+			return
+		}
+		age, err := utils.ParseDuration(input)
+		if err != nil {
+			mc.display.ShowError(err)
+			return
+		}
+		results, err := mc.todoService.ArchiveCompleted(age, time.Now())
+		mc.showArchived(results, err)
+	case "3":
+		archive, err := mc.todoService.OpenArchive()
+		if err != nil {
+			mc.display.ShowError(err)
+			return
+		}
+		mc.showTodos(archive.ListTodos())
+	case "4":
+		mc.restoreArchived()
+	case "5":
+		return
+	}
+}
+
+func (mc *MenuController) showArchived(results []service.TransferResult, err error) {
+	if err != nil {
+		mc.display.ShowError(fmt.Errorf("failed to archive todos: %w", err))
+		if results == nil {
+			return
+		}
+	}
+	if len(results) == 0 {
+		mc.display.ShowInfo("Nothing to archive")
+		return
+	}
+	mc.lastShown = nil
+	mc.display.ShowSuccess(fmt.Sprintf("Archived %d todo(s)", len(results)))
+}
+
+func (mc *MenuController) restoreArchived() {
+	archive, err := mc.todoService.OpenArchive()
+	if err != nil {
+		mc.display.ShowError(err)
+		return
+	}
+
+	todos := archive.ListTodos()
+	if len(todos) == 0 {
+		mc.display.ShowInfo("The archive is empty")
+		return
+	}
+	mc.showTodos(todos)
+
+	todo, err := mc.selectTodoIn(archive, "Enter the id, row number or task of the todo to restore: ")
+	if err != nil {
+		mc.display.ShowError(err)
+		return
+	}
+
+	results, err := mc.todoService.Unarchive(archive, []string{todo.ID})
+	if err != nil {
+		mc.display.ShowError(fmt.Errorf("failed to restore todo: %w", err))
+		if results == nil {
+			return
+		}
+	}
+	mc.lastShown = nil
+	mc.display.ShowTransfers("move", archive.File(), mc.todoService.File(), results)
+}
@@ -0,0 +1,100 @@
+package menu
+
+import (
+	"fmt"
+
+	"github.com/Ng1n3/go-todo/internal/service"
+	"github.com/Ng1n3/go-todo/internal/utils"
+)
+
+// bulkUpdate applies one change to every todo matching a filter, after a
+// preview and a confirmation, or undoes the last bulk update.
+func (mc *MenuController) bulkUpdate() {
+	choice, err := mc.input.ReadChoice("\n1.) Set priority\n2.) Add labels\n3.) Remove labels\n4.) Shift due dates\n5.) Complete\n6.) Delete\n7.) Undo the last bulk update\n8.) back\nChoice: ", []string{"1", "2", "3", "4", "5", "6", "7", "8"})
+	if err != nil {
+		mc.display.ShowError(err)
+		return
+	}
+
+	var update service.BulkUpdate
+	switch choice {
+	case "1":
+		if update.Priority, err = mc.input.ReadPriority("⭐ Enter new priority (HIGH/MEDIUM/LOW): "); err != nil {
+			mc.display.ShowError(err)
+			return
+		}
+	case "2":
+		update.AddLabels = mc.input.ReadLabels("🏷️  Labels to add (comma-separated): ")
+	case "3":
+		update.RemoveLabels = mc.input.ReadLabels("🏷️  Labels to remove (comma-separated): ")
+	case "4":
+		input, err := mc.input.ReadString("📅 Shift due dates by (e.g. 3d or -1w): ")
+		if err != nil {
+			mc.display.ShowError(err)
+			return
+		}
+		if update.ShiftDue, err = utils.ParseDuration(input); err != nil {
+			mc.display.ShowError(fmt.Errorf("invalid shift %q", input))
+			return
+		}
+	case "5":
+		update.Complete = true
+	case "6":
+		update.Delete = true
+	case "7":
+		point, err := mc.todoService.UndoBulk(false)
+		if err != nil {
+			mc.display.ShowError(err)
+			return
+		}
+		mc.display.ShowSuccess(fmt.Sprintf("Reverted %s", point.Description))
+		return
+	case "8":
+		return
+	}
+
+	query, err := mc.input.ReadString("Filter (e.g. label:work is:open priority:low): ")
+	if err != nil {
+		mc.display.ShowError(err)
+		return
+	}
+	filter, err := service.ParseFilter(query)
+	if err != nil {
+		mc.display.ShowError(err)
+		return
+	}
+	if filter.IsZero() {
+		mc.display.ShowError(fmt.Errorf("enter a filter; bulk updates don't apply to every todo"))
+		return
+	}
+
+	matches := mc.todoService.ListFiltered(filter)
+	ids := make([]string, len(matches))
+	for i, todo := range matches {
+		ids[i] = todo.ID
+	}
+	changes, err := mc.todoService.PreviewBulk(ids, update)
+	if err != nil {
+		mc.display.ShowError(err)
+		return
+	}
+	mc.display.ShowBulkChanges(changes)
+	if len(changes) == 0 {
+		return
+	}
+
+	ok, err := mc.input.ReadBool(fmt.Sprintf("Apply %q to %d todos? (y/n): ", update, len(changes)))
+	if err != nil {
+		mc.display.ShowError(err)
+		return
+	}
+	if !ok {
+		mc.display.ShowInfo("Nothing changed")
+		return
+	}
+	if changes, err = mc.todoService.ApplyBulk(ids, update); err != nil {
+		mc.display.ShowError(err)
+		return
+	}
+	mc.display.ShowSuccess(fmt.Sprintf("Changed %d todos; choose Undo in this menu to revert", len(changes)))
+}
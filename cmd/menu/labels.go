@@ -0,0 +1,99 @@
+package menu
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Ng1n3/go-todo/internal/service"
+	"github.com/Ng1n3/go-todo/internal/types"
+	"github.com/Ng1n3/go-todo/internal/utils"
+)
+
+func (mc *MenuController) labelsMenu() {
+	workspace := service.NewWorkspace(mc.config)
+	usage, err := workspace.LabelUsage()
+	if err != nil {
+		mc.display.ShowError(err)
+		return
+	}
+	mc.display.ShowLabels(usage)
+
+	choice, err := mc.input.ReadChoice("\n1.) Rename a label\n2.) Merge labels\n3.) Delete a label\n4.) Label tree\n5.) back\nChoice: ", []string{"1", "2", "3", "4", "5"})
+	if err != nil {
+		mc.display.ShowError(err)
+		return
+	}
+
+	var edits []service.LabelEdit
+	switch choice {
+	case "1":
+		from, err := mc.input.ReadString("🏷️  Label to rename (labels below it follow): ")
+		if err != nil {
+			mc.display.ShowError(err)
+			return
+		}
+		to, err := mc.input.ReadString("🏷️  New name: ")
+		if err != nil {
+			mc.display.ShowError(err)
+			return
+		}
+		edits, err = workspace.RenameLabel(strings.TrimSpace(from), to)
+		if err != nil {
+			mc.display.ShowError(err)
+			return
+		}
+	case "2":
+		labels := mc.input.ReadLabels("🏷️  Labels to merge (comma-separated): ")
+		into, err := mc.input.ReadString("🏷️  Merge them into: ")
+		if err != nil {
+			mc.display.ShowError(err)
+			return
+		}
+		edits, err = workspace.MergeLabels(labels, into)
+		if err != nil {
+			mc.display.ShowError(err)
+			return
+		}
+	case "3":
+		label, err := mc.input.ReadString("🏷️  Label to delete: ")
+		if err != nil {
+			mc.display.ShowError(err)
+			return
+		}
+		count := 0
+		for _, u := range usage {
+			if utils.SameLabel(u.Label, label) {
+				count = u.Open + u.Done
+			}
+		}
+		ok, err := mc.input.ReadBool(fmt.Sprintf("Remove %s from %d todos in every file? (y/n): ", strings.TrimSpace(label), count))
+		if err != nil {
+			mc.display.ShowError(err)
+			return
+		}
+		if !ok {
+			return
+		}
+		if edits, err = workspace.DeleteLabel(label); err != nil {
+			mc.display.ShowError(err)
+			return
+		}
+	case "4":
+		files, err := workspace.LoadAll()
+		if err != nil {
+			mc.display.ShowError(err)
+			return
+		}
+		var todos []types.Todo
+		for _, file := range files {
+			todos = append(todos, file.Todos...)
+		}
+		mc.display.ShowLabelTree(service.LabelTree(todos))
+		return
+	case "5":
+		return
+	}
+
+	mc.display.ShowLabelEdits(edits)
+	mc.display.ShowSuccess("Labels updated successfully!")
+}
@@ -5,11 +5,15 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/Ng1n3/go-todo/internal/config"
 	"github.com/Ng1n3/go-todo/internal/errors"
 	"github.com/Ng1n3/go-todo/internal/service"
+	"github.com/Ng1n3/go-todo/internal/summary"
+	"github.com/Ng1n3/go-todo/internal/types"
 	"github.com/Ng1n3/go-todo/internal/ui"
+	"github.com/Ng1n3/go-todo/internal/utils"
 )
 
 type MenuController struct {
@@ -17,13 +21,24 @@ type MenuController struct {
 	display     *ui.Display
 	config      *config.Config
 	todoService *service.TodoService
+	// lastShown holds the rows of the last displayed todo table.
+	lastShown []types.Todo
 }
 
-func NewMenuController() *MenuController {
+func NewMenuController(cfg *config.Config) *MenuController {
+	if cfg == nil {
+		cfg = config.Default()
+	}
+
+	input := ui.NewInputReader()
+	if cfg.Passphrase == nil {
+		cfg.Passphrase = input.PassphrasePrompt()
+	}
+
 	return &MenuController{
-		input:   ui.NewInputReader(),
+		input:   input,
 		display: ui.NewDisplay(),
-		config:  config.Default(),
+		config:  cfg,
 	}
 }
 
@@ -36,8 +51,8 @@ func (mc *MenuController) Start() {
 	}
 
 	for {
-		choice, err := mc.input.ReadChoice("\n1.) Create a new Todo file\n2.) Load from my todo files\n3.) List todo files\n4.) Delete todo files\n5.) Exit app\nChoice: ",
-			[]string{"1", "2", "3", "4", "5"})
+		choice, err := mc.input.ReadChoice("\n1.) Create a new Todo file\n2.) Load from my todo files\n3.) List todo files\n4.) Delete todo files\n5.) Dashboard\n6.) Agenda & calendar\n7.) Statistics\n8.) Trash\n9.) Labels\n10.) What next?\n11.) Exit app\nChoice: ",
+			[]string{"1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11"})
 
 		if err != nil {
 			mc.display.ShowError(err)
@@ -54,6 +69,18 @@ func (mc *MenuController) Start() {
 		case "4":
 			mc.deleteTodoFile()
 		case "5":
+			mc.showDashboard()
+		case "6":
+			mc.showAgenda()
+		case "7":
+			mc.showStats()
+		case "8":
+			mc.trashMenu()
+		case "9":
+			mc.labelsMenu()
+		case "10":
+			mc.showNext()
+		case "11":
 			fmt.Println("Bye. Hope to see you soon!")
 			return
 		}
@@ -92,6 +119,7 @@ func (mc *MenuController) createTodoFile() {
 	}
 
 	mc.todoService = todoService
+	mc.lastShown = nil
 	mc.display.ShowSuccess(fmt.Sprintf("Created todo file :%s", normalizedName))
 	mc.todoMenu()
 }
@@ -103,8 +131,10 @@ func (mc *MenuController) loadTodoFile() {
 		return
 	}
 
-	if !strings.HasSuffix(filename, ".json") {
-		filename = ".json"
+	filename, err = mc.normalizeFileName(filename)
+	if err != nil {
+		mc.display.ShowError(err)
+		return
 	}
 
 	fullPath := mc.config.GetFullPath(filename)
@@ -121,7 +151,15 @@ func (mc *MenuController) loadTodoFile() {
 	}
 
 	mc.todoService = todoService
+	mc.lastShown = nil
 	mc.display.ShowSuccess(fmt.Sprintf("Loaded todo file: %s", filename))
+
+	results, err := todoService.AutoArchive()
+	if err != nil {
+		mc.display.ShowError(fmt.Errorf("failed to archive completed todos: %w", err))
+	} else if len(results) > 0 {
+		mc.display.ShowTransfers("move", filename, filepath.Join(config.ArchiveDir, filename), results)
+	}
 	mc.todoMenu()
 }
 
@@ -143,6 +181,15 @@ func (mc *MenuController) listTodoFiles() {
 	mc.display.ShowFiles(fileInfos, mc.config.StorageDir)
 }
 
+func (mc *MenuController) showDashboard() {
+	idx, err := summary.Current(mc.config)
+	if err != nil {
+		mc.display.ShowError(fmt.Errorf("failed to load dashboard: %w", err))
+		return
+	}
+	mc.display.ShowDashboard(idx, time.Now())
+}
+
 func (mc *MenuController) deleteTodoFile() {
 	mc.listTodoFiles()
 
@@ -171,29 +218,16 @@ func (mc *MenuController) deleteTodoFile() {
 	}
 
 	if confirm == "y" || confirm == "yes" {
-		if err := os.Remove(fullPath); err != nil {
+		if _, err := service.NewWorkspace(mc.config).DeleteFile(filename); err != nil {
 			mc.display.ShowError(fmt.Errorf("failed to delete file %s: %w", filename, err))
 			return
 		}
-		mc.display.ShowSuccess("Todo file deleted Successfully")
+		mc.display.ShowSuccess("Todo file moved to the trash")
 	} else {
 		mc.display.ShowInfo("Deletion cancelled")
 	}
 }
 
 func (mc *MenuController) normalizeFileName(input string) (string, error) {
-	name := strings.TrimSpace(input)
-	if name == "" {
-		return "", errors.ErrInvalidInput
-	}
-
-	name = strings.TrimSuffix(name, ".json")
-
-	name = filepath.Base(name)
-
-	if name == "" || name == "." {
-		return "", errors.ErrInvalidInput
-	}
-
-	return name + ".json", nil
+	return utils.NormalizeFileName(input)
 }
@@ -0,0 +1,34 @@
+package menu
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Ng1n3/go-todo/internal/service"
+	"github.com/Ng1n3/go-todo/internal/urgency"
+)
+
+// showNext lists the most urgent open todos across all files.
+func (mc *MenuController) showNext() {
+	input, err := mc.input.ReadString("How many todos? (default 5, 0 for all): ")
+	if err != nil {
+		mc.display.ShowError(err)
+		return
+	}
+	n := 5
+	if strings.TrimSpace(input) != "" {
+		if n, err = strconv.Atoi(strings.TrimSpace(input)); err != nil || n < 0 {
+			mc.display.ShowError(fmt.Errorf("invalid number %q", input))
+			return
+		}
+	}
+
+	files, err := service.NewWorkspace(mc.config).LoadAll()
+	if err != nil {
+		mc.display.ShowError(err)
+		return
+	}
+	mc.display.ShowNext(urgency.Next(files, service.Filter{}, mc.config.Urgency, n, false, time.Now()))
+}
@@ -0,0 +1,90 @@
+package menu
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Ng1n3/go-todo/internal/types"
+)
+
+func (mc *MenuController) showDetails() {
+	mc.showTodos(mc.todoService.ListTodos())
+	todo, err := mc.selectTodo("Enter the id, row number or task of the todo to show: ")
+	if err != nil {
+		mc.display.ShowError(err)
+		return
+	}
+	mc.display.ShowTodoDetail(todo)
+}
+
+// editLinks adds a link to todo or removes one of its links.
+func (mc *MenuController) editLinks(todo types.Todo) {
+	mc.display.ShowTodoDetail(todo)
+
+	choice, err := mc.input.ReadChoice("\n1.) Add link\n2.) Remove link\n3.) back\nChoice: ", []string{"1", "2", "3"})
+	if err != nil {
+		mc.display.ShowError(err)
+		return
+	}
+
+	switch choice {
+	case "1":
+		kind, err := mc.input.ReadChoice("Kind (url/file/todo, empty to guess): ", []string{"url", "file", "todo", ""})
+		if err != nil {
+			mc.display.ShowError(err)
+			return
+		}
+		target, err := mc.input.ReadString("🔗 Target (URL, file path or todo): ")
+		if err != nil {
+			mc.display.ShowError(err)
+			return
+		}
+		title, err := mc.input.ReadString("Title (optional): ")
+		if err != nil {
+			mc.display.ShowError(err)
+			return
+		}
+
+		link := types.Link{Kind: types.LinkKind(kind), Target: target, Title: title}
+		if link.Kind == types.LinkTodo {
+			other, err := mc.todoService.ResolveTodo(target, mc.lastShown)
+			if err != nil {
+				mc.display.ShowError(err)
+				return
+			}
+			link.Target = other.ID
+		}
+		if err := mc.todoService.AddLink(todo.ID, link); err != nil {
+			mc.display.ShowError(err)
+			return
+		}
+	case "2":
+		if len(todo.Links) == 0 {
+			mc.display.ShowInfo("The todo has no links")
+			return
+		}
+		input, err := mc.input.ReadString("Number of the link to remove: ")
+		if err != nil {
+			mc.display.ShowError(err)
+			return
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(input))
+		if err != nil {
+			mc.display.ShowError(fmt.Errorf("invalid number %q", input))
+			return
+		}
+		if _, err := mc.todoService.RemoveLink(todo.ID, n); err != nil {
+			mc.display.ShowError(err)
+			return
+		}
+	case "3":
+		return
+	}
+
+	if err := mc.todoService.Save(); err != nil {
+		mc.display.ShowError(fmt.Errorf("failed to save updates: %w", err))
+		return
+	}
+	mc.display.ShowSuccess("Links updated successfully!")
+}
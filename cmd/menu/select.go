@@ -0,0 +1,44 @@
+package menu
+
+import (
+	stderrors "errors"
+	"fmt"
+
+	"github.com/Ng1n3/go-todo/internal/service"
+	"github.com/Ng1n3/go-todo/internal/types"
+)
+
+// showTodos renders todos and remembers their order, so that the next prompt
+// can refer to a todo by its row number.
+func (mc *MenuController) showTodos(todos []types.Todo) {
+	mc.lastShown = todos
+	mc.display.ShowTodos(todos)
+}
+
+// selectTodo prompts for a todo reference (ID, ID prefix, row number or part
+// of the task) and resolves it. When the reference is ambiguous the matching
+// todos are listed and the user picks one by row number.
+func (mc *MenuController) selectTodo(prompt string) (types.Todo, error) {
+	return mc.selectTodoIn(mc.todoService, prompt)
+}
+
+// selectTodoIn is selectTodo for the todos of ts, such as an archive.
+func (mc *MenuController) selectTodoIn(ts *service.TodoService, prompt string) (types.Todo, error) {
+	for {
+		ref, err := mc.input.ReadString(prompt)
+		if err != nil {
+			return types.Todo{}, err
+		}
+
+		todo, err := ts.ResolveTodo(ref, mc.lastShown)
+
+		var ambiguous *service.AmbiguousRefError
+		if !stderrors.As(err, &ambiguous) {
+			return todo, err
+		}
+
+		mc.display.ShowInfo(fmt.Sprintf("%q matches more than one todo:", ref))
+		mc.showTodos(ambiguous.Candidates)
+		prompt = "Enter the row number of the todo you meant: "
+	}
+}
@@ -0,0 +1,112 @@
+package menu
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Ng1n3/go-todo/internal/errors"
+	"github.com/Ng1n3/go-todo/internal/service"
+	"github.com/Ng1n3/go-todo/internal/stats"
+)
+
+func (mc *MenuController) showStats() {
+	mc.listTodoFiles()
+
+	name, err := mc.input.ReadString("Enter a todo file, or leave empty for all files: ")
+	if err != nil {
+		mc.display.ShowError(err)
+		return
+	}
+
+	var names []string
+	if strings.TrimSpace(name) != "" {
+		filename, err := mc.normalizeFileName(name)
+		if err != nil {
+			mc.display.ShowError(err)
+			return
+		}
+		if _, err := os.Stat(mc.config.GetFullPath(filename)); os.IsNotExist(err) {
+			mc.display.ShowError(errors.ErrFileNotFound)
+			return
+		}
+		names = append(names, filename)
+	}
+
+	opts := stats.Options{Now: time.Now()}
+	if opts.From, err = mc.readDate("From (YYYY-MM-DD or e.g. -4w, default -8w): ", "-8w"); err != nil {
+		mc.display.ShowError(err)
+		return
+	}
+	if opts.To, err = mc.readDate("To (YYYY-MM-DD, default today): ", "today"); err != nil {
+		mc.display.ShowError(err)
+		return
+	}
+
+	kind, err := mc.input.ReadChoice("Report (summary/estimates, default summary): ", []string{"summary", "estimates", ""})
+	if err != nil {
+		mc.display.ShowError(err)
+		return
+	}
+	actual := stats.ActualTracked
+	if kind == "estimates" {
+		answer, err := mc.input.ReadChoice("Compare estimates with (tracked/completion, default tracked): ", []string{"tracked", "completion", ""})
+		if err != nil {
+			mc.display.ShowError(err)
+			return
+		}
+		if actual, err = stats.ParseActual(answer); err != nil {
+			mc.display.ShowError(err)
+			return
+		}
+	}
+
+	format, err := mc.input.ReadChoice("Show as (table/json, default table): ", []string{"table", "json", ""})
+	if err != nil {
+		mc.display.ShowError(err)
+		return
+	}
+
+	files, err := service.NewWorkspace(mc.config).LoadWithArchives(names)
+	if err != nil {
+		mc.display.ShowError(err)
+		return
+	}
+
+	var report any
+	if kind == "estimates" {
+		report = stats.CompareEstimates(files, stats.EstimateOptions{From: opts.From, To: opts.To, Actual: actual})
+	} else {
+		report = stats.Compute(files, opts)
+	}
+	if format == "json" {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			mc.display.ShowError(fmt.Errorf("failed to marshal report: %w", err))
+			return
+		}
+		fmt.Println(string(data))
+		return
+	}
+	switch report := report.(type) {
+	case *stats.EstimateReport:
+		mc.display.ShowEstimates(report)
+	case *stats.Report:
+		mc.display.ShowStats(report)
+	}
+}
+
+// readDate prompts for a date in the filter date syntax, using fallback
+// when the answer is empty.
+func (mc *MenuController) readDate(prompt, fallback string) (time.Time, error) {
+	value, err := mc.input.ReadString(prompt)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if strings.TrimSpace(value) == "" {
+		value = fallback
+	}
+	return service.ParseDate(value)
+}
@@ -0,0 +1,67 @@
+package menu
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Ng1n3/go-todo/internal/service"
+)
+
+// applyTemplate creates the todos of a template in the loaded file, asking
+// for the template's variables.
+func (mc *MenuController) applyTemplate() {
+	workspace := service.NewWorkspace(mc.config)
+	templates, err := workspace.Templates()
+	if err != nil {
+		mc.display.ShowError(err)
+		return
+	}
+	if len(templates) == 0 {
+		mc.display.ShowInfo("No templates yet; create one with `go-todo template edit <name>`")
+		return
+	}
+	mc.display.ShowTemplates(templates)
+
+	name, err := mc.input.ReadString("Enter the name of the template: ")
+	if err != nil {
+		mc.display.ShowError(err)
+		return
+	}
+	t, err := workspace.Template(name)
+	if err != nil {
+		mc.display.ShowError(err)
+		return
+	}
+	mc.display.ShowTemplate(t)
+
+	start, err := mc.readDate("Start date (YYYY-MM-DD or e.g. +1w, default today): ", "today")
+	if err != nil {
+		mc.display.ShowError(err)
+		return
+	}
+
+	values := make(map[string]string)
+	for _, v := range t.Placeholders() {
+		prompt := v.Prompt
+		if prompt == "" {
+			prompt = v.Name
+		}
+		if v.Default != "" {
+			prompt = fmt.Sprintf("%s (default %s)", prompt, v.Default)
+		}
+		value, err := mc.input.ReadString(prompt + ": ")
+		if err != nil {
+			mc.display.ShowError(err)
+			return
+		}
+		values[v.Name] = strings.TrimSpace(value)
+	}
+
+	created, err := mc.todoService.ApplyTemplate(t, values, start)
+	if err != nil {
+		mc.display.ShowError(err)
+		return
+	}
+	mc.showTodos(created)
+	mc.display.ShowSuccess(fmt.Sprintf("Created %d todos from %s", len(created), t.Name))
+}
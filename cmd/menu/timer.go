@@ -0,0 +1,232 @@
+package menu
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Ng1n3/go-todo/internal/focus"
+	"github.com/Ng1n3/go-todo/internal/service"
+	"github.com/Ng1n3/go-todo/internal/timesheet"
+	"github.com/Ng1n3/go-todo/internal/types"
+	"github.com/Ng1n3/go-todo/internal/utils"
+)
+
+func (mc *MenuController) timeMenu() {
+	workspace := service.NewWorkspace(mc.config)
+	timer, err := workspace.RunningTimer()
+	if err != nil {
+		mc.display.ShowError(err)
+		return
+	}
+	mc.display.ShowTimer(timer, time.Now())
+
+	choice, err := mc.input.ReadChoice("\n1.) Start timer\n2.) Stop timer\n3.) Log time\n4.) Timesheet of this file\n5.) Focus session\n6.) Focus report\n7.) back\nChoice: ", []string{"1", "2", "3", "4", "5", "6", "7"})
+	if err != nil {
+		mc.display.ShowError(err)
+		return
+	}
+
+	switch choice {
+	case "1":
+		mc.showTodos(mc.todoService.ListTodos())
+		todo, err := mc.selectTodo("Enter the id, row number or task of the todo to time: ")
+		if err != nil {
+			mc.display.ShowError(err)
+			return
+		}
+		if _, err := workspace.StartTimer(mc.todoService, todo.ID, time.Now()); err != nil {
+			mc.display.ShowError(err)
+			return
+		}
+		mc.display.ShowSuccess(fmt.Sprintf("Started timing %q", todo.Task))
+	case "2":
+		note, err := mc.input.ReadString("Note (optional): ")
+		if err != nil {
+			mc.display.ShowError(err)
+			return
+		}
+		stopped, entry, err := workspace.StopTimer(note, time.Now())
+		if err != nil {
+			mc.display.ShowError(err)
+			return
+		}
+		// The time was logged through another service; pick it up so the
+		// next save here doesn't drop it.
+		mc.reloadIfChanged(stopped.File)
+		mc.display.ShowSuccess(fmt.Sprintf("Logged %s on %s in %s", entry.Duration().Round(time.Second), stopped.TodoID, stopped.File))
+	case "3":
+		mc.logTime()
+	case "4":
+		from, err := mc.readDate("From (YYYY-MM-DD or e.g. -4w, default -1w): ", "-1w")
+		if err != nil {
+			mc.display.ShowError(err)
+			return
+		}
+		to, err := mc.readDate("To (YYYY-MM-DD, default today): ", "today")
+		if err != nil {
+			mc.display.ShowError(err)
+			return
+		}
+		archive, err := mc.todoService.OpenArchive()
+		if err != nil {
+			mc.display.ShowError(err)
+			return
+		}
+		files := []service.FileTodos{{
+			File:  mc.todoService.File(),
+			Todos: append(mc.todoService.ListTodos(), archive.ListTodos()...),
+		}}
+		mc.display.ShowTimesheet(timesheet.Compute(files, timesheet.Options{From: from, To: to, By: timesheet.ByTodo}))
+	case "5":
+		mc.focusSession(workspace)
+	case "6":
+		by, err := mc.input.ReadChoice("Per day or week? (day/week, default day): ", []string{"day", "week", ""})
+		if err != nil {
+			mc.display.ShowError(err)
+			return
+		}
+		opts := focus.Options{By: focus.Daily}
+		if by == "week" {
+			opts.By = focus.Weekly
+		}
+		if opts.From, err = mc.readDate("From (YYYY-MM-DD or e.g. -4w, default -1w): ", "-1w"); err != nil {
+			mc.display.ShowError(err)
+			return
+		}
+		if opts.To, err = mc.readDate("To (YYYY-MM-DD, default today): ", "today"); err != nil {
+			mc.display.ShowError(err)
+			return
+		}
+		files, err := service.NewWorkspace(mc.config).LoadWithArchives(nil)
+		if err != nil {
+			mc.display.ShowError(err)
+			return
+		}
+		mc.display.ShowFocusReport(focus.Compute(files, opts))
+	case "7":
+		return
+	}
+}
+
+// focusSession starts a focus session on a todo, or resumes the one in
+// progress, and runs it until it is done or the user presses Ctrl-C.
+func (mc *MenuController) focusSession(workspace *service.Workspace) {
+	current, err := workspace.CurrentFocus()
+	if err != nil {
+		mc.display.ShowError(err)
+		return
+	}
+
+	if current != nil {
+		mc.display.ShowFocus(current, time.Now())
+		resume, err := mc.input.ReadBool("A focus session is in progress. Resume it? (y/n): ")
+		if err != nil {
+			mc.display.ShowError(err)
+			return
+		}
+		if !resume {
+			if _, err := workspace.InterruptFocus(time.Now()); err != nil {
+				mc.display.ShowError(err)
+				return
+			}
+			mc.display.ShowInfo("Stopped the focus session")
+			mc.reloadIfChanged(current.File)
+			return
+		}
+	} else {
+		mc.showTodos(mc.todoService.ListTodos())
+		todo, err := mc.selectTodo("Enter the id, row number or task of the todo to focus on: ")
+		if err != nil {
+			mc.display.ShowError(err)
+			return
+		}
+		input, err := mc.input.ReadString("How many work intervals? (default 4, 0 until stopped): ")
+		if err != nil {
+			mc.display.ShowError(err)
+			return
+		}
+		rounds := 4
+		if strings.TrimSpace(input) != "" {
+			if rounds, err = strconv.Atoi(strings.TrimSpace(input)); err != nil || rounds < 0 {
+				mc.display.ShowError(fmt.Errorf("invalid number %q", input))
+				return
+			}
+		}
+		started, err := workspace.StartFocus(mc.todoService, todo.ID, rounds, time.Now())
+		if err != nil {
+			mc.display.ShowError(err)
+			return
+		}
+		current = &started
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	fmt.Println("Press Ctrl-C to stop the session.")
+	interrupted, err := focus.Run(ctx, workspace, os.Stdout)
+	mc.reloadIfChanged(current.File)
+	if err != nil {
+		mc.display.ShowError(err)
+		return
+	}
+	if interrupted {
+		mc.display.ShowInfo("Focus session stopped")
+		return
+	}
+	mc.display.ShowSuccess("Focus session complete")
+}
+
+// reloadIfChanged reloads the loaded todo file when it is file, which was
+// changed through another service.
+func (mc *MenuController) reloadIfChanged(file string) {
+	if file != mc.todoService.File() {
+		return
+	}
+	if err := mc.todoService.Reload(); err != nil {
+		mc.display.ShowError(err)
+	}
+}
+
+func (mc *MenuController) logTime() {
+	mc.showTodos(mc.todoService.ListTodos())
+	todo, err := mc.selectTodo("Enter the id, row number or task of the todo: ")
+	if err != nil {
+		mc.display.ShowError(err)
+		return
+	}
+
+	input, err := mc.input.ReadString("Time spent (e.g. 1h30m): ")
+	if err != nil {
+		mc.display.ShowError(err)
+		return
+	}
+	duration, err := utils.ParseDuration(input)
+	if err != nil || duration <= 0 {
+		mc.display.ShowError(fmt.Errorf("invalid duration %q", input))
+		return
+	}
+
+	note, err := mc.input.ReadString("Note (optional): ")
+	if err != nil {
+		mc.display.ShowError(err)
+		return
+	}
+
+	now := time.Now()
+	entry := types.TimeEntry{Start: now.Add(-duration), End: now, Note: note}
+	if err := mc.todoService.LogTime(todo.ID, entry); err != nil {
+		mc.display.ShowError(err)
+		return
+	}
+	if err := mc.todoService.Save(); err != nil {
+		mc.display.ShowError(err)
+		return
+	}
+	mc.display.ShowSuccess(fmt.Sprintf("Logged %s on %q", duration, todo.Task))
+}
@@ -5,11 +5,12 @@ import (
 	"strings"
 
 	"github.com/Ng1n3/go-todo/internal/types"
+	"github.com/Ng1n3/go-todo/internal/ui"
 )
 
 func (mc *MenuController) todoMenu() {
 	for {
-		choice, err := mc.input.ReadChoice("\n1.) Create Todo\n2.)List Todos \n3.)Update Todo\n4.)Delete Todo\n5.)main menu \nChoice: ", []string{"1", "2", "3", "4", "5"})
+		choice, err := mc.input.ReadChoice("\n1.) Create Todo\n2.)List Todos \n3.)Update Todo\n4.)Delete Todo\n5.)Move or copy Todos\n6.)Archive\n7.)Board\n8.)Time tracking\n9.)Details\n10.)Apply template\n11.)Bulk update\n12.)main menu \nChoice: ", []string{"1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11", "12"})
 		if err != nil {
 			mc.display.ShowError(err)
 			continue
@@ -25,6 +26,20 @@ func (mc *MenuController) todoMenu() {
 		case "4":
 			mc.deleteTodo()
 		case "5":
+			mc.transferTodos()
+		case "6":
+			mc.archiveMenu()
+		case "7":
+			mc.display.ShowBoard(mc.todoService.Workflow(), mc.todoService.ListTodos())
+		case "8":
+			mc.timeMenu()
+		case "9":
+			mc.showDetails()
+		case "10":
+			mc.applyTemplate()
+		case "11":
+			mc.bulkUpdate()
+		case "12":
 			mc.display.ShowInfo("Returning to Main menu ...")
 			return
 		default:
@@ -62,6 +77,12 @@ func (mc *MenuController) createTodo() {
 		return
 	}
 
+	estimate, err := mc.input.ReadString("Enter estimate (e.g. 90m, 2h or 3pt, optional): ")
+	if err != nil {
+		mc.display.ShowError(err)
+		return
+	}
+
 	completed, err := mc.input.ReadChoice("Is the task completed? (y/n, default n): ", []string{"y", "n", "yes", "no", ""})
 	if err != nil {
 		mc.display.ShowError(err)
@@ -74,7 +95,7 @@ func (mc *MenuController) createTodo() {
 		completed = "true"
 	}
 
-	todo, err := mc.todoService.CreateTodo(task, dueDate, completed, types.Priority(strings.ToLower(priority)), labels)
+	todo, err := mc.todoService.CreateTodo(task, dueDate, completed, types.Priority(strings.ToLower(priority)), labels, estimate)
 	if err != nil {
 		mc.display.ShowError(fmt.Errorf("failed to save todo: %w", err))
 		return
@@ -91,21 +112,15 @@ func (mc *MenuController) createTodo() {
 
 func (mc *MenuController) listTodo() {
 	todos := mc.todoService.ListTodos()
-	mc.display.ShowTodos(todos)
+	mc.showTodos(todos)
 }
 
 func (mc *MenuController) updateTodo() {
 	todos := mc.todoService.ListTodos()
 
-	mc.display.ShowTodos(todos)
-
-	todoID, err := mc.input.ReadString("Enter the id of the todo to update: ")
-	if err != nil {
-		mc.display.ShowError(err)
-		return
-	}
+	mc.showTodos(todos)
 
-	todo, err := mc.todoService.GetTodo(todoID)
+	todo, err := mc.selectTodo("Enter the id, row number or task of the todo to update: ")
 	if err != nil {
 		mc.display.ShowError(err)
 		return
@@ -113,7 +128,7 @@ func (mc *MenuController) updateTodo() {
 
 	mc.display.ShowTodo(todo)
 
-	field, err := mc.input.ReadChoice("\nwhich field woud you like to update?\n1.) Task\n2.)Due Date \n3.)Priority\n4.)Labels\n5.)Completed Status \n6.) back \nChoice: ", []string{"1", "2", "3", "4", "5", "6"})
+	field, err := mc.input.ReadChoice("\nwhich field woud you like to update?\n1.) Task\n2.)Due Date \n3.)Priority\n4.)Labels\n5.)Completed Status \n6.)Status \n7.)Estimate \n8.)Notes \n9.)Links \n10.) back \nChoice: ", []string{"1", "2", "3", "4", "5", "6", "7", "8", "9", "10"})
 	if err != nil {
 		mc.display.ShowError(err)
 		return
@@ -154,11 +169,44 @@ func (mc *MenuController) updateTodo() {
 		}
 		updates["completed"] = completed
 	case "6":
+		next := mc.todoService.Workflow().Next(todo.Status)
+		if len(next) == 0 {
+			mc.display.ShowInfo(fmt.Sprintf("No status changes are allowed from %s", todo.Status.Label()))
+			return
+		}
+		options := make([]string, len(next))
+		for i, status := range next {
+			options[i] = string(status)
+		}
+		status, err := mc.input.ReadChoice(fmt.Sprintf("🔀 Move from %s to (%s): ", todo.Status, strings.Join(options, "/")), options)
+		if err != nil {
+			mc.display.ShowError(err)
+			return
+		}
+		updates["status"] = status
+	case "7":
+		estimate, err := mc.input.ReadString("⏱️  Enter new estimate (e.g. 90m, 2h or 3pt, empty to clear): ")
+		if err != nil {
+			mc.display.ShowError(err)
+			return
+		}
+		updates["estimate"] = estimate
+	case "8":
+		notes, err := ui.EditText(todo.Notes, todo.ID+".md")
+		if err != nil {
+			mc.display.ShowError(err)
+			return
+		}
+		updates["notes"] = notes
+	case "9":
+		mc.editLinks(todo)
+		return
+	case "10":
 		mc.display.ShowInfo("Returning to menu...")
 		return
 	}
 
-	if err := mc.todoService.UpdateTodo(todoID, updates); err != nil {
+	if err := mc.todoService.UpdateTodo(todo.ID, updates); err != nil {
 		mc.display.ShowError(err)
 		return
 	}
@@ -174,27 +222,26 @@ func (mc *MenuController) updateTodo() {
 func (mc *MenuController) deleteTodo() {
 	todos := mc.todoService.ListTodos()
 
-	mc.display.ShowTodos(todos)
+	mc.showTodos(todos)
 
-	todoID, err := mc.input.ReadString("Enter the id of the todo to delete: ")
+	todo, err := mc.selectTodo("Enter the id, row number or task of the todo to delete: ")
 	if err != nil {
 		mc.display.ShowError(err)
 		return
 	}
 
-	todo, err := mc.todoService.GetTodo(todoID)
+	mc.display.ShowTodo(todo)
+	err = mc.todoService.DeleteTodo(todo.ID)
 	if err != nil {
 		mc.display.ShowError(err)
 		return
 	}
 
-	mc.display.ShowTodo(todo)
-	err = mc.todoService.DeleteTodo(todoID)
-	if err != nil {
-		mc.display.ShowError(err)
+	if err := mc.todoService.Save(); err != nil {
+		mc.display.ShowError(fmt.Errorf("failed to save deletion: %w", err))
 		return
 	}
 
-	mc.display.ShowSuccess("Todo deleted successfully!")
+	mc.display.ShowSuccess("Todo moved to the trash!")
 
 }
@@ -0,0 +1,98 @@
+package menu
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/Ng1n3/go-todo/internal/service"
+)
+
+func (mc *MenuController) transferTodos() {
+	mc.showTodos(mc.todoService.ListTodos())
+
+	which, err := mc.input.ReadChoice("\nWhich todos?\n1.) A single todo\n2.) All todos matching a filter\n3.) back\nChoice: ", []string{"1", "2", "3"})
+	if err != nil {
+		mc.display.ShowError(err)
+		return
+	}
+
+	var ids []string
+	switch which {
+	case "1":
+		todo, err := mc.selectTodo("Enter the id, row number or task of the todo: ")
+		if err != nil {
+			mc.display.ShowError(err)
+			return
+		}
+		ids = append(ids, todo.ID)
+	case "2":
+		query, err := mc.input.ReadString("Enter a filter (e.g. label:work is:open due<+7d): ")
+		if err != nil {
+			mc.display.ShowError(err)
+			return
+		}
+		filter, err := service.ParseFilter(query)
+		if err != nil {
+			mc.display.ShowError(err)
+			return
+		}
+		matches := mc.todoService.ListFiltered(filter)
+		mc.showTodos(matches)
+		for _, todo := range matches {
+			ids = append(ids, todo.ID)
+		}
+	case "3":
+		return
+	}
+
+	if len(ids) == 0 {
+		mc.display.ShowInfo("No todos selected")
+		return
+	}
+
+	mode, err := mc.input.ReadChoice(fmt.Sprintf("Move or copy %d todo(s)? (move/copy): ", len(ids)), []string{"move", "copy"})
+	if err != nil {
+		mc.display.ShowError(err)
+		return
+	}
+
+	mc.listTodoFiles()
+	target, err := mc.input.ReadString("Enter the name of the target todo file: ")
+	if err != nil {
+		mc.display.ShowError(err)
+		return
+	}
+
+	normalizedName, err := mc.normalizeFileName(target)
+	if err != nil {
+		mc.display.ShowError(err)
+		return
+	}
+
+	fullPath := mc.config.GetFullPath(normalizedName)
+	if _, err := os.Stat(fullPath); os.IsNotExist(err) {
+		mc.display.ShowInfo(fmt.Sprintf("%s doesn't exist yet and will be created", normalizedName))
+	}
+
+	dst, err := service.NewTodoService(fullPath, mc.config)
+	if err != nil {
+		mc.display.ShowError(err)
+		return
+	}
+
+	transfer := service.CopyTodos
+	if mode == "move" {
+		transfer = service.MoveTodos
+	}
+
+	results, err := transfer(mc.todoService, dst, ids, service.ConflictRename)
+	if err != nil {
+		mc.display.ShowError(fmt.Errorf("failed to %s todos: %w", mode, err))
+		if results == nil {
+			return
+		}
+	}
+
+	mc.lastShown = nil
+	mc.display.ShowTransfers(mode, mc.todoService.File(), dst.File(), results)
+}
@@ -0,0 +1,64 @@
+package menu
+
+import (
+	"fmt"
+
+	"github.com/Ng1n3/go-todo/internal/service"
+)
+
+func (mc *MenuController) trashMenu() {
+	workspace := service.NewWorkspace(mc.config)
+
+	items, err := workspace.Trash()
+	if err != nil {
+		mc.display.ShowError(err)
+		return
+	}
+	mc.display.ShowTrash(items, mc.config.TrashRetention)
+	if len(items) == 0 {
+		return
+	}
+
+	choice, err := mc.input.ReadChoice("\n1.) Restore an item\n2.) Empty the trash\n3.) back\nChoice: ", []string{"1", "2", "3"})
+	if err != nil {
+		mc.display.ShowError(err)
+		return
+	}
+
+	switch choice {
+	case "1":
+		id, err := mc.input.ReadString("Enter the id of the item to restore: ")
+		if err != nil {
+			mc.display.ShowError(err)
+			return
+		}
+		item, todo, err := workspace.RestoreTrash(id)
+		if err != nil {
+			mc.display.ShowError(fmt.Errorf("failed to restore %s: %w", id, err))
+			return
+		}
+		if todo.ID != "" {
+			mc.display.ShowSuccess(fmt.Sprintf("Restored todo %s to %s", todo.ID, item.File))
+		} else {
+			mc.display.ShowSuccess(fmt.Sprintf("Restored %s", item.File))
+		}
+	case "2":
+		confirm, err := mc.input.ReadChoice(fmt.Sprintf("Permanently delete %d item(s)? (y/n): ", len(items)), []string{"y", "n", "yes", "no"})
+		if err != nil {
+			mc.display.ShowError(err)
+			return
+		}
+		if confirm != "y" && confirm != "yes" {
+			mc.display.ShowInfo("Trash left as it is")
+			return
+		}
+		n, err := workspace.EmptyTrash()
+		if err != nil {
+			mc.display.ShowError(err)
+			return
+		}
+		mc.display.ShowSuccess(fmt.Sprintf("Deleted %d item(s) for good", n))
+	case "3":
+		return
+	}
+}
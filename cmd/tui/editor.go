@@ -0,0 +1,92 @@
+package tui
+
+import (
+	"unicode"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// lineEditor edits a single line of text with the usual readline keys.
+type lineEditor struct {
+	text []rune
+	pos  int
+}
+
+func newLineEditor(text string) *lineEditor {
+	runes := []rune(text)
+	return &lineEditor{text: runes, pos: len(runes)}
+}
+
+func (e *lineEditor) String() string {
+	return string(e.text)
+}
+
+// handle applies an editing key and reports whether it changed the text.
+// Keys it doesn't know are ignored.
+func (e *lineEditor) handle(k key) bool {
+	switch k.code {
+	case keyRune:
+		e.text = append(e.text[:e.pos], append([]rune{k.r}, e.text[e.pos:]...)...)
+		e.pos++
+		return true
+	case keyBackspace:
+		if e.pos == 0 {
+			return false
+		}
+		e.text = append(e.text[:e.pos-1], e.text[e.pos:]...)
+		e.pos--
+		return true
+	case keyDelete:
+		if e.pos == len(e.text) {
+			return false
+		}
+		e.text = append(e.text[:e.pos], e.text[e.pos+1:]...)
+		return true
+	case keyCtrlU:
+		changed := e.pos > 0
+		e.text, e.pos = e.text[e.pos:], 0
+		return changed
+	case keyCtrlK:
+		changed := e.pos < len(e.text)
+		e.text = e.text[:e.pos]
+		return changed
+	case keyCtrlW:
+		start := e.pos
+		for start > 0 && unicode.IsSpace(e.text[start-1]) {
+			start--
+		}
+		for start > 0 && !unicode.IsSpace(e.text[start-1]) {
+			start--
+		}
+		changed := start < e.pos
+		e.text, e.pos = append(e.text[:start], e.text[e.pos:]...), start
+		return changed
+	case keyLeft:
+		if e.pos > 0 {
+			e.pos--
+		}
+	case keyRight:
+		if e.pos < len(e.text) {
+			e.pos++
+		}
+	case keyHome, keyCtrlA:
+		e.pos = 0
+	case keyEnd, keyCtrlE:
+		e.pos = len(e.text)
+	}
+	return false
+}
+
+// view returns the part of the text that fits in width columns, scrolled so
+// the cursor is visible, and the column of the cursor in it.
+func (e *lineEditor) view(width int) (string, int) {
+	if width < 1 {
+		return "", 0
+	}
+	start := 0
+	for runewidth.StringWidth(string(e.text[start:e.pos])) >= width {
+		start++
+	}
+	visible := runewidth.Truncate(string(e.text[start:]), width, "")
+	return visible, runewidth.StringWidth(string(e.text[start:e.pos]))
+}
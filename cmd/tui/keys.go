@@ -0,0 +1,122 @@
+package tui
+
+import (
+	"bytes"
+	"unicode/utf8"
+)
+
+type keyCode int
+
+const (
+	keyNone keyCode = iota
+	// keyRune is a printable character, held in key.r.
+	keyRune
+	keyEnter
+	keyEsc
+	keyTab
+	keyBackTab
+	keyBackspace
+	keyDelete
+	keyUp
+	keyDown
+	keyLeft
+	keyRight
+	keyHome
+	keyEnd
+	keyPageUp
+	keyPageDown
+	keyCtrlA
+	keyCtrlC
+	keyCtrlE
+	keyCtrlK
+	keyCtrlL
+	keyCtrlU
+	keyCtrlW
+)
+
+type key struct {
+	code keyCode
+	r    rune
+}
+
+// is reports whether k is the printable character r.
+func (k key) is(r rune) bool {
+	return k.code == keyRune && k.r == r
+}
+
+var controlKeys = map[byte]keyCode{
+	0x01: keyCtrlA,
+	0x03: keyCtrlC,
+	0x05: keyCtrlE,
+	0x08: keyBackspace,
+	0x09: keyTab,
+	0x0a: keyEnter,
+	0x0b: keyCtrlK,
+	0x0c: keyCtrlL,
+	0x0d: keyEnter,
+	0x15: keyCtrlU,
+	0x17: keyCtrlW,
+	0x7f: keyBackspace,
+}
+
+// csiKeys maps the final byte of an escape sequence such as "\x1b[A" to
+// its key, and tildeKeys the parameter of one such as "\x1b[5~".
+var (
+	csiKeys = map[byte]keyCode{
+		'A': keyUp,
+		'B': keyDown,
+		'C': keyRight,
+		'D': keyLeft,
+		'H': keyHome,
+		'F': keyEnd,
+		'Z': keyBackTab,
+	}
+	tildeKeys = map[string]keyCode{
+		"1": keyHome,
+		"7": keyHome,
+		"4": keyEnd,
+		"8": keyEnd,
+		"3": keyDelete,
+		"5": keyPageUp,
+		"6": keyPageDown,
+	}
+)
+
+// decodeKey decodes the first key in b and returns it with the number of
+// bytes it took. Unknown sequences decode as keyNone.
+func decodeKey(b []byte) (key, int) {
+	if len(b) == 0 {
+		return key{}, 0
+	}
+
+	if b[0] == 0x1b {
+		if len(b) == 1 || (b[1] != '[' && b[1] != 'O') {
+			return key{code: keyEsc}, 1
+		}
+		// CSI and SS3 sequences: parameters, then a final byte.
+		for i := 2; i < len(b); i++ {
+			c := b[i]
+			if c >= 0x40 && c <= 0x7e {
+				if c == '~' {
+					param, _, _ := bytes.Cut(b[2:i], []byte{';'})
+					return key{code: tildeKeys[string(param)]}, i + 1
+				}
+				return key{code: csiKeys[c]}, i + 1
+			}
+		}
+		return key{}, len(b)
+	}
+
+	if code, ok := controlKeys[b[0]]; ok {
+		return key{code: code}, 1
+	}
+	if b[0] < 0x20 {
+		return key{}, 1
+	}
+
+	r, n := utf8.DecodeRune(b)
+	if r == utf8.RuneError {
+		return key{}, n
+	}
+	return key{code: keyRune, r: r}, n
+}
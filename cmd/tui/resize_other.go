@@ -0,0 +1,9 @@
+//go:build !unix
+
+package tui
+
+// watchResize does nothing where there is no SIGWINCH; the screen adapts
+// to the new size on the next key.
+func watchResize(redraw func()) (stop func()) {
+	return func() {}
+}
@@ -0,0 +1,83 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+
+	"golang.org/x/term"
+)
+
+// Supported reports whether the full-screen interface can run: stdin and
+// stdout must both be terminals that understand cursor movement.
+func Supported() bool {
+	return term.IsTerminal(int(os.Stdin.Fd())) && term.IsTerminal(int(os.Stdout.Fd())) && os.Getenv("TERM") != "dumb"
+}
+
+// terminal switches the controlling terminal to raw mode on the alternate
+// screen and reads key presses from it.
+type terminal struct {
+	in    *os.File
+	out   *os.File
+	state *term.State
+	// pending holds bytes read but not decoded yet, e.g. when several keys
+	// arrive in one read because text was pasted.
+	pending []byte
+}
+
+func openTerminal() (*terminal, error) {
+	t := &terminal{in: os.Stdin, out: os.Stdout}
+	if err := t.enter(); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// enter switches to raw mode and the alternate screen.
+func (t *terminal) enter() error {
+	state, err := term.MakeRaw(int(t.in.Fd()))
+	if err != nil {
+		return fmt.Errorf("failed to switch the terminal to raw mode: %w", err)
+	}
+	t.state = state
+	fmt.Fprint(t.out, "\x1b[?1049h\x1b[?25l")
+	return nil
+}
+
+// leave restores the screen and the terminal mode enter found.
+func (t *terminal) leave() {
+	fmt.Fprint(t.out, "\x1b[0m\x1b[?25h\x1b[?1049l")
+	if t.state != nil {
+		term.Restore(int(t.in.Fd()), t.state)
+		t.state = nil
+	}
+}
+
+// size returns the width and height of the terminal, assuming 80x24 when
+// they can't be read.
+func (t *terminal) size() (int, int) {
+	width, height, err := term.GetSize(int(t.out.Fd()))
+	if err != nil || width <= 0 || height <= 0 {
+		return 80, 24
+	}
+	return width, height
+}
+
+// readKey blocks until a key is pressed.
+func (t *terminal) readKey() (key, error) {
+	for {
+		if len(t.pending) == 0 {
+			buf := make([]byte, 256)
+			n, err := t.in.Read(buf)
+			if err != nil {
+				return key{}, fmt.Errorf("failed to read key: %w", err)
+			}
+			t.pending = buf[:n]
+		}
+
+		k, n := decodeKey(t.pending)
+		t.pending = t.pending[n:]
+		if k.code != keyNone {
+			return k, nil
+		}
+	}
+}
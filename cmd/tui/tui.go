@@ -0,0 +1,588 @@
+// Package tui is the full-screen interface of go-todo: a sidebar of todo
+// files, the todos of the open file and a status bar, driven by single
+// keys. Like the menu and the commands, it makes every change through
+// service.TodoService.
+package tui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Ng1n3/go-todo/internal/config"
+	"github.com/Ng1n3/go-todo/internal/errors"
+	"github.com/Ng1n3/go-todo/internal/service"
+	"github.com/Ng1n3/go-todo/internal/summary"
+	"github.com/Ng1n3/go-todo/internal/types"
+	"github.com/Ng1n3/go-todo/internal/ui"
+	"github.com/Ng1n3/go-todo/internal/utils"
+)
+
+type pane int
+
+const (
+	filesPane pane = iota
+	todosPane
+)
+
+// prompt is a line the user is typing, in the bottom line or, when inline
+// is set, in the task column of the selected row.
+type prompt struct {
+	label  string
+	editor *lineEditor
+	inline bool
+	// onChange is called after every edit, onSubmit on Enter and onCancel
+	// on Esc. The prompt is closed before onSubmit and onCancel run, so
+	// they may open another one.
+	onChange func(text string)
+	onSubmit func(text string)
+	onCancel func()
+}
+
+// confirmation is a yes/no question in the bottom line.
+type confirmation struct {
+	question string
+	onYes    func()
+}
+
+type model struct {
+	config    *config.Config
+	workspace *service.Workspace
+	term      *terminal
+
+	files      []string
+	fileCursor int
+	fileOffset int
+	// index holds the counts shown next to the files; it is nil when the
+	// summary file can't be read.
+	index *summary.Index
+
+	todoService *service.TodoService
+	file        string
+	rows        []types.Todo
+	cursor      int
+	offset      int
+	// adding is set while the task of a new todo is typed in an extra row
+	// below the others.
+	adding bool
+
+	query  string
+	filter service.Filter
+
+	focus   pane
+	prompt  *prompt
+	confirm *confirmation
+	help    bool
+
+	message string
+	failed  bool
+	quit    bool
+}
+
+// Run starts the full-screen interface and returns when the user quits.
+// Callers should check Supported first and start the menu otherwise.
+func Run(cfg *config.Config) error {
+	if err := cfg.EnsureStorageDir(); err != nil {
+		return fmt.Errorf("failed to create storage directory: %w", err)
+	}
+
+	t, err := openTerminal()
+	if err != nil {
+		return err
+	}
+	defer t.leave()
+
+	m := &model{config: cfg, workspace: service.NewWorkspace(cfg), term: t}
+	m.askPassphrases()
+	m.loadFiles()
+	// Open the first file right away, unless it would ask for a
+	// passphrase before anything is on screen.
+	if len(m.files) > 0 && !m.encrypted(m.files[0]) && m.openFile(m.files[0]) {
+		m.focus = todosPane
+	}
+
+	var mu sync.Mutex
+	stop := watchResize(func() {
+		mu.Lock()
+		defer mu.Unlock()
+		m.draw()
+	})
+	defer stop()
+
+	m.draw()
+	for !m.quit {
+		k, err := t.readKey()
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		m.handle(k)
+		if !m.quit {
+			m.draw()
+		}
+		mu.Unlock()
+	}
+	return nil
+}
+
+// askPassphrases makes passphrase prompts for encrypted files leave the
+// full screen while they read the passphrase.
+func (m *model) askPassphrases() {
+	ask := m.config.Passphrase
+	if ask == nil {
+		ask = ui.NewInputReader().PassphrasePrompt()
+	}
+	m.config.Passphrase = func(file string, attempt int) (passphrase string, err error) {
+		if os.Getenv(config.PassphraseEnv) != "" {
+			return ask(file, attempt)
+		}
+		m.suspend(func() { passphrase, err = ask(file, attempt) })
+		return passphrase, err
+	}
+}
+
+// suspend hands the terminal back while fn runs, e.g. to run the editor.
+func (m *model) suspend(fn func()) {
+	m.term.leave()
+	fn()
+	if err := m.term.enter(); err != nil {
+		m.fail(err)
+		m.quit = true
+	}
+}
+
+func (m *model) info(message string) {
+	m.message, m.failed = message, false
+}
+
+func (m *model) fail(err error) {
+	// Some validation errors start on a new line for the menu.
+	m.message, m.failed = strings.TrimSpace(err.Error()), true
+}
+
+func (m *model) loadFiles() {
+	files, err := m.workspace.Files()
+	if err != nil {
+		m.fail(err)
+		return
+	}
+	m.files = files
+	m.fileCursor = clamp(m.fileCursor, 0, len(m.files)-1)
+
+	// The counts are a nicety; the sidebar works without them.
+	m.index, _ = summary.Current(m.config)
+}
+
+// summary returns the summary of file, or nil if there is none.
+func (m *model) summary(file string) *summary.FileSummary {
+	if m.index == nil {
+		return nil
+	}
+	return m.index.Files[file]
+}
+
+func (m *model) encrypted(file string) bool {
+	s := m.summary(file)
+	return s != nil && s.Encrypted
+}
+
+// openFile loads a todo file, archiving its old completed todos like the
+// menu does, and reports whether it could be opened.
+func (m *model) openFile(name string) bool {
+	ts, err := m.workspace.Open(name)
+	if err != nil {
+		m.fail(err)
+		return false
+	}
+
+	m.todoService, m.file = ts, name
+	m.cursor, m.offset = 0, 0
+	m.info("Opened " + name)
+
+	results, err := ts.AutoArchive()
+	if err != nil {
+		m.fail(fmt.Errorf("failed to archive completed todos: %w", err))
+	} else if len(results) > 0 {
+		m.info(fmt.Sprintf("Opened %s and archived %d completed todos", name, len(results)))
+	}
+	m.refresh("")
+	return true
+}
+
+// refresh applies the filter to the todos of the open file again, keeping
+// the cursor on the todo with the ID keep if it is still shown.
+func (m *model) refresh(keep string) {
+	m.rows = nil
+	if m.todoService != nil {
+		m.rows = m.filter.Apply(m.todoService.ListTodos(), time.Now())
+	}
+	for i, todo := range m.rows {
+		if todo.ID == keep {
+			m.cursor = i
+		}
+	}
+	m.cursor = clamp(m.cursor, 0, len(m.rows)-1)
+}
+
+func (m *model) selected() (types.Todo, bool) {
+	if m.todoService == nil || m.cursor >= len(m.rows) {
+		return types.Todo{}, false
+	}
+	return m.rows[m.cursor], true
+}
+
+// save saves the open file after a change and reports done, or why the
+// file could not be saved.
+func (m *model) save(keep, done string) {
+	defer m.refresh(keep)
+	if err := m.todoService.Save(); err != nil {
+		m.fail(err)
+		return
+	}
+	m.index, _ = summary.Current(m.config)
+	m.info(done)
+}
+
+func (m *model) handle(k key) {
+	if m.prompt != nil {
+		m.handlePrompt(k)
+		return
+	}
+	if m.confirm != nil {
+		c := m.confirm
+		m.confirm = nil
+		if k.is('y') || k.is('Y') {
+			c.onYes()
+		} else {
+			m.info("Cancelled")
+		}
+		return
+	}
+	if m.help {
+		// Any key closes the help.
+		m.help = false
+		return
+	}
+
+	switch {
+	case k.code == keyCtrlC || k.is('q'):
+		m.quit = true
+		return
+	case k.is('?'):
+		m.help = true
+		return
+	case k.code == keyCtrlL:
+		m.loadFiles()
+		return
+	case k.code == keyTab || k.code == keyBackTab:
+		if m.focus == filesPane && m.todoService != nil {
+			m.focus = todosPane
+		} else {
+			m.focus = filesPane
+		}
+		return
+	}
+
+	if m.focus == filesPane {
+		m.handleFiles(k)
+	} else {
+		m.handleTodos(k)
+	}
+}
+
+func (m *model) handlePrompt(k key) {
+	p := m.prompt
+	switch k.code {
+	case keyEnter:
+		m.prompt = nil
+		if p.onSubmit != nil {
+			p.onSubmit(p.editor.String())
+		}
+	case keyEsc, keyCtrlC:
+		m.prompt = nil
+		if p.onCancel != nil {
+			p.onCancel()
+		}
+	default:
+		if p.editor.handle(k) && p.onChange != nil {
+			p.onChange(p.editor.String())
+		}
+	}
+}
+
+// move applies a movement key to a cursor over n rows, paging by page rows,
+// and reports whether k was a movement key.
+func move(k key, cursor *int, n, page int) bool {
+	switch {
+	case k.code == keyUp || k.is('k'):
+		*cursor--
+	case k.code == keyDown || k.is('j'):
+		*cursor++
+	case k.code == keyPageUp:
+		*cursor -= page
+	case k.code == keyPageDown:
+		*cursor += page
+	case k.code == keyHome || k.is('g'):
+		*cursor = 0
+	case k.code == keyEnd || k.is('G'):
+		*cursor = n - 1
+	default:
+		return false
+	}
+	*cursor = clamp(*cursor, 0, n-1)
+	return true
+}
+
+func clamp(n, lo, hi int) int {
+	if n > hi {
+		n = hi
+	}
+	if n < lo {
+		n = lo
+	}
+	return n
+}
+
+func (m *model) handleFiles(k key) {
+	if move(k, &m.fileCursor, len(m.files), m.pageSize()) {
+		return
+	}
+
+	switch {
+	case k.code == keyEnter || k.code == keyRight || k.is('l'):
+		if len(m.files) > 0 && m.openFile(m.files[m.fileCursor]) {
+			m.focus = todosPane
+		}
+	case k.is('a'):
+		m.prompt = &prompt{label: "New file", editor: newLineEditor(""), onSubmit: m.createFile}
+	case k.is('r'):
+		m.loadFiles()
+		m.info("Reloaded the file list")
+	}
+}
+
+func (m *model) createFile(name string) {
+	name, err := utils.NormalizeFileName(name)
+	if err != nil {
+		m.fail(err)
+		return
+	}
+	if _, err := os.Stat(m.config.GetFullPath(name)); err == nil {
+		m.fail(fmt.Errorf("file %s already exists", name))
+		return
+	}
+
+	ts, err := m.workspace.Open(name)
+	if err != nil {
+		m.fail(err)
+		return
+	}
+	if err := ts.Save(); err != nil {
+		m.fail(err)
+		return
+	}
+
+	m.loadFiles()
+	for i, file := range m.files {
+		if file == name {
+			m.fileCursor = i
+		}
+	}
+	if m.openFile(name) {
+		m.focus = todosPane
+		m.info("Created " + name)
+	}
+}
+
+func (m *model) handleTodos(k key) {
+	if move(k, &m.cursor, len(m.rows), m.pageSize()) {
+		return
+	}
+
+	switch {
+	case k.code == keyLeft || k.is('h'):
+		m.focus = filesPane
+	case k.code == keyEsc:
+		if m.query != "" {
+			m.applyFilter("")
+			m.info("Cleared the filter")
+		} else {
+			m.focus = filesPane
+		}
+	case k.is('/'):
+		m.startFilter()
+	case k.is('a'):
+		m.startAdd()
+	case k.is('r'):
+		m.reload()
+	}
+
+	todo, ok := m.selected()
+	if !ok {
+		return
+	}
+	switch {
+	case k.is(' ') || k.is('x'):
+		state := "done"
+		if todo.Completed {
+			state = "open"
+		}
+		m.update(todo, "completed", !todo.Completed, fmt.Sprintf("Marked %q %s", todo.Task, state))
+	case k.code == keyEnter || k.is('e'):
+		m.prompt = &prompt{label: "Task", editor: newLineEditor(todo.Task), inline: true, onSubmit: func(task string) {
+			m.update(todo, "task", task, "Renamed the todo")
+		}}
+	case k.is('d'):
+		due := ""
+		if todo.HasDueDate() {
+			due = todo.DueDate.Format("2006-01-02")
+		}
+		m.prompt = &prompt{label: "Due (YYYY-MM-DD, today, +3d)", editor: newLineEditor(due), onSubmit: func(value string) {
+			due, err := service.ParseDate(value)
+			if err != nil {
+				m.fail(err)
+				return
+			}
+			m.update(todo, "due_date", due.Format("2006-01-02"), fmt.Sprintf("%q is due on %s", todo.Task, due.Format("2006-01-02")))
+		}}
+	case k.is('p'):
+		next := map[types.Priority]types.Priority{types.Low: types.Medium, types.Medium: types.High, types.High: types.Low}[todo.Priority.Normalize()]
+		if next == "" {
+			next = types.Low
+		}
+		m.update(todo, "priority", next, fmt.Sprintf("%q is now %s priority", todo.Task, next))
+	case k.is('l'):
+		m.prompt = &prompt{label: "Labels (comma-separated)", editor: newLineEditor(strings.Join(todo.Labels, ", ")), onSubmit: func(labels string) {
+			m.update(todo, "labels", labels, fmt.Sprintf("Labelled %q", todo.Task))
+		}}
+	case k.is('s'):
+		m.startStatus(todo)
+	case k.is('n'):
+		var notes string
+		var err error
+		m.suspend(func() { notes, err = ui.EditText(todo.Notes, todo.ID+".md") })
+		if err != nil {
+			m.fail(err)
+			return
+		}
+		m.update(todo, "notes", notes, fmt.Sprintf("Saved the notes of %q", todo.Task))
+	case k.is('D') || k.code == keyDelete:
+		m.confirm = &confirmation{
+			question: fmt.Sprintf("Delete %q? (y/n)", todo.Task),
+			onYes: func() {
+				if err := m.todoService.DeleteTodo(todo.ID); err != nil {
+					m.fail(err)
+					return
+				}
+				m.save("", fmt.Sprintf("Deleted %q; it is kept in the trash", todo.Task))
+			},
+		}
+	}
+}
+
+// update changes one field of todo through the service and saves the file.
+func (m *model) update(todo types.Todo, field string, value any, done string) {
+	if err := m.todoService.UpdateTodo(todo.ID, map[string]any{field: value}); err != nil {
+		m.fail(err)
+		return
+	}
+	m.save(todo.ID, done)
+}
+
+func (m *model) startStatus(todo types.Todo) {
+	next := m.todoService.Workflow().Next(todo.Status)
+	if len(next) == 0 {
+		m.fail(fmt.Errorf("%w: %s is a final status", errors.ErrInvalidInput, todo.Status.Label()))
+		return
+	}
+	names := make([]string, len(next))
+	for i, status := range next {
+		names[i] = string(status)
+	}
+	m.prompt = &prompt{label: "Status (" + strings.Join(names, ", ") + ")", editor: newLineEditor(""), onSubmit: func(status string) {
+		m.update(todo, "status", status, fmt.Sprintf("Moved %q to %s", todo.Task, types.ParseStatus(status).Label()))
+	}}
+}
+
+// startAdd asks for the task of a new todo in an extra row, then for its
+// due date.
+func (m *model) startAdd() {
+	if m.todoService == nil {
+		return
+	}
+	m.adding = true
+	m.cursor = len(m.rows)
+	stop := func() {
+		m.adding = false
+		m.cursor = clamp(m.cursor, 0, len(m.rows)-1)
+	}
+
+	m.prompt = &prompt{label: "Task", editor: newLineEditor(""), inline: true, onCancel: stop, onSubmit: func(task string) {
+		stop()
+		if _, err := utils.ValidateTask(task); err != nil {
+			m.fail(err)
+			return
+		}
+		m.prompt = &prompt{label: "Due (YYYY-MM-DD, today, +3d)", editor: newLineEditor("today"), onSubmit: func(value string) {
+			due, err := service.ParseDate(value)
+			if err != nil {
+				m.fail(err)
+				return
+			}
+			todo, err := m.todoService.CreateTodo(task, due.Format("2006-01-02"), "", "", "", "")
+			if err != nil {
+				m.fail(err)
+				return
+			}
+			m.save(todo.ID, fmt.Sprintf("Added %q", todo.Task))
+		}}
+	}}
+}
+
+// startFilter filters the todos as the query is typed. Esc goes back to
+// the filter in effect before.
+func (m *model) startFilter() {
+	before := m.query
+	m.prompt = &prompt{
+		label:    "Filter",
+		editor:   newLineEditor(m.query),
+		onChange: func(query string) { m.applyFilter(query) },
+		onSubmit: func(query string) { m.applyFilter(query) },
+		onCancel: func() { m.applyFilter(before) },
+	}
+}
+
+// applyFilter filters the todos with query. A query that doesn't parse,
+// e.g. one still being typed, leaves the current filter in place.
+func (m *model) applyFilter(query string) {
+	filter, err := service.ParseFilter(query)
+	if err != nil {
+		m.fail(err)
+		return
+	}
+	keep := ""
+	if todo, ok := m.selected(); ok {
+		keep = todo.ID
+	}
+	m.query, m.filter = strings.TrimSpace(query), filter
+	m.message = ""
+	m.refresh(keep)
+}
+
+// reload reads the open file and the file list again, e.g. after they were
+// changed by a command in another terminal.
+func (m *model) reload() {
+	keep := ""
+	if todo, ok := m.selected(); ok {
+		keep = todo.ID
+	}
+	if err := m.todoService.Reload(); err != nil {
+		m.fail(err)
+		return
+	}
+	m.loadFiles()
+	m.refresh(keep)
+	m.info("Reloaded " + m.file)
+}
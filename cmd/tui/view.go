@@ -0,0 +1,357 @@
+package tui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/mattn/go-runewidth"
+
+	"github.com/Ng1n3/go-todo/internal/types"
+)
+
+// SGR attributes. Colours are left out when fatih/color has them disabled,
+// e.g. because NO_COLOR is set; the layout doesn't depend on them.
+const (
+	bold    = "1"
+	dim     = "2"
+	reverse = "7"
+	red     = "31"
+	green   = "32"
+	yellow  = "33"
+)
+
+func style(text string, attrs ...string) string {
+	codes := make([]string, 0, len(attrs))
+	for _, attr := range attrs {
+		if color.NoColor && attr >= "30" {
+			continue
+		}
+		codes = append(codes, attr)
+	}
+	if len(codes) == 0 {
+		return text
+	}
+	return "\x1b[" + strings.Join(codes, ";") + "m" + text + "\x1b[0m"
+}
+
+// fit truncates or pads text to exactly width columns.
+func fit(text string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	if runewidth.StringWidth(text) > width {
+		text = runewidth.Truncate(text, width, "…")
+	}
+	return runewidth.FillRight(text, width)
+}
+
+var help = []string{
+	"Everywhere",
+	"  Tab          switch between files and todos",
+	"  ↑ ↓ j k      move          PgUp PgDn  page",
+	"  Home End g G first, last   Ctrl-L     redraw",
+	"  ?            this help     q Ctrl-C   quit",
+	"",
+	"Files",
+	"  Enter → l    open the file",
+	"  a            new file      r          reload the list",
+	"",
+	"Todos",
+	"  Space x      toggle done   Enter e    edit the task",
+	"  a            add a todo    D Del      delete (to the trash)",
+	"  d            due date      p          cycle priority",
+	"  l            labels        s          status",
+	"  n            notes in $EDITOR",
+	"  /            filter as you type, e.g. label:work due:overdue",
+	"  Esc          clear the filter, or back to the files",
+	"  r            reload the file",
+	"",
+	"While typing: Enter saves, Esc cancels; Ctrl-A/E/U/K/W edit the line.",
+}
+
+// sidebarWidth is the width of the file list, without the separator.
+func sidebarWidth(width int) int {
+	return clamp(width/4, 16, 28)
+}
+
+// pageSize is the number of todo rows that fit on the screen.
+func (m *model) pageSize() int {
+	_, height := m.term.size()
+	// Title, column header, status bar and bottom line.
+	return clamp(height-4, 1, height)
+}
+
+// draw renders the whole screen. Each line is cleared before it is
+// written, so nothing is left of the previous frame.
+func (m *model) draw() {
+	width, height := m.term.size()
+	var b strings.Builder
+	b.WriteString("\x1b[?25l")
+	line := func(row int, text string) {
+		fmt.Fprintf(&b, "\x1b[%d;1H\x1b[2K%s", row, text)
+	}
+
+	if width < 40 || height < 8 {
+		b.WriteString("\x1b[2J")
+		line(1, fit("Make the terminal larger to use go-todo.", width))
+		m.term.out.WriteString(b.String())
+		return
+	}
+
+	// Never write the last column of the bottom line, which would scroll
+	// the screen on some terminals.
+	line(1, style(fit(" go-todo  "+m.config.StorageDir, width-7)+fit("? help", 7), reverse))
+
+	side := sidebarWidth(width)
+	list := width - side - 1
+	rows := height - 4
+	sidebar := m.drawFiles(side, rows+1)
+	var body []string
+	cursorRow, cursorCol := -1, -1
+	if m.help {
+		for _, text := range help {
+			body = append(body, " "+fit(text, list-1))
+		}
+	} else {
+		body, cursorRow, cursorCol = m.drawTodos(list, rows)
+	}
+
+	for i := 0; i < rows+1; i++ {
+		text := ""
+		if i < len(sidebar) {
+			text = sidebar[i]
+		} else {
+			text = strings.Repeat(" ", side)
+		}
+		text += style("│", dim)
+		if i < len(body) {
+			text += body[i]
+		}
+		line(2+i, text)
+	}
+
+	line(height-1, m.statusBar(width))
+
+	switch {
+	case m.prompt != nil && !m.prompt.inline:
+		label := m.prompt.label + ": "
+		view, col := m.prompt.editor.view(width - 1 - runewidth.StringWidth(label))
+		line(height, style(label, bold)+view)
+		cursorRow, cursorCol = height, runewidth.StringWidth(label)+col
+	case m.confirm != nil:
+		line(height, style(fit(m.confirm.question, width-1), bold, yellow))
+	default:
+		line(height, style(fit(m.hints(), width-1), dim))
+	}
+
+	if cursorRow > 0 {
+		fmt.Fprintf(&b, "\x1b[%d;%dH\x1b[?25h", cursorRow, cursorCol+1)
+	}
+	m.term.out.WriteString(b.String())
+}
+
+// drawFiles renders the sidebar: a header, then the files with their open
+// and overdue counts.
+func (m *model) drawFiles(width, height int) []string {
+	lines := []string{style(fit(" Files", width), bold)}
+
+	visible := height - 1
+	if m.fileCursor < m.fileOffset {
+		m.fileOffset = m.fileCursor
+	}
+	if m.fileCursor >= m.fileOffset+visible {
+		m.fileOffset = m.fileCursor - visible + 1
+	}
+
+	now := time.Now()
+	for i := m.fileOffset; i < len(m.files) && i < m.fileOffset+visible; i++ {
+		name := m.files[i]
+		marker := "  "
+		if name == m.file {
+			marker = "▸ "
+		}
+		count := ""
+		if s := m.summary(name); s != nil {
+			count = strconv.Itoa(s.Open)
+			if s.Encrypted && s.Total == 0 {
+				count = "🔒"
+			}
+			if overdue := s.Overdue(now); overdue > 0 {
+				count = fmt.Sprintf("%d!%d", s.Open, overdue)
+			}
+		}
+		text := marker + fit(strings.TrimSuffix(name, ".json"), width-3-runewidth.StringWidth(count)) + count + " "
+
+		switch {
+		case i == m.fileCursor && m.focus == filesPane:
+			text = style(text, reverse)
+		case name == m.file:
+			text = style(text, bold)
+		}
+		lines = append(lines, text)
+	}
+	if len(m.files) == 0 {
+		lines = append(lines, style(fit("  No files yet;", width), dim), style(fit("  press a", width), dim))
+	}
+	return lines
+}
+
+// drawTodos renders the column header and the rows of the todo list, and
+// returns where the cursor goes when a task is edited inline.
+func (m *model) drawTodos(width, height int) ([]string, int, int) {
+	if m.todoService == nil {
+		return []string{"", style(" Open a file from the list.", dim)}, -1, -1
+	}
+
+	// Narrow terminals drop the status and label columns.
+	statusWidth, labelWidth := 0, 0
+	if width >= 72 {
+		statusWidth = 12
+	}
+	if width >= 56 {
+		labelWidth = clamp(width/5, 10, 24)
+	}
+	taskWidth := width - 1 - 4 - 2 - 11 - statusWidth - labelWidth
+
+	cells := func(check, priority, due, status, task, labels string) string {
+		text := " " + fit(check, 4) + fit(priority, 2) + fit(due, 11)
+		if statusWidth > 0 {
+			text += fit(status, statusWidth)
+		}
+		text += fit(task, taskWidth)
+		if labelWidth > 0 {
+			text += " " + fit(labels, labelWidth-1)
+		}
+		return text
+	}
+	lines := []string{style(cells("", "P", "Due", "Status", "Task", "Labels"), bold)}
+
+	n := len(m.rows)
+	if m.adding {
+		n++
+	}
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+height {
+		m.offset = m.cursor - height + 1
+	}
+	m.offset = clamp(m.offset, 0, n)
+
+	now := time.Now()
+	cursorRow, cursorCol := -1, -1
+	taskCol := width - taskWidth - labelWidth
+	for i := m.offset; i < n && i < m.offset+height; i++ {
+		if i == len(m.rows) {
+			// The new todo being added.
+			view, col := m.prompt.editor.view(taskWidth)
+			text := cells("[ ]", "", "", "", "", "")
+			lines = append(lines, text[:byteOffset(text, taskCol)]+fit(view, taskWidth))
+			cursorRow, cursorCol = 2+len(lines)-1, side(m)+taskCol+col
+			continue
+		}
+
+		todo := m.rows[i]
+		check := "[ ]"
+		if todo.Completed {
+			check = "[x]"
+		}
+		due := ""
+		if todo.HasDueDate() {
+			due = todo.DueDate.Format("2006-01-02")
+		}
+		priority := string(todo.Priority.Normalize())
+		if priority != "" {
+			priority = priority[:1]
+		}
+		text := cells(check, priority, due, todo.Status.Label(), todo.Task, strings.Join(todo.Labels, ", "))
+
+		selected := i == m.cursor && m.focus == todosPane
+		if selected && m.prompt != nil && m.prompt.inline {
+			view, col := m.prompt.editor.view(taskWidth)
+			text = text[:byteOffset(text, taskCol)] + fit(view, taskWidth) + text[byteOffset(text, taskCol+taskWidth):]
+			cursorRow, cursorCol = 2+len(lines), side(m)+taskCol+col
+		}
+
+		switch {
+		case selected:
+			text = style(text, reverse)
+		case todo.Completed:
+			text = style(text, dim)
+		case todo.IsOverdue(now):
+			text = style(text, red)
+		case todo.Priority.Normalize() == types.High:
+			text = style(text, yellow)
+		}
+		lines = append(lines, text)
+	}
+
+	if len(m.rows) == 0 && !m.adding {
+		if m.query != "" {
+			lines = append(lines, style(" No todos match the filter.", dim))
+		} else {
+			lines = append(lines, style(" No todos yet; press a to add one.", dim))
+		}
+	}
+	return lines, cursorRow, cursorCol
+}
+
+// side is the column where the todo list starts, counted from zero.
+func side(m *model) int {
+	width, _ := m.term.size()
+	return sidebarWidth(width) + 1
+}
+
+// byteOffset returns the byte offset of column col in text.
+func byteOffset(text string, col int) int {
+	width := 0
+	for i, r := range text {
+		if width >= col {
+			return i
+		}
+		width += runewidth.RuneWidth(r)
+	}
+	return len(text)
+}
+
+func (m *model) statusBar(width int) string {
+	left := " No file open"
+	if m.todoService != nil {
+		open, done := 0, 0
+		for _, todo := range m.todoService.ListTodos() {
+			if todo.Completed {
+				done++
+			} else {
+				open++
+			}
+		}
+		left = fmt.Sprintf(" %s · %d open · %d done", m.file, open, done)
+		if m.query != "" {
+			left += fmt.Sprintf(" · %d shown by %q", len(m.rows), m.query)
+		}
+	}
+
+	right := m.message
+	if m.failed {
+		right = "✗ " + right
+	}
+	space := width - 1 - runewidth.StringWidth(left)
+	if space < 1 {
+		return style(fit(left, width-1), reverse)
+	}
+	bar := style(left+" ", reverse)
+	if m.failed {
+		return bar + style(fit(right, space-1), reverse, bold, red)
+	}
+	return bar + style(fit(right, space-1), reverse, green)
+}
+
+func (m *model) hints() string {
+	if m.focus == filesPane {
+		return " ↑↓ move  Enter open  a new file  r reload  Tab todos  ? help  q quit"
+	}
+	return " Space done  Enter edit  a add  d due  p priority  l labels  s status  n notes  D delete  / filter  ? help  q quit"
+}
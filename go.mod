@@ -3,13 +3,18 @@ module github.com/Ng1n3/go-todo
 go 1.22.3
 
 require (
-	github.com/fatih/color v1.15.0 // indirect
+	github.com/fatih/color v1.15.0
+	github.com/mattn/go-runewidth v0.0.16
+	github.com/olekukonko/tablewriter v1.0.9
+	golang.org/x/crypto v0.13.0
+	golang.org/x/term v0.12.0
+)
+
+require (
 	github.com/mattn/go-colorable v0.1.13 // indirect
 	github.com/mattn/go-isatty v0.0.19 // indirect
-	github.com/mattn/go-runewidth v0.0.16 // indirect
 	github.com/olekukonko/errors v1.1.0 // indirect
 	github.com/olekukonko/ll v0.0.9 // indirect
-	github.com/olekukonko/tablewriter v1.0.9 // indirect
 	github.com/rivo/uniseg v0.2.0 // indirect
 	golang.org/x/sys v0.12.0 // indirect
 )
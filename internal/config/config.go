@@ -1,34 +1,210 @@
 // Package config provides configuration management for storage directories, summary file paths, and default file permission
+//
+// Configuration is loaded in layers, each overriding the previous one:
+// built-in defaults, the config file in $XDG_CONFIG_HOME/go-todo, GO_TODO_*
+// environment variables and finally command-line flags. The layer every
+// setting came from is remembered so it can be reported by `config show`.
 package config
 
 import (
 	"os"
 	"path/filepath"
+	"time"
+
+	"github.com/Ng1n3/go-todo/internal/types"
+)
+
+// PassphraseEnv is the environment variable holding the passphrase of
+// encrypted todo files.
+const PassphraseEnv = "GO_TODO_PASSPHRASE"
+
+// ArchiveDir is the directory inside the storage directory that holds the
+// archived todos of each file.
+const ArchiveDir = "archive"
+
+// Interfaces started when go-todo runs without a command.
+const (
+	// InterfaceTUI is the full-screen interface. It needs a terminal and
+	// falls back to the menu otherwise.
+	InterfaceTUI = "tui"
+	// InterfaceMenu is the numbered menu.
+	InterfaceMenu = "menu"
 )
 
+// Formats of todo listings on the command line, chosen with the output
+// setting. The template formats carry the template after an equals sign,
+// e.g. "template={{.ID}} {{.Task}}".
+const (
+	OutputTable        = "table"
+	OutputJSON         = "json"
+	OutputNDJSON       = "ndjson"
+	OutputCSV          = "csv"
+	OutputYAML         = "yaml"
+	OutputTemplate     = "template"
+	OutputTemplateFile = "template-file"
+)
+
+// TemplateDir is the directory inside the storage directory that holds the
+// todo templates.
+const TemplateDir = "templates"
+
 type Config struct {
 	StorageDir  string
 	SummaryFile string
 	FileMode    os.FileMode
+
+	// ArchiveAfter is how long after completion a todo is moved to the
+	// archive automatically. Zero disables automatic archiving.
+	ArchiveAfter time.Duration
+	// TrashRetention is how long deleted todos and files are kept in the
+	// trash before they are purged. Zero keeps them until the trash is
+	// emptied.
+	TrashRetention time.Duration
+
+	// Interface is what runs when no command is given: InterfaceTUI or
+	// InterfaceMenu.
+	Interface string
+
+	// Output is the format commands list todos in; see OutputTable.
+	Output string
+
+	// Focus holds the interval lengths of Pomodoro focus sessions.
+	Focus Focus
+
+	// Urgency holds the coefficients of the urgency score used by `next`.
+	Urgency Urgency
+
+	// GitAutoCommit commits every save to a git repository in StorageDir.
+	GitAutoCommit bool
+	// GitRemote is the remote that sync pulls from and pushes to.
+	GitRemote string
+
+	// Passphrase is called when an encrypted todo file is loaded. attempt
+	// counts previous failed attempts for the same file, so a prompt can ask
+	// again instead of reusing a remembered passphrase. When nil, only
+	// $GO_TODO_PASSPHRASE is used.
+	Passphrase func(file string, attempt int) (string, error)
+
+	// sources records where each setting's value came from, keyed by setting.
+	sources map[string]string
+}
+
+// Focus configures focus sessions: work intervals separated by short breaks,
+// with a long break after every LongBreakEvery work intervals.
+type Focus struct {
+	Work           time.Duration `json:"work"`
+	ShortBreak     time.Duration `json:"short_break"`
+	LongBreak      time.Duration `json:"long_break"`
+	LongBreakEvery int           `json:"long_break_every"`
+}
+
+// Urgency weighs the terms of a todo's urgency score. Each term is a factor
+// between 0 and 1 times its coefficient: Due grows from 0.2 two weeks before
+// the due date to 1 a week after it, and Age grows to 1 at AgeMax. Labels
+// maps labels to the amount they add, which may be negative; a label's
+// weight also applies to the labels below it. Blocked applies to todos in
+// any of BlockedStatuses, so custom workflows can name their own.
+type Urgency struct {
+	PriorityHigh    float64            `json:"priority_high"`
+	PriorityMedium  float64            `json:"priority_medium"`
+	PriorityLow     float64            `json:"priority_low"`
+	Due             float64            `json:"due"`
+	Age             float64            `json:"age"`
+	AgeMax          time.Duration      `json:"age_max"`
+	Blocked         float64            `json:"blocked"`
+	BlockedStatuses []types.Status     `json:"blocked_statuses"`
+	Labels          map[string]float64 `json:"labels,omitempty"`
 }
 
 func Default() *Config {
+	dataDir := DataDir()
 	return &Config{
-		StorageDir:  "storage",
-		SummaryFile: "save_todos.json",
+		StorageDir:  filepath.Join(dataDir, "storage"),
+		SummaryFile: filepath.Join(dataDir, "save_todos.json"),
 		FileMode:    0644,
+		GitRemote:   "origin",
+		Interface:   InterfaceTUI,
+		Output:      OutputTable,
+
+		TrashRetention: 30 * 24 * time.Hour,
+		Focus: Focus{
+			Work:           25 * time.Minute,
+			ShortBreak:     5 * time.Minute,
+			LongBreak:      15 * time.Minute,
+			LongBreakEvery: 4,
+		},
+		// The coefficients Taskwarrior uses.
+		Urgency: Urgency{
+			PriorityHigh:    6.0,
+			PriorityMedium:  3.9,
+			PriorityLow:     1.8,
+			Due:             12.0,
+			Age:             2.0,
+			AgeMax:          365 * 24 * time.Hour,
+			Blocked:         -5.0,
+			BlockedStatuses: []types.Status{types.StatusBlocked},
+		},
 	}
 }
 
-// EnsureStorageDir create storage Directory if it doesn't exist
+// EnsureStorageDir create storage Directory, and the directory holding the
+// summary file, if they don't exist
 func (c *Config) EnsureStorageDir() error {
-	if _, err := os.Stat(c.StorageDir); os.IsNotExist(err) {
-		return os.MkdirAll(c.StorageDir, 0755)
+	if err := os.MkdirAll(c.StorageDir, 0755); err != nil {
+		return err
 	}
-	return nil
+	return os.MkdirAll(filepath.Dir(c.SummaryFile), 0755)
 }
 
 // GetFullPath return full path for filename
 func (c *Config) GetFullPath(filename string) string {
 	return filepath.Join(c.StorageDir, filename)
 }
+
+// GetStatePath returns the path of an internal state file. State files live in
+// a hidden directory inside the storage directory so they are never listed as
+// todo files.
+func (c *Config) GetStatePath(name string) string {
+	return filepath.Join(c.StorageDir, ".state", name)
+}
+
+// GetArchivePath returns the path of the archive companion of a todo file.
+// Archives live in the archive sub-directory under the same name, so they are
+// not listed as todo files.
+func (c *Config) GetArchivePath(filename string) string {
+	return filepath.Join(c.StorageDir, ArchiveDir, filepath.Base(filename))
+}
+
+// GetTemplatePath returns the path of the template with the given file name.
+func (c *Config) GetTemplatePath(filename string) string {
+	return filepath.Join(c.StorageDir, TemplateDir, filepath.Base(filename))
+}
+
+// EnsureStateDir creates the internal state directory if it doesn't exist
+func (c *Config) EnsureStateDir() error {
+	return os.MkdirAll(filepath.Join(c.StorageDir, ".state"), 0755)
+}
+
+// DataDir returns the go-todo directory under $XDG_DATA_HOME, falling back to
+// ~/.local/share, or the current directory if the home directory is unknown.
+func DataDir() string {
+	if dir := os.Getenv("XDG_DATA_HOME"); dir != "" {
+		return filepath.Join(dir, "go-todo")
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		return filepath.Join(home, ".local", "share", "go-todo")
+	}
+	return "."
+}
+
+// ConfigDir returns the go-todo directory under $XDG_CONFIG_HOME, falling
+// back to ~/.config.
+func ConfigDir() string {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "go-todo")
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		return filepath.Join(home, ".config", "go-todo")
+	}
+	return "."
+}
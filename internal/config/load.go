@@ -0,0 +1,281 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type LoadOptions struct {
+	// File is an explicit config file path. When empty, $GO_TODO_CONFIG and
+	// then the files in ConfigDir are tried.
+	File string
+	// Flags holds values given on the command line, keyed by setting.
+	Flags map[string]string
+}
+
+// Load builds the effective configuration from the defaults, the config
+// file, the environment and the command-line flags, in that order.
+func Load(opts LoadOptions) (*Config, error) {
+	cfg := Default()
+
+	path, err := findConfigFile(opts.File)
+	if err != nil {
+		return nil, err
+	}
+	if path != "" {
+		values, err := readConfigFile(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, key := range sortedKeys(values) {
+			if err := cfg.Set(key, values[key], SourceFile+" "+path); err != nil {
+				return nil, fmt.Errorf("config file %s: %w", path, err)
+			}
+		}
+	}
+
+	for _, s := range settings {
+		name := EnvName(s.key)
+		if value, ok := os.LookupEnv(name); ok && value != "" {
+			if err := cfg.Set(s.key, value, SourceEnv+" "+name); err != nil {
+				return nil, fmt.Errorf("environment %s: %w", name, err)
+			}
+		}
+	}
+
+	for _, key := range sortedKeys(opts.Flags) {
+		if err := cfg.Set(key, opts.Flags[key], SourceFlag+" --"+FlagName(key)); err != nil {
+			return nil, fmt.Errorf("flag --%s: %w", FlagName(key), err)
+		}
+	}
+
+	return cfg, nil
+}
+
+// findConfigFile returns the config file to read, or "" if there is none.
+// An explicitly requested file must exist.
+func findConfigFile(explicit string) (string, error) {
+	if explicit == "" {
+		explicit = os.Getenv("GO_TODO_CONFIG")
+	}
+	if explicit != "" {
+		explicit = expandHome(explicit)
+		if _, err := os.Stat(explicit); err != nil {
+			return "", fmt.Errorf("config file %s: %w", explicit, err)
+		}
+		return explicit, nil
+	}
+
+	dir := ConfigDir()
+	for _, name := range []string{"config", "config.toml", "config.json"} {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+	return "", nil
+}
+
+// readConfigFile parses a JSON or TOML config file into flat setting keys.
+// Nested tables/objects become dotted keys, e.g. [urgency] due = 12 is
+// returned as "urgency.due".
+func readConfigFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	isJSON := strings.HasSuffix(path, ".json")
+	if !strings.HasSuffix(path, ".toml") && !isJSON {
+		isJSON = bytes.HasPrefix(bytes.TrimSpace(data), []byte("{"))
+	}
+
+	if isJSON {
+		return parseJSON(data, path)
+	}
+	return parseTOML(data, path)
+}
+
+func parseJSON(data []byte, path string) (map[string]string, error) {
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	values := make(map[string]string)
+	var err error
+	var flatten func(prefix string, m map[string]any)
+	flatten = func(prefix string, m map[string]any) {
+		for k, v := range m {
+			key := prefix + k
+			switch v := v.(type) {
+			case map[string]any:
+				flatten(key+".", v)
+			case []any:
+				parts := make([]string, len(v))
+				for i, item := range v {
+					parts[i] = fmt.Sprint(item)
+				}
+				values[key] = strings.Join(parts, ",")
+			case float64:
+				values[key] = strconv.FormatFloat(v, 'f', -1, 64)
+				if key == "file_mode" {
+					values[key], err = jsonFileMode(v)
+				}
+			default:
+				values[key] = fmt.Sprint(v)
+			}
+		}
+	}
+	flatten("", raw)
+	if err != nil {
+		return nil, fmt.Errorf("config file %s: %w", path, err)
+	}
+	return values, nil
+}
+
+// jsonFileMode converts a file_mode given as a JSON number to the octal the
+// setting expects. JSON has no octal numbers, so the number is the decimal
+// value of the mode, as encoding/json writes an os.FileMode: 420 is 0644.
+func jsonFileMode(v float64) (string, error) {
+	if v < 0 || v > 0777 || v != math.Trunc(v) {
+		return "", fmt.Errorf("invalid file_mode %v: a JSON number is read as decimal, e.g. 420 for 0644; give the octal mode as a string, e.g. \"0644\"", v)
+	}
+	return fmt.Sprintf("%#o", int(v)), nil
+}
+
+// parseTOML understands the subset of TOML needed for configuration:
+// [tables], key = value pairs, strings, numbers, booleans, flat arrays and
+// comments.
+func parseTOML(data []byte, path string) (map[string]string, error) {
+	values := make(map[string]string)
+	prefix := ""
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(stripComment(scanner.Text()))
+		if line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			table := strings.TrimSpace(line[1 : len(line)-1])
+			if table == "" {
+				return nil, fmt.Errorf("%s:%d: empty table name", path, lineNo)
+			}
+			prefix = table + "."
+			continue
+		}
+
+		key, raw, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("%s:%d: expected key = value", path, lineNo)
+		}
+
+		value, err := tomlValue(strings.TrimSpace(raw))
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, lineNo, err)
+		}
+		values[prefix+strings.Trim(strings.TrimSpace(key), `"`)] = value
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read config file %s: %w", path, err)
+	}
+	return values, nil
+}
+
+func tomlValue(raw string) (string, error) {
+	switch {
+	case raw == "":
+		return "", fmt.Errorf("missing value")
+	case strings.HasPrefix(raw, `"`):
+		value, err := strconv.Unquote(raw)
+		if err != nil {
+			return "", fmt.Errorf("invalid string %s", raw)
+		}
+		return value, nil
+	case strings.HasPrefix(raw, "'"):
+		if len(raw) < 2 || !strings.HasSuffix(raw, "'") {
+			return "", fmt.Errorf("invalid string %s", raw)
+		}
+		return raw[1 : len(raw)-1], nil
+	case strings.HasPrefix(raw, "["):
+		if !strings.HasSuffix(raw, "]") {
+			return "", fmt.Errorf("invalid array %s", raw)
+		}
+		var parts []string
+		for _, item := range strings.Split(raw[1:len(raw)-1], ",") {
+			item = strings.TrimSpace(item)
+			if item == "" {
+				continue
+			}
+			value, err := tomlValue(item)
+			if err != nil {
+				return "", err
+			}
+			parts = append(parts, value)
+		}
+		return strings.Join(parts, ","), nil
+	default:
+		return tomlNumber(raw), nil
+	}
+}
+
+// tomlNumber removes the _ digit separators from a numeric literal such as
+// 1_000 or 0xdead_beef. Other bare values are returned unchanged.
+func tomlNumber(raw string) string {
+	digits := strings.ReplaceAll(raw, "_", "")
+	if digits == raw {
+		return raw
+	}
+	for i, r := range raw {
+		if r == '_' && (i == 0 || i == len(raw)-1 || !isHexDigit(raw[i-1]) || !isHexDigit(raw[i+1])) {
+			return raw
+		}
+	}
+	if _, err := strconv.ParseInt(digits, 0, 64); err == nil {
+		return digits
+	}
+	if _, err := strconv.ParseFloat(digits, 64); err == nil {
+		return digits
+	}
+	return raw
+}
+
+func isHexDigit(c byte) bool {
+	return '0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
+}
+
+// stripComment removes a trailing # comment that is not inside a string.
+func stripComment(line string) string {
+	var quote rune
+	for i, r := range line {
+		switch {
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote == 0 && (r == '"' || r == '\''):
+			quote = r
+		case quote == 0 && r == '#':
+			return line[:i]
+		}
+	}
+	return line
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,69 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTomlValue(t *testing.T) {
+	tests := []struct {
+		raw, want string
+	}{
+		{raw: "1_000", want: "1000"},
+		{raw: "-2_500.5", want: "-2500.5"},
+		{raw: "0xdead_beef", want: "0xdeadbeef"},
+		{raw: "0x_ff", want: "0x_ff"},
+		{raw: "0o6_44", want: "0o644"},
+		{raw: "1e1_0", want: "1e10"},
+		{raw: "0600", want: "0600"},
+		{raw: `"in_progress"`, want: "in_progress"},
+		{raw: "'a_b'", want: "a_b"},
+		{raw: "in_progress", want: "in_progress"},
+		{raw: "_1", want: "_1"},
+		{raw: "1__0", want: "1__0"},
+		{raw: "[1_0, \"a_b\", c_d]", want: "10,a_b,c_d"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			got, err := tomlValue(tt.raw)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("tomlValue(%s) = %q, want %q", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadFileMode(t *testing.T) {
+	tests := []struct {
+		name, file, content string
+		want                os.FileMode
+		wantErr             bool
+	}{
+		{name: "toml octal", file: "config.toml", content: "file_mode = 0600", want: 0600},
+		{name: "toml 0o", file: "config.toml", content: "file_mode = 0o640", want: 0640},
+		{name: "toml string", file: "config.toml", content: `file_mode = "0644"`, want: 0644},
+		{name: "json string", file: "config.json", content: `{"file_mode": "0600"}`, want: 0600},
+		{name: "json decimal", file: "config.json", content: `{"file_mode": 420}`, want: 0644},
+		{name: "json out of range", file: "config.json", content: `{"file_mode": 644}`, wantErr: true},
+		{name: "json fraction", file: "config.json", content: `{"file_mode": 1.5}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.file)
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			cfg, err := Load(LoadOptions{File: path})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load() error = %v, want error %v", err, tt.wantErr)
+			}
+			if err == nil && cfg.FileMode != tt.want {
+				t.Errorf("FileMode = %#o, want %#o", cfg.FileMode, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,365 @@
+package config
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Ng1n3/go-todo/internal/types"
+	"github.com/Ng1n3/go-todo/internal/utils"
+)
+
+const (
+	SourceDefault = "default"
+	SourceFile    = "file"
+	SourceEnv     = "env"
+	SourceFlag    = "flag"
+)
+
+// setting binds a configuration key to a Config field.
+type setting struct {
+	key   string
+	usage string
+	get   func(c *Config) string
+	set   func(c *Config, value string) error
+}
+
+var settings = []setting{
+	{
+		key:   "storage_dir",
+		usage: "directory holding the todo files",
+		get:   func(c *Config) string { return c.StorageDir },
+		set: func(c *Config, value string) error {
+			c.StorageDir = expandHome(value)
+			return nil
+		},
+	},
+	{
+		key:   "summary_file",
+		usage: "path of the summary file",
+		get:   func(c *Config) string { return c.SummaryFile },
+		set: func(c *Config, value string) error {
+			c.SummaryFile = expandHome(value)
+			return nil
+		},
+	},
+	{
+		key:   "file_mode",
+		usage: "permissions of written files, in octal",
+		get:   func(c *Config) string { return fmt.Sprintf("%#o", c.FileMode) },
+		set: func(c *Config, value string) error {
+			digits := strings.TrimSpace(value)
+			digits = strings.TrimPrefix(strings.TrimPrefix(digits, "0o"), "0O")
+			mode, err := strconv.ParseUint(digits, 8, 32)
+			if err != nil || mode > 0777 {
+				return fmt.Errorf("invalid file mode %q: must be octal, e.g. 0644", value)
+			}
+			c.FileMode = os.FileMode(mode)
+			return nil
+		},
+	},
+	{
+		key:   "archive.after",
+		usage: "archive completed todos this long after completion, e.g. 30d; 0 disables",
+		get: func(c *Config) string {
+			if c.ArchiveAfter == 0 {
+				return "0"
+			}
+			return utils.FormatDuration(c.ArchiveAfter)
+		},
+		set: func(c *Config, value string) error {
+			after, err := utils.ParseDuration(value)
+			if err != nil || after < 0 {
+				return fmt.Errorf("invalid duration %q: use e.g. 30d or 2w", value)
+			}
+			c.ArchiveAfter = after
+			return nil
+		},
+	},
+	{
+		key:   "trash.retention",
+		usage: "how long deleted todos and files stay in the trash, e.g. 30d; 0 keeps them until emptied",
+		get: func(c *Config) string {
+			if c.TrashRetention == 0 {
+				return "0"
+			}
+			return utils.FormatDuration(c.TrashRetention)
+		},
+		set: func(c *Config, value string) error {
+			retention, err := utils.ParseDuration(value)
+			if err != nil || retention < 0 {
+				return fmt.Errorf("invalid duration %q: use e.g. 30d or 2w", value)
+			}
+			c.TrashRetention = retention
+			return nil
+		},
+	},
+	focusLength("focus.work", "length of a focus work interval", func(c *Config) *time.Duration { return &c.Focus.Work }),
+	focusLength("focus.short_break", "length of the break after a focus work interval", func(c *Config) *time.Duration { return &c.Focus.ShortBreak }),
+	focusLength("focus.long_break", "length of the long break after every few focus work intervals", func(c *Config) *time.Duration { return &c.Focus.LongBreak }),
+	{
+		key:   "focus.long_break_every",
+		usage: "number of focus work intervals before a long break",
+		get:   func(c *Config) string { return strconv.Itoa(c.Focus.LongBreakEvery) },
+		set: func(c *Config, value string) error {
+			n, err := strconv.Atoi(strings.TrimSpace(value))
+			if err != nil || n < 1 {
+				return fmt.Errorf("invalid count %q: must be a positive number", value)
+			}
+			c.Focus.LongBreakEvery = n
+			return nil
+		},
+	},
+	{
+		key:   "interface",
+		usage: "what runs without a command: tui (full screen) or menu",
+		get:   func(c *Config) string { return c.Interface },
+		set: func(c *Config, value string) error {
+			switch value = strings.ToLower(strings.TrimSpace(value)); value {
+			case InterfaceTUI, InterfaceMenu:
+				c.Interface = value
+				return nil
+			default:
+				return fmt.Errorf("invalid interface %q: use tui or menu", value)
+			}
+		},
+	},
+	{
+		key:   "output",
+		usage: "format of todo listings: table, json, ndjson, csv, yaml, template=TEXT or template-file=PATH",
+		get:   func(c *Config) string { return c.Output },
+		set: func(c *Config, value string) error {
+			format, _, _ := strings.Cut(value, "=")
+			switch format {
+			case OutputTable, OutputJSON, OutputNDJSON, OutputCSV, OutputYAML:
+				if format == value {
+					c.Output = value
+					return nil
+				}
+			case OutputTemplate, OutputTemplateFile:
+				if format != value {
+					c.Output = value
+					return nil
+				}
+			}
+			return fmt.Errorf("invalid output %q: use table, json, ndjson, csv, yaml, template=TEXT or template-file=PATH", value)
+		},
+	},
+	coefficient("urgency.priority_high", "urgency of high priority todos", func(c *Config) *float64 { return &c.Urgency.PriorityHigh }),
+	coefficient("urgency.priority_medium", "urgency of medium priority todos", func(c *Config) *float64 { return &c.Urgency.PriorityMedium }),
+	coefficient("urgency.priority_low", "urgency of low priority todos", func(c *Config) *float64 { return &c.Urgency.PriorityLow }),
+	coefficient("urgency.due", "urgency of a todo about to be or past due", func(c *Config) *float64 { return &c.Urgency.Due }),
+	coefficient("urgency.age", "urgency of a todo as old as urgency.age_max", func(c *Config) *float64 { return &c.Urgency.Age }),
+	{
+		key:   "urgency.age_max",
+		usage: "age at which a todo gets the full urgency.age, e.g. 365d",
+		get:   func(c *Config) string { return utils.FormatDuration(c.Urgency.AgeMax) },
+		set: func(c *Config, value string) error {
+			max, err := utils.ParseDuration(value)
+			if err != nil || max <= 0 {
+				return fmt.Errorf("invalid duration %q: use e.g. 365d", value)
+			}
+			c.Urgency.AgeMax = max
+			return nil
+		},
+	},
+	coefficient("urgency.blocked", "urgency of blocked todos, usually negative", func(c *Config) *float64 { return &c.Urgency.Blocked }),
+	{
+		key:   "urgency.blocked_statuses",
+		usage: "statuses that count as blocked for urgency.blocked, e.g. blocked,waiting",
+		get: func(c *Config) string {
+			statuses := make([]string, len(c.Urgency.BlockedStatuses))
+			for i, status := range c.Urgency.BlockedStatuses {
+				statuses[i] = string(status)
+			}
+			return strings.Join(statuses, ",")
+		},
+		set: func(c *Config, value string) error {
+			var statuses []types.Status
+			for _, name := range strings.Split(value, ",") {
+				if status := types.ParseStatus(name); status != "" {
+					statuses = append(statuses, status)
+				}
+			}
+			c.Urgency.BlockedStatuses = statuses
+			return nil
+		},
+	},
+	{
+		key:   "urgency.labels",
+		usage: "urgency of labels, e.g. urgent=4,someday=-3",
+		get: func(c *Config) string {
+			labels := make([]string, 0, len(c.Urgency.Labels))
+			for label, weight := range c.Urgency.Labels {
+				labels = append(labels, label+"="+strconv.FormatFloat(weight, 'f', -1, 64))
+			}
+			sort.Strings(labels)
+			return strings.Join(labels, ",")
+		},
+		set: func(c *Config, value string) error {
+			weights := make(map[string]float64)
+			for _, pair := range strings.Split(value, ",") {
+				if strings.TrimSpace(pair) == "" {
+					continue
+				}
+				label, weight, ok := strings.Cut(pair, "=")
+				n, err := parseFinite(weight)
+				if !ok || err != nil || strings.TrimSpace(label) == "" {
+					return fmt.Errorf("invalid label weight %q: use label=number", pair)
+				}
+				weights[strings.TrimSpace(label)] = n
+			}
+			c.Urgency.Labels = weights
+			return nil
+		},
+	},
+	{
+		key:   "git.autocommit",
+		usage: "commit the storage directory to git after every save",
+		get:   func(c *Config) string { return strconv.FormatBool(c.GitAutoCommit) },
+		set: func(c *Config, value string) error {
+			enabled, err := strconv.ParseBool(strings.TrimSpace(value))
+			if err != nil {
+				return fmt.Errorf("invalid boolean %q", value)
+			}
+			c.GitAutoCommit = enabled
+			return nil
+		},
+	},
+	{
+		key:   "git.remote",
+		usage: "name of the git remote used by sync",
+		get:   func(c *Config) string { return c.GitRemote },
+		set: func(c *Config, value string) error {
+			c.GitRemote = strings.TrimSpace(value)
+			return nil
+		},
+	},
+}
+
+// focusLength is a setting for one of the focus interval lengths, which must
+// be positive.
+func focusLength(key, usage string, field func(c *Config) *time.Duration) setting {
+	return setting{
+		key:   key,
+		usage: usage + ", e.g. 25m",
+		get:   func(c *Config) string { return utils.FormatDuration(*field(c)) },
+		set: func(c *Config, value string) error {
+			length, err := utils.ParseDuration(value)
+			if err != nil || length <= 0 {
+				return fmt.Errorf("invalid duration %q: use e.g. 25m", value)
+			}
+			*field(c) = length
+			return nil
+		},
+	}
+}
+
+// coefficient is a setting for one of the urgency coefficients.
+func coefficient(key, usage string, field func(c *Config) *float64) setting {
+	return setting{
+		key:   key,
+		usage: usage,
+		get:   func(c *Config) string { return strconv.FormatFloat(*field(c), 'f', -1, 64) },
+		set: func(c *Config, value string) error {
+			n, err := parseFinite(value)
+			if err != nil {
+				return fmt.Errorf("invalid number %q", value)
+			}
+			*field(c) = n
+			return nil
+		},
+	}
+}
+
+// parseFinite parses a number, rejecting NaN and infinities, which would
+// make urgency scores impossible to sort.
+func parseFinite(value string) (float64, error) {
+	n, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil {
+		return 0, err
+	}
+	if math.IsNaN(n) || math.IsInf(n, 0) {
+		return 0, fmt.Errorf("%q is not a finite number", value)
+	}
+	return n, nil
+}
+
+// Setting describes a configuration key and, when returned from
+// Config.Settings, its effective value and where that value came from.
+type Setting struct {
+	Key    string
+	Usage  string
+	Env    string
+	Flag   string
+	Value  string
+	Source string
+}
+
+// Describe lists every configuration key with its environment variable and
+// flag names.
+func Describe() []Setting {
+	out := make([]Setting, 0, len(settings))
+	for _, s := range settings {
+		out = append(out, Setting{Key: s.key, Usage: s.usage, Env: EnvName(s.key), Flag: FlagName(s.key)})
+	}
+	return out
+}
+
+// Settings returns the effective value and source of every setting.
+func (c *Config) Settings() []Setting {
+	out := Describe()
+	for i, s := range settings {
+		out[i].Value = s.get(c)
+		out[i].Source = c.sources[s.key]
+		if out[i].Source == "" {
+			out[i].Source = SourceDefault
+		}
+	}
+	return out
+}
+
+// Set assigns value to the setting key and records source as its origin.
+func (c *Config) Set(key, value, source string) error {
+	for _, s := range settings {
+		if s.key != key {
+			continue
+		}
+		if err := s.set(c, value); err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+		if c.sources == nil {
+			c.sources = make(map[string]string)
+		}
+		c.sources[key] = source
+		return nil
+	}
+	return fmt.Errorf("unknown setting %q", key)
+}
+
+// EnvName returns the environment variable for a setting, e.g.
+// storage_dir -> GO_TODO_STORAGE_DIR.
+func EnvName(key string) string {
+	return "GO_TODO_" + strings.ToUpper(strings.NewReplacer(".", "_", "-", "_").Replace(key))
+}
+
+// FlagName returns the command-line flag for a setting, e.g.
+// storage_dir -> storage-dir.
+func FlagName(key string) string {
+	return strings.NewReplacer("_", "-", ".", "-").Replace(key)
+}
+
+func expandHome(path string) string {
+	path = strings.TrimSpace(path)
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, strings.TrimPrefix(path, "~"))
+		}
+	}
+	return path
+}
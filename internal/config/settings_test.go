@@ -0,0 +1,47 @@
+package config
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/Ng1n3/go-todo/internal/types"
+)
+
+func TestSetUrgencyNumbers(t *testing.T) {
+	tests := []struct {
+		key, value string
+		wantErr    bool
+	}{
+		{key: "urgency.due", value: "15"},
+		{key: "urgency.blocked", value: "-2.5"},
+		{key: "urgency.due", value: "NaN", wantErr: true},
+		{key: "urgency.age", value: "inf", wantErr: true},
+		{key: "urgency.blocked", value: "-Inf", wantErr: true},
+		{key: "urgency.due", value: "lots", wantErr: true},
+		{key: "urgency.labels", value: "urgent=4, someday=-3"},
+		{key: "urgency.labels", value: "urgent=nan", wantErr: true},
+		{key: "urgency.labels", value: "urgent=+Inf", wantErr: true},
+		{key: "urgency.labels", value: "urgent", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key+"="+tt.value, func(t *testing.T) {
+			err := Default().Set(tt.key, tt.value, SourceFlag)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Set(%s, %q) = %v, want error %v", tt.key, tt.value, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSetBlockedStatuses(t *testing.T) {
+	c := Default()
+	if !slices.Equal(c.Urgency.BlockedStatuses, []types.Status{types.StatusBlocked}) {
+		t.Errorf("default blocked statuses = %v, want [blocked]", c.Urgency.BlockedStatuses)
+	}
+	if err := c.Set("urgency.blocked_statuses", "blocked, Waiting On", SourceFlag); err != nil {
+		t.Fatal(err)
+	}
+	if want := []types.Status{"blocked", "waiting_on"}; !slices.Equal(c.Urgency.BlockedStatuses, want) {
+		t.Errorf("blocked statuses = %v, want %v", c.Urgency.BlockedStatuses, want)
+	}
+}
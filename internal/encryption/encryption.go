@@ -0,0 +1,171 @@
+// Package encryption implements passphrase-based encryption of todo files.
+//
+// An encrypted file is a JSON document with a header and the ciphertext.
+// The header records the key derivation function (argon2id), its salt and
+// cost parameters, and the AES-256-GCM nonce. The header is authenticated as
+// additional data, so tampering with the parameters makes decryption fail.
+package encryption
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+
+	"golang.org/x/crypto/argon2"
+
+	"github.com/Ng1n3/go-todo/internal/errors"
+)
+
+const (
+	formatVersion = 1
+	kdfArgon2id   = "argon2id"
+	cipherAESGCM  = "aes-256-gcm"
+	keyLength     = 32
+	saltLength    = 16
+)
+
+// Default argon2id cost parameters.
+const (
+	defaultTime    = 3
+	defaultMemory  = 64 * 1024 // KiB
+	defaultThreads = 4
+)
+
+// Header describes how an encrypted file was produced.
+type Header struct {
+	Version int    `json:"version"`
+	KDF     string `json:"kdf"`
+	Salt    []byte `json:"salt"`
+	Time    uint32 `json:"time"`
+	Memory  uint32 `json:"memory"`
+	Threads uint8  `json:"threads"`
+	Cipher  string `json:"cipher"`
+	Nonce   []byte `json:"nonce"`
+}
+
+type file struct {
+	Encryption *Header `json:"encryption"`
+	Ciphertext []byte  `json:"ciphertext"`
+}
+
+// Key is a key derived from a passphrase together with the parameters used
+// to derive it, so the same key can seal a file again without re-running
+// the key derivation.
+type Key struct {
+	header Header
+	key    []byte
+}
+
+// NewKey derives a key from passphrase with a fresh random salt.
+func NewKey(passphrase string) (*Key, error) {
+	if passphrase == "" {
+		return nil, errors.ErrPassphraseRequired
+	}
+
+	salt := make([]byte, saltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	header := Header{
+		Version: formatVersion,
+		KDF:     kdfArgon2id,
+		Salt:    salt,
+		Time:    defaultTime,
+		Memory:  defaultMemory,
+		Threads: defaultThreads,
+		Cipher:  cipherAESGCM,
+	}
+	return &Key{header: header, key: derive(passphrase, header)}, nil
+}
+
+func derive(passphrase string, h Header) []byte {
+	return argon2.IDKey([]byte(passphrase), h.Salt, h.Time, h.Memory, h.Threads, keyLength)
+}
+
+// IsEncrypted reports whether data is an encrypted todo file.
+func IsEncrypted(data []byte) bool {
+	if !bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		return false
+	}
+
+	var probe struct {
+		Encryption *Header `json:"encryption"`
+	}
+	return json.Unmarshal(data, &probe) == nil && probe.Encryption != nil
+}
+
+// Seal encrypts plaintext with key, using a fresh nonce.
+func Seal(key *Key, plaintext []byte) ([]byte, error) {
+	aead, err := newAEAD(key.key)
+	if err != nil {
+		return nil, err
+	}
+
+	header := key.header
+	header.Nonce = make([]byte, aead.NonceSize())
+	if _, err := rand.Read(header.Nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	aad, err := json.Marshal(header)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal encryption header: %w", err)
+	}
+
+	return json.MarshalIndent(file{
+		Encryption: &header,
+		Ciphertext: aead.Seal(nil, header.Nonce, plaintext, aad),
+	}, "", " ")
+}
+
+// Open decrypts an encrypted file with passphrase. It returns the plaintext
+// and the derived key, which can be used to seal the file again.
+func Open(data []byte, passphrase string) ([]byte, *Key, error) {
+	var f file
+	if err := json.Unmarshal(data, &f); err != nil || f.Encryption == nil {
+		return nil, nil, fmt.Errorf("not an encrypted todo file")
+	}
+
+	h := *f.Encryption
+	if h.Version != formatVersion || h.KDF != kdfArgon2id || h.Cipher != cipherAESGCM {
+		return nil, nil, fmt.Errorf("unsupported encryption format: version %d, %s, %s", h.Version, h.KDF, h.Cipher)
+	}
+	if passphrase == "" {
+		return nil, nil, errors.ErrPassphraseRequired
+	}
+
+	key := &Key{header: h, key: derive(passphrase, h)}
+	key.header.Nonce = nil
+
+	aead, err := newAEAD(key.key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	aad, err := json.Marshal(h)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal encryption header: %w", err)
+	}
+
+	plaintext, err := aead.Open(nil, h.Nonce, f.Ciphertext, aad)
+	if err != nil {
+		return nil, nil, errors.ErrWrongPassphrase
+	}
+	return plaintext, key, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	return aead, nil
+}
@@ -0,0 +1,126 @@
+package encryption
+
+import (
+	"bytes"
+	"encoding/json"
+	stderrors "errors"
+	"testing"
+
+	"github.com/Ng1n3/go-todo/internal/errors"
+)
+
+func TestSealOpen(t *testing.T) {
+	plaintext := []byte(`{"schema_version":3,"todos":{}}`)
+	key, err := NewKey("correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sealed, err := Seal(key, plaintext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsEncrypted(sealed) {
+		t.Fatal("IsEncrypted(sealed) = false")
+	}
+	if IsEncrypted(plaintext) {
+		t.Error("IsEncrypted(plaintext) = true")
+	}
+	if bytes.Contains(sealed, []byte("schema_version")) {
+		t.Error("the sealed file contains the plaintext")
+	}
+
+	// tamper returns sealed with a change made to its decoded form.
+	tamper := func(change func(f *file)) []byte {
+		var f file
+		if err := json.Unmarshal(sealed, &f); err != nil {
+			t.Fatal(err)
+		}
+		change(&f)
+		data, err := json.Marshal(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return data
+	}
+
+	tests := []struct {
+		name       string
+		data       []byte
+		passphrase string
+		wantErr    error
+	}{
+		{name: "right passphrase", data: sealed, passphrase: "correct horse"},
+		{name: "wrong passphrase", data: sealed, passphrase: "battery staple", wantErr: errors.ErrWrongPassphrase},
+		{name: "no passphrase", data: sealed, wantErr: errors.ErrPassphraseRequired},
+		{
+			name:       "ciphertext changed",
+			data:       tamper(func(f *file) { f.Ciphertext[0] ^= 1 }),
+			passphrase: "correct horse",
+			wantErr:    errors.ErrWrongPassphrase,
+		},
+		{
+			// The header is authenticated, so weakening the parameters fails.
+			name:       "header changed",
+			data:       tamper(func(f *file) { f.Encryption.Threads = 1 }),
+			passphrase: "correct horse",
+			wantErr:    errors.ErrWrongPassphrase,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _, err := Open(tt.data, tt.passphrase)
+			if tt.wantErr != nil {
+				if !stderrors.Is(err, tt.wantErr) {
+					t.Fatalf("Open = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, plaintext) {
+				t.Errorf("Open = %s, want %s", got, plaintext)
+			}
+		})
+	}
+}
+
+func TestResealWithOpenedKey(t *testing.T) {
+	key, err := NewKey("pw")
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, err := Seal(key, []byte("one"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, opened, err := Open(first, "pw")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	second, err := Seal(opened, []byte("two"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _, err := Open(second, "pw")
+	if err != nil || string(got) != "two" {
+		t.Fatalf("Open(resealed) = %q, %v; want two", got, err)
+	}
+
+	var a, b file
+	json.Unmarshal(first, &a)
+	json.Unmarshal(second, &b)
+	if bytes.Equal(a.Encryption.Nonce, b.Encryption.Nonce) {
+		t.Error("resealing reused the nonce")
+	}
+	if !bytes.Equal(a.Encryption.Salt, b.Encryption.Salt) {
+		t.Error("resealing changed the salt")
+	}
+}
+
+func TestNewKeyNeedsPassphrase(t *testing.T) {
+	if _, err := NewKey(""); !stderrors.Is(err, errors.ErrPassphraseRequired) {
+		t.Errorf("NewKey(\"\") = %v, want ErrPassphraseRequired", err)
+	}
+}
@@ -10,4 +10,25 @@ var (
 	ErrInvalidDateFormat     = errors.New("invalid date format")
 	ErrTaskTooShort          = errors.New("task must be at least 2 characters long")
 	ErrInvalidCompletedValue = errors.New("invalid input")
+	ErrInvalidDuration       = errors.New("invalid duration")
+	ErrAmbiguousTodo         = errors.New("ambiguous todo reference")
+	ErrUnsupportedSchema     = errors.New("unsupported schema version")
+	ErrPassphraseRequired    = errors.New("passphrase required")
+	ErrWrongPassphrase       = errors.New("wrong passphrase or corrupted file")
+	ErrInvalidFilter         = errors.New("invalid filter")
+	ErrTodoExists            = errors.New("todo already exists")
+	ErrTrashItemNotFound     = errors.New("trash item not found")
+	ErrInvalidWorkflow       = errors.New("invalid workflow")
+	ErrInvalidTransition     = errors.New("status change not allowed")
+	ErrTimerRunning          = errors.New("a timer is already running")
+	ErrNoTimer               = errors.New("no timer is running")
+	ErrFocusRunning          = errors.New("a focus session is already in progress")
+	ErrNoFocus               = errors.New("no focus session in progress")
+	ErrInvalidEstimate       = errors.New("invalid estimate")
+	ErrInvalidLink           = errors.New("invalid link")
+	ErrTemplateNotFound      = errors.New("template not found")
+	ErrInvalidTemplate       = errors.New("invalid template")
+	ErrMissingVariable       = errors.New("missing template variable")
+	ErrNothingToUndo         = errors.New("nothing to undo")
+	ErrLabelNotAllowed       = errors.New("label not allowed")
 )
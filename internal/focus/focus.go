@@ -0,0 +1,83 @@
+// Package focus runs Pomodoro style focus sessions on todos and reports on
+// the recorded sessions. The session itself is kept by service.Workspace so
+// it survives the process; this package drives it in the terminal.
+package focus
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/Ng1n3/go-todo/internal/errors"
+	"github.com/Ng1n3/go-todo/internal/service"
+)
+
+var phaseLabels = map[service.FocusPhase]string{
+	service.FocusWork:       "Focus",
+	service.FocusShortBreak: "Short break",
+	service.FocusLongBreak:  "Long break",
+}
+
+// Run drives the focus session in progress until all its rounds are done or
+// ctx is cancelled, drawing a countdown on out. Cancelling ctx interrupts
+// the session, and Run reports whether that happened. A phase that ran out
+// while nothing was driving the session, e.g. because the terminal was
+// closed, is ended straight away and the session carries on from there.
+func Run(ctx context.Context, ws *service.Workspace, out io.Writer) (bool, error) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	current, err := ws.CurrentFocus()
+	if err != nil {
+		return false, err
+	}
+	if current == nil {
+		return false, errors.ErrNoFocus
+	}
+	focus := *current
+
+	for {
+		for remaining := focus.Remaining(time.Now()); remaining > 0; remaining = focus.Remaining(time.Now()) {
+			fmt.Fprintf(out, "\r%s  ", countdown(focus, remaining))
+			select {
+			case <-ctx.Done():
+				fmt.Fprintln(out)
+				if _, err := ws.InterruptFocus(time.Now()); err != nil {
+					return true, err
+				}
+				return true, nil
+			case <-ticker.C:
+			}
+		}
+		fmt.Fprintf(out, "\r%s  \a\n", countdown(focus, 0))
+
+		next, finished, err := ws.AdvanceFocus(time.Now())
+		if err != nil {
+			return false, err
+		}
+		if finished {
+			return false, nil
+		}
+		focus = next
+	}
+}
+
+// countdown renders the state of the session, e.g.
+// "Focus 2/4  12:05  write report".
+func countdown(focus service.Focus, remaining time.Duration) string {
+	round := fmt.Sprintf("%d", focus.Done+1)
+	if focus.Phase != service.FocusWork {
+		round = fmt.Sprintf("%d", focus.Done)
+	}
+	if focus.Rounds > 0 {
+		round += fmt.Sprintf("/%d", focus.Rounds)
+	}
+
+	seconds := int((remaining + time.Second - 1) / time.Second)
+	task := focus.Task
+	if task == "" {
+		task = focus.TodoID
+	}
+	return fmt.Sprintf("%s %s  %02d:%02d  %s", phaseLabels[focus.Phase], round, seconds/60, seconds%60, task)
+}
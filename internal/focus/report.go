@@ -0,0 +1,125 @@
+package focus
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/Ng1n3/go-todo/internal/errors"
+	"github.com/Ng1n3/go-todo/internal/service"
+	"github.com/Ng1n3/go-todo/internal/types"
+)
+
+// Period is what a report groups the sessions by.
+type Period string
+
+const (
+	Daily  Period = "day"
+	Weekly Period = "week"
+)
+
+func ParsePeriod(value string) (Period, error) {
+	switch p := Period(value); p {
+	case Daily, Weekly:
+		return p, nil
+	case "":
+		return Daily, nil
+	default:
+		return "", fmt.Errorf("%w: unknown period %q, use day or week", errors.ErrInvalidInput, value)
+	}
+}
+
+// Options selects the period the report covers. From and To are inclusive
+// days; a session belongs to the local day it started on.
+type Options struct {
+	From time.Time
+	To   time.Time
+	By   Period
+}
+
+type Report struct {
+	From time.Time
+	To   time.Time
+	By   Period
+	// Rows has one row per day or week of the period, including the ones
+	// without sessions. Weeks start on Monday.
+	Rows  []Row
+	Todos []TodoRow
+	Total Row
+}
+
+// Row counts the sessions of a day or week, and how much time was spent
+// focused in them.
+type Row struct {
+	Start       time.Time
+	Completed   int
+	Interrupted int
+	Focused     time.Duration
+}
+
+// TodoRow counts the sessions spent on a single todo in the period.
+type TodoRow struct {
+	File string
+	ID   string
+	Task string
+	Row
+}
+
+func (r *Row) add(interrupted bool, focused time.Duration) {
+	if interrupted {
+		r.Interrupted++
+	} else {
+		r.Completed++
+	}
+	r.Focused += focused
+}
+
+// Compute builds the focus report for the todos of files.
+func Compute(files []service.FileTodos, opts Options) *Report {
+	r := &Report{From: opts.From, To: opts.To, By: opts.By}
+
+	bucket := func(day time.Time) time.Time {
+		if opts.By == Weekly {
+			return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+		}
+		return day
+	}
+
+	rows := make(map[time.Time]*Row)
+	for day := opts.From; !day.After(opts.To); day = day.AddDate(0, 0, 1) {
+		start := bucket(day)
+		if rows[start] == nil {
+			rows[start] = &Row{Start: start}
+			r.Rows = append(r.Rows, Row{Start: start})
+		}
+	}
+
+	for _, file := range files {
+		for _, todo := range file.Todos {
+			row := TodoRow{File: file.File, ID: todo.ID, Task: todo.Task}
+			for _, session := range todo.FocusSessions {
+				day := types.DayOf(session.Start)
+				if day.Before(opts.From) || day.After(opts.To) {
+					continue
+				}
+				rows[bucket(day)].add(session.Interrupted, session.Duration())
+				row.add(session.Interrupted, session.Duration())
+				r.Total.add(session.Interrupted, session.Duration())
+			}
+			if row.Completed+row.Interrupted > 0 {
+				r.Todos = append(r.Todos, row)
+			}
+		}
+	}
+
+	for i := range r.Rows {
+		r.Rows[i] = *rows[r.Rows[i].Start]
+	}
+	sort.Slice(r.Todos, func(i, j int) bool {
+		if r.Todos[i].Focused != r.Todos[j].Focused {
+			return r.Todos[i].Focused > r.Todos[j].Focused
+		}
+		return r.Todos[i].Task < r.Todos[j].Task
+	})
+	return r
+}
@@ -0,0 +1,98 @@
+package gitsync
+
+import (
+	"github.com/Ng1n3/go-todo/internal/config"
+	"github.com/Ng1n3/go-todo/internal/store"
+	"github.com/Ng1n3/go-todo/internal/types"
+)
+
+// MergeTodoFile merges two conflicting versions of a todo file per todo.
+// base is the common ancestor and any of the three may be nil when that side
+// doesn't have the file. For todos changed on both sides the version with the
+// later UpdatedAt wins. A todo deleted on one side is dropped unless the other
+// side changed it after the common ancestor. It returns nil when the file
+// should be deleted.
+func MergeTodoFile(file string, base, ours, theirs []byte, cfg *config.Config) ([]byte, error) {
+	decode := func(data []byte) (*store.Snapshot, error) {
+		if data == nil {
+			return nil, nil
+		}
+		snap, _, err := store.Decode(file, data, cfg)
+		return snap, err
+	}
+
+	baseSnap, err := decode(base)
+	if err != nil {
+		return nil, err
+	}
+	oursSnap, err := decode(ours)
+	if err != nil {
+		return nil, err
+	}
+	theirsSnap, err := decode(theirs)
+	if err != nil {
+		return nil, err
+	}
+
+	// A side that deleted the whole file counts as having deleted every todo.
+	result := oursSnap
+	if result == nil {
+		result = theirsSnap
+	}
+	if result == nil {
+		return nil, nil
+	}
+
+	todos := func(s *store.Snapshot) map[string]types.Todo {
+		if s == nil {
+			return map[string]types.Todo{}
+		}
+		return s.Todos
+	}
+	baseTodos, oursTodos, theirsTodos := todos(baseSnap), todos(oursSnap), todos(theirsSnap)
+
+	merged := make(map[string]types.Todo)
+	ids := make(map[string]bool)
+	for id := range oursTodos {
+		ids[id] = true
+	}
+	for id := range theirsTodos {
+		ids[id] = true
+	}
+
+	for id := range ids {
+		o, inOurs := oursTodos[id]
+		t, inTheirs := theirsTodos[id]
+		b, inBase := baseTodos[id]
+
+		switch {
+		case inOurs && inTheirs:
+			if t.UpdatedAt.After(o.UpdatedAt) {
+				merged[id] = t
+			} else {
+				merged[id] = o
+			}
+		case inOurs:
+			// Deleted by them; keep it only if we changed it since.
+			if !inBase || o.UpdatedAt.After(b.UpdatedAt) {
+				merged[id] = o
+			}
+		case inTheirs:
+			if !inBase || t.UpdatedAt.After(b.UpdatedAt) {
+				merged[id] = t
+			}
+		}
+	}
+
+	if oursSnap != nil && theirsSnap != nil {
+		if theirsSnap.Meta.CreatedAt.Before(result.Meta.CreatedAt) {
+			result.Meta.CreatedAt = theirsSnap.Meta.CreatedAt
+		}
+		if theirsSnap.Meta.UpdatedAt.After(result.Meta.UpdatedAt) {
+			result.Meta.UpdatedAt = theirsSnap.Meta.UpdatedAt
+		}
+	}
+
+	result.Todos = merged
+	return result.Encode()
+}
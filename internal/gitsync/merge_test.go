@@ -0,0 +1,193 @@
+package gitsync
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/Ng1n3/go-todo/internal/config"
+	"github.com/Ng1n3/go-todo/internal/store"
+	"github.com/Ng1n3/go-todo/internal/types"
+)
+
+var created = time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+
+func todo(id, task string, updated int) types.Todo {
+	return types.Todo{
+		ID: id, Task: task, Labels: []string{}, Priority: types.Low, Status: types.StatusTodo,
+		CreatedAt: created, UpdatedAt: created.Add(time.Duration(updated) * time.Hour),
+	}
+}
+
+// file encodes todos as a todo file.
+func file(t *testing.T, todos ...types.Todo) []byte {
+	t.Helper()
+	m := make(map[string]types.Todo, len(todos))
+	for _, todo := range todos {
+		m[todo.ID] = todo
+	}
+	data, err := json.Marshal(map[string]any{
+		"schema_version": store.SchemaVersion,
+		"meta":           map[string]any{"created_at": created, "updated_at": created},
+		"todos":          m,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// tasks decodes a todo file and returns "id:task" for each todo, sorted.
+func tasks(t *testing.T, data []byte) []string {
+	t.Helper()
+	snap, _, err := store.Decode("work.json", data, config.Default())
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := []string{}
+	for id, todo := range snap.Todos {
+		out = append(out, id+":"+todo.Task)
+	}
+	slices.Sort(out)
+	return out
+}
+
+func TestMergeTodoFile(t *testing.T) {
+	tests := []struct {
+		name               string
+		base, ours, theirs []types.Todo
+		want               []string
+	}{
+		{
+			name:   "both changed, theirs later",
+			base:   []types.Todo{todo("a", "plan", 0)},
+			ours:   []types.Todo{todo("a", "plan ours", 1)},
+			theirs: []types.Todo{todo("a", "plan theirs", 2)},
+			want:   []string{"a:plan theirs"},
+		},
+		{
+			name:   "both changed, ours later",
+			base:   []types.Todo{todo("a", "plan", 0)},
+			ours:   []types.Todo{todo("a", "plan ours", 3)},
+			theirs: []types.Todo{todo("a", "plan theirs", 2)},
+			want:   []string{"a:plan ours"},
+		},
+		{
+			name:   "added on both sides",
+			base:   []types.Todo{todo("a", "plan", 0)},
+			ours:   []types.Todo{todo("a", "plan", 0), todo("b", "ours", 1)},
+			theirs: []types.Todo{todo("a", "plan", 0), todo("c", "theirs", 1)},
+			want:   []string{"a:plan", "b:ours", "c:theirs"},
+		},
+		{
+			name:   "deleted by them",
+			base:   []types.Todo{todo("a", "plan", 0), todo("b", "old", 0)},
+			ours:   []types.Todo{todo("a", "plan", 0), todo("b", "old", 0)},
+			theirs: []types.Todo{todo("a", "plan", 0)},
+			want:   []string{"a:plan"},
+		},
+		{
+			name:   "deleted by them, changed by us",
+			base:   []types.Todo{todo("a", "plan", 0), todo("b", "old", 0)},
+			ours:   []types.Todo{todo("a", "plan", 0), todo("b", "still needed", 1)},
+			theirs: []types.Todo{todo("a", "plan", 0)},
+			want:   []string{"a:plan", "b:still needed"},
+		},
+		{
+			name:   "deleted by us, changed by them",
+			base:   []types.Todo{todo("a", "plan", 0), todo("b", "old", 0)},
+			ours:   []types.Todo{todo("a", "plan", 0)},
+			theirs: []types.Todo{todo("a", "plan", 0), todo("b", "still needed", 1)},
+			want:   []string{"a:plan", "b:still needed"},
+		},
+		{
+			name:   "created on both sides without a base",
+			ours:   []types.Todo{todo("a", "ours", 1)},
+			theirs: []types.Todo{todo("b", "theirs", 1)},
+			want:   []string{"a:ours", "b:theirs"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var baseFile []byte
+			if tt.base != nil {
+				baseFile = file(t, tt.base...)
+			}
+			merged, err := MergeTodoFile("work.json", baseFile, file(t, tt.ours...), file(t, tt.theirs...), config.Default())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := tasks(t, merged); !slices.Equal(got, tt.want) {
+				t.Errorf("merged todos = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSyncResolvesTodoConflicts(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	remote := filepath.Join(t.TempDir(), "remote.git")
+	gitIn(t, t.TempDir(), "init", "-q", "--bare", remote)
+	cfg := config.Default()
+
+	write := func(dir string, data []byte) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, "work.json"), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	read := func(dir string) []string {
+		t.Helper()
+		data, err := os.ReadFile(filepath.Join(dir, "work.json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tasks(t, data)
+	}
+	sync := func(dir string) Result {
+		t.Helper()
+		result, err := Open(dir).Sync("origin", remote, cfg)
+		if err != nil {
+			t.Fatalf("sync %s: %v", filepath.Base(dir), err)
+		}
+		return result
+	}
+
+	laptop, desktop := filepath.Join(t.TempDir(), "laptop"), filepath.Join(t.TempDir(), "desktop")
+	for _, dir := range []string{laptop, desktop} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write(laptop, file(t, todo("a", "plan", 0), todo("b", "call", 0)))
+	sync(laptop)
+	sync(desktop)
+	if got := read(desktop); !slices.Equal(got, []string{"a:plan", "b:call"}) {
+		t.Fatalf("desktop has %v after the first sync", got)
+	}
+
+	// Both change the same file.
+	write(laptop, file(t, todo("a", "plan sprint", 1), todo("b", "call", 0)))
+	write(desktop, file(t, todo("a", "plan", 0), todo("b", "call bank", 2), todo("c", "new", 2)))
+	sync(laptop)
+	result := sync(desktop)
+	if !result.Merged || !slices.Equal(result.Resolved, []string{"work.json"}) {
+		t.Errorf("desktop sync = %+v, want work.json resolved", result)
+	}
+
+	want := []string{"a:plan sprint", "b:call bank", "c:new"}
+	if got := read(desktop); !slices.Equal(got, want) {
+		t.Errorf("desktop has %v after merging, want %v", got, want)
+	}
+	sync(laptop)
+	if got := read(laptop); !slices.Equal(got, want) {
+		t.Errorf("laptop has %v after syncing the merge, want %v", got, want)
+	}
+}
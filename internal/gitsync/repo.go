@@ -0,0 +1,190 @@
+// Package gitsync keeps the storage directory in a git repository.
+//
+// With autocommit enabled every save of a todo file becomes a commit with a
+// message describing the change. Sync pulls from and pushes to a remote,
+// which may be a local path or any URL git understands. Merge conflicts in
+// todo files are not left as conflict markers: both versions are decoded and
+// merged per todo, keeping the most recently updated version of each.
+package gitsync
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// gitignore keeps internal state, the trash, backups and temporary files out
+// of the repository.
+const gitignore = ".state/\n.trash/\n*.bak\n.*.tmp-*\n"
+
+type Repo struct {
+	dir string
+	env []string
+}
+
+func Open(dir string) *Repo {
+	return &Repo{dir: dir}
+}
+
+// IsRepo reports whether the directory is the root of a git repository.
+func (r *Repo) IsRepo() bool {
+	_, err := os.Stat(filepath.Join(r.dir, ".git"))
+	return err == nil
+}
+
+// Init turns the directory into a git repository if it isn't one yet, and
+// makes sure the repository ignores internal state, also when it was created
+// by the user.
+func (r *Repo) Init() error {
+	_, err := r.init()
+	return err
+}
+
+// init is Init, also reporting whether .gitignore was changed.
+func (r *Repo) init() (bool, error) {
+	if _, err := exec.LookPath("git"); err != nil {
+		return false, fmt.Errorf("git is required for sync: %w", err)
+	}
+	if !r.IsRepo() {
+		if _, err := r.git("init", "-q"); err != nil {
+			return false, err
+		}
+	}
+
+	changed, err := r.ensureIgnored()
+	if err != nil {
+		return false, err
+	}
+	return changed, r.untrackIgnored()
+}
+
+// ensureIgnored appends the rules of gitignore that .gitignore lacks, and
+// reports whether it did.
+func (r *Repo) ensureIgnored() (bool, error) {
+	path := filepath.Join(r.dir, ".gitignore")
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return false, fmt.Errorf("failed to read .gitignore: %w", err)
+	}
+
+	present := map[string]bool{}
+	for _, line := range strings.Split(string(data), "\n") {
+		present[strings.TrimSpace(line)] = true
+	}
+	var missing []string
+	for _, rule := range strings.Fields(gitignore) {
+		if !present[rule] {
+			missing = append(missing, rule)
+		}
+	}
+	if len(missing) == 0 {
+		return false, nil
+	}
+
+	if len(data) > 0 && !bytes.HasSuffix(data, []byte("\n")) {
+		data = append(data, '\n')
+	}
+	data = append(data, strings.Join(missing, "\n")+"\n"...)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return false, fmt.Errorf("failed to write .gitignore: %w", err)
+	}
+	return true, nil
+}
+
+// untrackIgnored removes files matching the rules of gitignore from the
+// index, for repositories that committed them before the rules were added.
+// The files themselves are kept.
+func (r *Repo) untrackIgnored() error {
+	args := []string{"ls-files", "-z", "--cached", "--ignored"}
+	for _, rule := range strings.Fields(gitignore) {
+		args = append(args, "--exclude="+rule)
+	}
+	out, err := r.run(args...)
+	if err != nil {
+		return err
+	}
+
+	tracked := strings.Split(strings.TrimSuffix(string(out), "\x00"), "\x00")
+	if len(tracked) == 0 || tracked[0] == "" {
+		return nil
+	}
+	_, err = r.git(append([]string{"rm", "-q", "--cached", "--"}, tracked...)...)
+	return err
+}
+
+// Commit stages paths (relative to the repository) and commits them with
+// message. It reports whether a commit was created, which is not the case
+// when nothing changed.
+func (r *Repo) Commit(message string, paths ...string) (bool, error) {
+	ignoreChanged, err := r.init()
+	if err != nil {
+		return false, err
+	}
+	if ignoreChanged {
+		paths = append(paths, ".gitignore")
+	}
+
+	addArgs := append([]string{"add", "-A", "--"}, paths...)
+	if _, err := r.git(addArgs...); err != nil {
+		return false, err
+	}
+
+	if !r.hasStaged() {
+		return false, nil
+	}
+
+	if _, err := r.git("commit", "-q", "-m", message); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (r *Repo) hasStaged() bool {
+	_, err := r.git("diff", "--cached", "--quiet")
+	return err != nil
+}
+
+// git runs a git command in the repository and returns its trimmed stdout.
+func (r *Repo) git(args ...string) (string, error) {
+	out, err := r.run(args...)
+	return strings.TrimSpace(string(out)), err
+}
+
+func (r *Repo) run(args ...string) ([]byte, error) {
+	cmd := exec.Command("git", append([]string{"-C", r.dir}, args...)...)
+	cmd.Env = append(os.Environ(), r.identity()...)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = strings.TrimSpace(stdout.String())
+		}
+		return stdout.Bytes(), fmt.Errorf("git %s: %w: %s", args[0], err, msg)
+	}
+	return stdout.Bytes(), nil
+}
+
+// identity supplies a fallback author when the user hasn't configured one,
+// so that commits don't fail on fresh machines.
+func (r *Repo) identity() []string {
+	if r.env != nil {
+		return r.env
+	}
+
+	r.env = []string{}
+	out, err := exec.Command("git", "-C", r.dir, "config", "user.email").Output()
+	if err != nil || len(bytes.TrimSpace(out)) == 0 {
+		r.env = []string{
+			"GIT_AUTHOR_NAME=go-todo", "GIT_AUTHOR_EMAIL=go-todo@localhost",
+			"GIT_COMMITTER_NAME=go-todo", "GIT_COMMITTER_EMAIL=go-todo@localhost",
+		}
+	}
+	return r.env
+}
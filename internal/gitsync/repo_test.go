@@ -0,0 +1,119 @@
+package gitsync
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func gitIn(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@localhost",
+		"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@localhost",
+	)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s: %v: %s", strings.Join(args, " "), err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestCommitIgnoresStateInExistingRepo(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	tests := []struct {
+		name      string
+		gitignore string
+	}{
+		{name: "no gitignore"},
+		{name: "own gitignore", gitignore: "notes.txt"},
+		{name: "partial gitignore", gitignore: "*.bak\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			gitIn(t, dir, "init", "-q")
+			files := map[string]string{
+				"work.json":                        "{}",
+				"work.json.bak":                    "{}",
+				".state/search-index.json":         "{}",
+				".state/undo/work.json":            "{}",
+				".trash/old.json":                  "{}",
+				filepath.Join("sub", "x.json.bak"): "{}",
+			}
+			writeFiles(t, dir, files)
+			if tt.gitignore != "" {
+				writeFiles(t, dir, map[string]string{".gitignore": tt.gitignore})
+			}
+			// The user committed everything before turning on autocommit.
+			gitIn(t, dir, "add", "-A", "-f")
+			gitIn(t, dir, "commit", "-q", "-m", "initial")
+
+			writeFiles(t, dir, map[string]string{"work.json": `{"todos":[]}`})
+			committed, err := Open(dir).Commit("update work.json", "work.json")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !committed {
+				t.Fatal("Commit() = false, want a commit")
+			}
+
+			tracked := strings.Fields(gitIn(t, dir, "ls-files"))
+			want := []string{".gitignore", "work.json"}
+			if !slices.Equal(tracked, want) {
+				t.Errorf("tracked files = %v, want %v", tracked, want)
+			}
+			if status := gitIn(t, dir, "status", "--porcelain"); status != "" {
+				t.Errorf("uncommitted changes after Commit:\n%s", status)
+			}
+			for name := range files {
+				if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+					t.Errorf("%s was removed from disk: %v", name, err)
+				}
+			}
+
+			data, err := os.ReadFile(filepath.Join(dir, ".gitignore"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+			for _, rule := range strings.Fields(gitignore + tt.gitignore) {
+				if n := strings.Count("\n"+string(data), "\n"+rule+"\n"); n != 1 {
+					t.Errorf(".gitignore has %q %d times, want once:\n%s", rule, n, data)
+				}
+			}
+			if tt.gitignore != "" && lines[0] != strings.TrimSpace(strings.Split(tt.gitignore, "\n")[0]) {
+				t.Errorf(".gitignore doesn't start with the user's rules:\n%s", data)
+			}
+
+			// Nothing is left to do the second time.
+			committed, err = Open(dir).Commit("again", "work.json")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if committed {
+				t.Error("second Commit() = true, want nothing to commit")
+			}
+		})
+	}
+}
@@ -0,0 +1,180 @@
+package gitsync
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Ng1n3/go-todo/internal/config"
+	"github.com/Ng1n3/go-todo/internal/encryption"
+)
+
+// Result describes what a sync did.
+type Result struct {
+	Committed bool
+	Merged    bool
+	Resolved  []string
+	Pushed    bool
+}
+
+// Sync commits pending changes, merges the remote branch and pushes the
+// result. When url is not empty the remote is created or pointed at url.
+// Conflicting todo files are merged per todo; any other conflict aborts the
+// merge.
+func (r *Repo) Sync(remote, url string, cfg *config.Config) (Result, error) {
+	var result Result
+
+	if err := r.Init(); err != nil {
+		return result, err
+	}
+	if err := r.setRemote(remote, url); err != nil {
+		return result, err
+	}
+
+	committed, err := r.Commit("sync: commit local changes", ".")
+	if err != nil {
+		return result, err
+	}
+	result.Committed = committed
+
+	branch, err := r.git("symbolic-ref", "--short", "HEAD")
+	if err != nil {
+		return result, err
+	}
+
+	if _, err := r.git("fetch", "-q", remote); err != nil {
+		return result, err
+	}
+
+	upstream := remote + "/" + branch
+	if _, err := r.git("rev-parse", "--verify", "--quiet", upstream); err == nil {
+		resolved, err := r.merge(upstream, cfg)
+		if err != nil {
+			return result, err
+		}
+		result.Merged = true
+		result.Resolved = resolved
+	}
+
+	if _, err := r.git("rev-parse", "--verify", "--quiet", "HEAD"); err != nil {
+		// Nothing has been committed on either side yet.
+		return result, nil
+	}
+
+	if _, err := r.git("push", "-q", "-u", remote, branch); err != nil {
+		return result, err
+	}
+	result.Pushed = true
+	return result, nil
+}
+
+func (r *Repo) setRemote(remote, url string) error {
+	current, err := r.git("remote", "get-url", remote)
+	switch {
+	case url == "" && err != nil:
+		return fmt.Errorf("no git remote %q configured; pass the remote URL or path once to set it up", remote)
+	case url == "":
+		return nil
+	case err != nil:
+		_, err = r.git("remote", "add", remote, absIfPath(url))
+		return err
+	case current != url:
+		_, err = r.git("remote", "set-url", remote, absIfPath(url))
+		return err
+	}
+	return nil
+}
+
+// absIfPath makes local remote paths absolute, since git resolves them
+// relative to the repository rather than the current directory.
+func absIfPath(url string) string {
+	if strings.Contains(url, "://") || strings.Contains(url, "@") {
+		return url
+	}
+	if _, err := os.Stat(url); err == nil {
+		if abs, err := filepath.Abs(url); err == nil {
+			return abs
+		}
+	}
+	return url
+}
+
+// merge merges upstream into the current branch and resolves conflicts in
+// todo files. It returns the files it resolved.
+func (r *Repo) merge(upstream string, cfg *config.Config) ([]string, error) {
+	if _, err := r.git("merge", "-q", "--no-edit", "--allow-unrelated-histories", upstream); err == nil {
+		return nil, nil
+	}
+
+	out, err := r.git("diff", "--name-only", "--diff-filter=U")
+	if err != nil || out == "" {
+		r.git("merge", "--abort")
+		return nil, fmt.Errorf("merge of %s failed", upstream)
+	}
+
+	conflicts := strings.Split(out, "\n")
+	for _, path := range conflicts {
+		if !isTodoFile(path) {
+			r.git("merge", "--abort")
+			return nil, fmt.Errorf("merge conflict in %s must be resolved manually", path)
+		}
+	}
+
+	for _, path := range conflicts {
+		if err := r.resolve(path, cfg); err != nil {
+			r.git("merge", "--abort")
+			return nil, fmt.Errorf("failed to resolve conflict in %s: %w", path, err)
+		}
+	}
+
+	if _, err := r.git("commit", "-q", "--no-edit"); err != nil {
+		return nil, err
+	}
+	return conflicts, nil
+}
+
+func (r *Repo) resolve(path string, cfg *config.Config) error {
+	full := filepath.Join(r.dir, path)
+	base, ours, theirs := r.stage(1, path), r.stage(2, path), r.stage(3, path)
+
+	merged, err := MergeTodoFile(full, base, ours, theirs, cfg)
+	if err != nil {
+		return err
+	}
+
+	if merged == nil {
+		if err := os.Remove(full); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		_, err = r.git("rm", "-q", "--cached", "--", path)
+		return err
+	}
+
+	mode := cfg.FileMode
+	if encryption.IsEncrypted(merged) {
+		mode &^= 0077
+	}
+	if err := os.WriteFile(full, merged, mode); err != nil {
+		return err
+	}
+	_, err = r.git("add", "--", path)
+	return err
+}
+
+// stage returns the content of path at a merge stage (1 base, 2 ours,
+// 3 theirs), or nil if that side doesn't have the file.
+func (r *Repo) stage(n int, path string) []byte {
+	out, err := r.run("show", fmt.Sprintf(":%d:%s", n, path))
+	if err != nil {
+		return nil
+	}
+	return out
+}
+
+// isTodoFile reports whether a repository path is a todo file or the archive
+// of one, which are merged per todo.
+func isTodoFile(path string) bool {
+	path = strings.TrimPrefix(path, config.ArchiveDir+"/")
+	return !strings.Contains(path, "/") && strings.HasSuffix(path, ".json") && !strings.HasPrefix(path, ".")
+}
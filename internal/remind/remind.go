@@ -0,0 +1,253 @@
+// Package remind implements the reminder daemon for the go-todo application.
+//
+// The Daemon periodically scans every todo file in the storage directory and
+// fires reminders at configurable offsets before a todo's deadline, and once
+// more when the todo becomes overdue. Reminders are handed to one or more
+// Sinks (terminal, log file, external command). Every delivered reminder is
+// recorded in a state file so that restarting the daemon doesn't re-send it.
+package remind
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Ng1n3/go-todo/internal/config"
+	"github.com/Ng1n3/go-todo/internal/service"
+	"github.com/Ng1n3/go-todo/internal/types"
+	"github.com/Ng1n3/go-todo/internal/utils"
+)
+
+type Kind string
+
+const (
+	Upcoming Kind = "upcoming"
+	Overdue  Kind = "overdue"
+)
+
+// Reminder is a single notification about a todo. It is also the JSON
+// document written to the stdin of command sinks.
+type Reminder struct {
+	File     string         `json:"file"`
+	TodoID   string         `json:"todo_id"`
+	Task     string         `json:"task"`
+	Priority types.Priority `json:"priority"`
+	Labels   []string       `json:"labels"`
+	DueDate  time.Time      `json:"due_date"`
+	Kind     Kind           `json:"kind"`
+	Offset   string         `json:"offset,omitempty"`
+	FiredAt  time.Time      `json:"fired_at"`
+}
+
+// Message returns a one-line human readable description of the reminder.
+func (r Reminder) Message() string {
+	due := r.DueDate.Format("2006-01-02")
+	if r.Kind == Overdue {
+		return fmt.Sprintf("OVERDUE: %s [%s/%s] was due %s", r.Task, r.File, r.TodoID, due)
+	}
+	return fmt.Sprintf("Due within %s: %s [%s/%s] due %s", r.Offset, r.Task, r.File, r.TodoID, due)
+}
+
+// key identifies a reminder for de-duplication. The due date is part of the
+// key so that moving a todo's due date re-arms its reminders.
+func key(file, id string, due time.Time, kind Kind, offset time.Duration) string {
+	parts := []string{file, id, due.Format("2006-01-02"), string(kind)}
+	if kind == Upcoming {
+		parts = append(parts, offset.String())
+	}
+	return strings.Join(parts, "|")
+}
+
+type Options struct {
+	// Offsets before the deadline at which upcoming reminders fire.
+	Offsets []time.Duration
+	// Interval between two scans of the storage directory.
+	Interval time.Duration
+	Sinks    []Sink
+}
+
+// DefaultOffsets are used when no offsets are configured.
+var DefaultOffsets = []time.Duration{24 * time.Hour, time.Hour}
+
+type Daemon struct {
+	workspace *service.Workspace
+	state     *State
+	sinks     []Sink
+	offsets   []time.Duration
+	interval  time.Duration
+	now       func() time.Time
+}
+
+func NewDaemon(cfg *config.Config, opts Options) (*Daemon, error) {
+	if cfg == nil {
+		cfg = config.Default()
+	}
+	if len(opts.Sinks) == 0 {
+		return nil, fmt.Errorf("at least one notification sink is required")
+	}
+
+	offsets := opts.Offsets
+	if len(offsets) == 0 {
+		offsets = DefaultOffsets
+	}
+	offsets = append([]time.Duration(nil), offsets...)
+	for _, offset := range offsets {
+		if offset <= 0 {
+			return nil, fmt.Errorf("reminder offset must be positive: %s", offset)
+		}
+	}
+	// Smallest offset first, so the most specific reminder wins.
+	sort.Slice(offsets, func(i, j int) bool { return offsets[i] < offsets[j] })
+
+	interval := opts.Interval
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	state, err := LoadState(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Daemon{
+		workspace: service.NewWorkspace(cfg),
+		state:     state,
+		sinks:     opts.Sinks,
+		offsets:   offsets,
+		interval:  interval,
+		now:       time.Now,
+	}, nil
+}
+
+// Run checks for due reminders every interval until ctx is cancelled.
+// Errors from a single pass are reported through onError and don't stop the
+// daemon.
+func (d *Daemon) Run(ctx context.Context, onError func(error)) error {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := d.Check(); err != nil && onError != nil {
+			onError(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check performs a single pass over all todo files, delivers every reminder
+// that is due and not yet sent, and returns how many were delivered.
+func (d *Daemon) Check() (int, error) {
+	files, err := d.workspace.LoadAll()
+	if err != nil {
+		return 0, err
+	}
+
+	now := d.now()
+	var errs []string
+	sent := 0
+	open := make(map[string]bool)
+
+	for _, file := range files {
+		for _, todo := range file.Todos {
+			if !todo.Completed && todo.HasDueDate() {
+				open[file.File+"|"+todo.ID] = true
+			}
+
+			reminder, keys := d.due(file.File, todo, now)
+			if reminder == nil {
+				continue
+			}
+
+			reminder.FiredAt = now
+			if err := d.deliver(*reminder); err != nil {
+				errs = append(errs, err.Error())
+				continue
+			}
+
+			// Record the delivered reminder along with every wider offset that
+			// was already due, so they are not sent late after this one.
+			for _, k := range keys {
+				d.state.Mark(k, file.File+"|"+todo.ID, todo.DueDate, now)
+			}
+			sent++
+		}
+	}
+
+	d.state.Prune(open)
+	if err := d.state.Save(); err != nil {
+		errs = append(errs, err.Error())
+	}
+
+	if len(errs) > 0 {
+		return sent, fmt.Errorf("reminder errors: %s", strings.Join(errs, "; "))
+	}
+	return sent, nil
+}
+
+// due returns the reminder that should be delivered for todo at now, if any,
+// together with the state keys to record once it has been delivered.
+func (d *Daemon) due(file string, todo types.Todo, now time.Time) (*Reminder, []string) {
+	if todo.Completed || !todo.HasDueDate() {
+		return nil, nil
+	}
+
+	deadline := todo.Deadline()
+	reminder := &Reminder{
+		File:     file,
+		TodoID:   todo.ID,
+		Task:     todo.Task,
+		Priority: todo.Priority,
+		Labels:   todo.Labels,
+		DueDate:  todo.DueDate,
+	}
+
+	var keys []string
+	current := ""
+	for _, offset := range d.offsets {
+		if now.Before(deadline.Add(-offset)) {
+			continue
+		}
+		k := key(file, todo.ID, todo.DueDate, Upcoming, offset)
+		keys = append(keys, k)
+		if current == "" && now.Before(deadline) {
+			current = k
+			reminder.Kind = Upcoming
+			reminder.Offset = utils.FormatDuration(offset)
+		}
+	}
+
+	if todo.IsOverdue(now) {
+		current = key(file, todo.ID, todo.DueDate, Overdue, 0)
+		keys = append(keys, current)
+		reminder.Kind = Overdue
+		reminder.Offset = ""
+	}
+
+	if current == "" || d.state.Delivered(current) {
+		return nil, nil
+	}
+	return reminder, keys
+}
+
+// deliver hands the reminder to every sink. It succeeds if at least one sink
+// accepted the reminder.
+func (d *Daemon) deliver(reminder Reminder) error {
+	var errs []string
+	for _, sink := range d.sinks {
+		if err := sink.Notify(reminder); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+
+	if len(errs) == len(d.sinks) {
+		return fmt.Errorf("failed to deliver reminder for %s/%s: %s", reminder.File, reminder.TodoID, strings.Join(errs, "; "))
+	}
+	return nil
+}
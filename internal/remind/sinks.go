@@ -0,0 +1,121 @@
+package remind
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// Sink receives reminders from the daemon.
+type Sink interface {
+	Notify(reminder Reminder) error
+}
+
+// TerminalSink prints reminders to a terminal, ringing the bell.
+type TerminalSink struct {
+	w io.Writer
+}
+
+func NewTerminalSink(w io.Writer) *TerminalSink {
+	if w == nil {
+		w = os.Stdout
+	}
+	return &TerminalSink{w: w}
+}
+
+func (s *TerminalSink) Notify(reminder Reminder) error {
+	_, err := fmt.Fprintf(s.w, "\a[%s] %s\n", reminder.FiredAt.Format("2006-01-02 15:04"), reminder.Message())
+	return err
+}
+
+// LogSink appends reminders to a log file, one line per reminder.
+type LogSink struct {
+	path string
+	mode os.FileMode
+}
+
+func NewLogSink(path string, mode os.FileMode) *LogSink {
+	return &LogSink{path: path, mode: mode}
+}
+
+func (s *LogSink) Notify(reminder Reminder) error {
+	f, err := os.OpenFile(s.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, s.mode)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+	defer f.Close()
+
+	line := fmt.Sprintf("%s %s %s\n", reminder.FiredAt.Format(time.RFC3339), strings.ToUpper(string(reminder.Kind)), reminder.Message())
+	if _, err := f.WriteString(line); err != nil {
+		return fmt.Errorf("failed to write log file: %w", err)
+	}
+	return nil
+}
+
+// CommandSink runs an external command for every reminder and writes the
+// reminder as JSON to its stdin.
+type CommandSink struct {
+	name    string
+	args    []string
+	timeout time.Duration
+}
+
+func NewCommandSink(command string) (*CommandSink, error) {
+	fields := strings.Fields(command)
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("command sink requires a command")
+	}
+	return &CommandSink{name: fields[0], args: fields[1:], timeout: 30 * time.Second}, nil
+}
+
+func (s *CommandSink) Notify(reminder Reminder) error {
+	data, err := json.Marshal(reminder)
+	if err != nil {
+		return fmt.Errorf("failed to marshal reminder: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, s.name, s.args...)
+	cmd.Stdin = bytes.NewReader(data)
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg != "" {
+			return fmt.Errorf("command %s failed: %w: %s", s.name, err, msg)
+		}
+		return fmt.Errorf("command %s failed: %w", s.name, err)
+	}
+	return nil
+}
+
+// ParseSink builds a sink from a command-line specification:
+//
+//	terminal          print to stdout
+//	log:<path>        append to a log file
+//	exec:<command>    run a command with the reminder as JSON on stdin
+func ParseSink(spec string, mode os.FileMode) (Sink, error) {
+	kind, arg, _ := strings.Cut(strings.TrimSpace(spec), ":")
+	switch kind {
+	case "terminal", "":
+		return NewTerminalSink(os.Stdout), nil
+	case "log":
+		if arg == "" {
+			return nil, fmt.Errorf("log sink requires a path, e.g. log:reminders.log")
+		}
+		return NewLogSink(arg, mode), nil
+	case "exec":
+		return NewCommandSink(arg)
+	default:
+		return nil, fmt.Errorf("unknown sink %q: use terminal, log:<path> or exec:<command>", kind)
+	}
+}
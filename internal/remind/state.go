@@ -0,0 +1,92 @@
+package remind
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/Ng1n3/go-todo/internal/config"
+	"github.com/Ng1n3/go-todo/internal/store"
+)
+
+type delivery struct {
+	Todo    string    `json:"todo"`
+	DueDate time.Time `json:"due_date"`
+	FiredAt time.Time `json:"fired_at"`
+}
+
+// State records which reminders have already been delivered.
+type State struct {
+	path      string
+	mode      os.FileMode
+	delivered map[string]delivery
+}
+
+// LoadState reads the reminder state file, returning an empty state if it
+// doesn't exist yet.
+func LoadState(cfg *config.Config) (*State, error) {
+	s := &State{
+		path:      cfg.GetStatePath("reminders.json"),
+		mode:      cfg.FileMode,
+		delivered: make(map[string]delivery),
+	}
+
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+		return nil, fmt.Errorf("failed to read reminder state: %w", err)
+	}
+
+	if len(data) == 0 {
+		return s, nil
+	}
+
+	if err := json.Unmarshal(data, &s.delivered); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal reminder state: %w", err)
+	}
+	return s, nil
+}
+
+func (s *State) Delivered(key string) bool {
+	_, ok := s.delivered[key]
+	return ok
+}
+
+// Mark records key as delivered for todo, identified as "<file>|<id>".
+func (s *State) Mark(key, todo string, due, firedAt time.Time) {
+	if _, ok := s.delivered[key]; ok {
+		return
+	}
+	s.delivered[key] = delivery{Todo: todo, DueDate: due, FiredAt: firedAt}
+}
+
+// Prune forgets reminders for todos that are no longer open, i.e. that were
+// completed, deleted or had their due date removed. open holds the
+// "<file>|<id>" of every open todo with a due date.
+func (s *State) Prune(open map[string]bool) {
+	for key, d := range s.delivered {
+		if !open[d.Todo] {
+			delete(s.delivered, key)
+		}
+	}
+}
+
+func (s *State) Save() error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(s.delivered, "", " ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal reminder state: %w", err)
+	}
+
+	if err := store.WriteFileAtomic(s.path, data, s.mode); err != nil {
+		return fmt.Errorf("failed to write reminder state: %w", err)
+	}
+	return nil
+}
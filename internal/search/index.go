@@ -0,0 +1,242 @@
+// Package search provides full-text search across every todo file in the
+// storage directory.
+//
+// It maintains an inverted index from normalized tokens to the todos that
+// contain them. The index is persisted in the state directory and refreshed
+// incrementally: only files whose size or modification time changed since
+// the last run are re-read and re-tokenized.
+package search
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/Ng1n3/go-todo/internal/config"
+	"github.com/Ng1n3/go-todo/internal/service"
+	"github.com/Ng1n3/go-todo/internal/store"
+	"github.com/Ng1n3/go-todo/internal/types"
+)
+
+// indexVersion is bumped whenever the indexed fields or the on-disk layout
+// change, which forces a full rebuild.
+const indexVersion = 2
+
+// Weights of a token occurrence per field.
+const (
+	taskWeight  = 1.0
+	labelWeight = 2.0
+	noteWeight  = 0.5
+	linkWeight  = 0.5
+)
+
+// fileEntry is the indexed content of one todo file.
+type fileEntry struct {
+	ModTime time.Time             `json:"mod_time"`
+	Size    int64                 `json:"size"`
+	Todos   map[string]types.Todo `json:"todos"`
+	// Terms maps a token to the weighted frequency per todo ID.
+	Terms map[string]map[string]float64 `json:"terms"`
+	// encrypted entries are searchable in memory but never written to disk,
+	// so the index doesn't leak the contents of encrypted files.
+	encrypted bool
+}
+
+type Index struct {
+	Version int                   `json:"version"`
+	Files   map[string]*fileEntry `json:"files"`
+
+	path      string
+	mode      os.FileMode
+	workspace *service.Workspace
+	// terms is the sorted list of every indexed token, used for prefix lookups.
+	terms []string
+	// postings maps a token to the weighted frequency per "<file>|<id>".
+	postings map[string]map[string]float64
+}
+
+// Open loads the persisted index, or starts an empty one if none exists or
+// it was written by an incompatible version.
+func Open(cfg *config.Config) (*Index, error) {
+	if cfg == nil {
+		cfg = config.Default()
+	}
+
+	idx := &Index{
+		path:      cfg.GetStatePath("search-index.json"),
+		mode:      cfg.FileMode,
+		workspace: service.NewWorkspace(cfg),
+	}
+
+	data, err := os.ReadFile(idx.path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read search index: %w", err)
+	}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, idx); err != nil || idx.Version != indexVersion {
+			idx.Files = nil
+		}
+	}
+
+	idx.Version = indexVersion
+	if idx.Files == nil {
+		idx.Files = make(map[string]*fileEntry)
+	}
+	return idx, nil
+}
+
+// Refresh re-indexes every todo file and archive that changed since it was
+// last indexed and drops files that no longer exist. It reports whether
+// anything changed.
+func (idx *Index) Refresh() (bool, error) {
+	files, err := idx.workspace.Files()
+	if err != nil {
+		return false, err
+	}
+	archives, err := idx.workspace.ArchiveFiles()
+	if err != nil {
+		return false, err
+	}
+	files = append(files, archives...)
+
+	changed := false
+	seen := make(map[string]bool, len(files))
+	for _, name := range files {
+		seen[name] = true
+
+		info, err := os.Stat(idx.workspace.Config().GetFullPath(name))
+		if err != nil {
+			return false, fmt.Errorf("failed to stat %s: %w", name, err)
+		}
+
+		if entry, ok := idx.Files[name]; ok && entry.ModTime.Equal(info.ModTime()) && entry.Size == info.Size() {
+			continue
+		}
+
+		ts, err := idx.workspace.Open(name)
+		if err != nil {
+			return false, fmt.Errorf("failed to index %s: %w", name, err)
+		}
+		entry := buildEntry(ts.ListTodos(), info)
+		entry.encrypted = ts.Encrypted()
+		idx.Files[name] = entry
+		changed = true
+	}
+
+	for name := range idx.Files {
+		if !seen[name] {
+			delete(idx.Files, name)
+			changed = true
+		}
+	}
+
+	idx.postings = nil
+	return changed, nil
+}
+
+// Rebuild discards the index and indexes every file from scratch.
+func (idx *Index) Rebuild() error {
+	idx.Files = make(map[string]*fileEntry)
+	_, err := idx.Refresh()
+	return err
+}
+
+// Save writes the index to the state directory.
+func (idx *Index) Save() error {
+	if err := os.MkdirAll(filepath.Dir(idx.path), 0755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	persisted := *idx
+	persisted.Files = make(map[string]*fileEntry, len(idx.Files))
+	for name, entry := range idx.Files {
+		if !entry.encrypted {
+			persisted.Files[name] = entry
+		}
+	}
+
+	data, err := json.Marshal(&persisted)
+	if err != nil {
+		return fmt.Errorf("failed to marshal search index: %w", err)
+	}
+
+	if err := store.WriteFileAtomic(idx.path, data, idx.mode); err != nil {
+		return fmt.Errorf("failed to write search index: %w", err)
+	}
+	return nil
+}
+
+// Count returns the number of indexed todos.
+func (idx *Index) Count() int {
+	n := 0
+	for _, entry := range idx.Files {
+		n += len(entry.Todos)
+	}
+	return n
+}
+
+func buildEntry(todos []types.Todo, info os.FileInfo) *fileEntry {
+	entry := &fileEntry{
+		ModTime: info.ModTime(),
+		Size:    info.Size(),
+		Todos:   make(map[string]types.Todo, len(todos)),
+		Terms:   make(map[string]map[string]float64),
+	}
+
+	add := func(id, text string, weight float64) {
+		for _, token := range Tokenize(text) {
+			if entry.Terms[token] == nil {
+				entry.Terms[token] = make(map[string]float64)
+			}
+			entry.Terms[token][id] += weight
+		}
+	}
+
+	for _, todo := range todos {
+		entry.Todos[todo.ID] = todo
+		add(todo.ID, todo.Task, taskWeight)
+		for _, label := range todo.Labels {
+			add(todo.ID, label, labelWeight)
+		}
+		add(todo.ID, todo.Notes, noteWeight)
+		for _, link := range todo.Links {
+			add(todo.ID, link.Title, linkWeight)
+			add(todo.ID, link.Target, linkWeight)
+		}
+	}
+	return entry
+}
+
+// buildPostings merges the per-file terms into the global posting lists.
+func (idx *Index) buildPostings() {
+	idx.postings = make(map[string]map[string]float64)
+	for name, entry := range idx.Files {
+		for token, docs := range entry.Terms {
+			if idx.postings[token] == nil {
+				idx.postings[token] = make(map[string]float64)
+			}
+			for id, weight := range docs {
+				idx.postings[token][name+"|"+id] = weight
+			}
+		}
+	}
+
+	idx.terms = make([]string, 0, len(idx.postings))
+	for token := range idx.postings {
+		idx.terms = append(idx.terms, token)
+	}
+	sort.Strings(idx.terms)
+}
+
+// Tokenize splits text into lower-cased tokens on any character that is not
+// a letter or a digit.
+func Tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
@@ -0,0 +1,102 @@
+package search
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/Ng1n3/go-todo/internal/types"
+)
+
+// prefixPenalty scales the score of a token that only matches a query term
+// as a prefix, so exact matches rank first.
+const prefixPenalty = 0.5
+
+// Hit is a todo matching a query.
+type Hit struct {
+	File  string
+	Todo  types.Todo
+	Score float64
+}
+
+// Search returns the todos matching every term of query, best matches first.
+// Each query term matches indexed tokens exactly or as a prefix. A limit of
+// zero or less returns all hits.
+func (idx *Index) Search(query string, limit int) []Hit {
+	terms := Tokenize(query)
+	if len(terms) == 0 {
+		return nil
+	}
+
+	if idx.postings == nil {
+		idx.buildPostings()
+	}
+	total := float64(idx.Count())
+
+	var scores map[string]float64
+	for _, term := range terms {
+		termScores := make(map[string]float64)
+		for _, token := range idx.expand(term) {
+			docs := idx.postings[token]
+			idf := math.Log(1 + total/float64(len(docs)))
+			factor := 1.0
+			if token != term {
+				factor = prefixPenalty
+			}
+			for doc, weight := range docs {
+				score := weight * idf * factor
+				if score > termScores[doc] {
+					termScores[doc] = score
+				}
+			}
+		}
+
+		// Every term must match: intersect with the documents found so far.
+		if scores == nil {
+			scores = termScores
+			continue
+		}
+		for doc, score := range scores {
+			if extra, ok := termScores[doc]; ok {
+				scores[doc] = score + extra
+			} else {
+				delete(scores, doc)
+			}
+		}
+	}
+
+	hits := make([]Hit, 0, len(scores))
+	for doc, score := range scores {
+		file, id, _ := strings.Cut(doc, "|")
+		entry, ok := idx.Files[file]
+		if !ok {
+			continue
+		}
+		hits = append(hits, Hit{File: file, Todo: entry.Todos[id], Score: score})
+	}
+
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		if hits[i].File != hits[j].File {
+			return hits[i].File < hits[j].File
+		}
+		return hits[i].Todo.ID < hits[j].Todo.ID
+	})
+
+	if limit > 0 && len(hits) > limit {
+		hits = hits[:limit]
+	}
+	return hits
+}
+
+// expand returns every indexed token that starts with term.
+func (idx *Index) expand(term string) []string {
+	start := sort.SearchStrings(idx.terms, term)
+	var tokens []string
+	for i := start; i < len(idx.terms) && strings.HasPrefix(idx.terms[i], term); i++ {
+		tokens = append(tokens, idx.terms[i])
+	}
+	return tokens
+}
@@ -0,0 +1,151 @@
+package search
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/Ng1n3/go-todo/internal/config"
+	"github.com/Ng1n3/go-todo/internal/service"
+	"github.com/Ng1n3/go-todo/internal/types"
+)
+
+func testConfig(t *testing.T) *config.Config {
+	t.Helper()
+	dir := t.TempDir()
+	cfg := config.Default()
+	cfg.StorageDir = filepath.Join(dir, "storage")
+	cfg.SummaryFile = filepath.Join(dir, "summary.json")
+	if err := os.MkdirAll(cfg.StorageDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	return cfg
+}
+
+// addTodos adds todos with the given tasks and labels to a todo file and
+// returns their IDs by task.
+func addTodos(t *testing.T, cfg *config.Config, name string, todos map[string]string) map[string]string {
+	t.Helper()
+	ts, err := service.NewWorkspace(cfg).Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := make(map[string]string, len(todos))
+	for task, labels := range todos {
+		todo, err := ts.CreateTodo(task, "2026-03-05", "no", types.Medium, labels, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids[task] = todo.ID
+	}
+	if err := ts.Save(); err != nil {
+		t.Fatal(err)
+	}
+	return ids
+}
+
+func tasksOf(hits []Hit) []string {
+	tasks := make([]string, len(hits))
+	for i, hit := range hits {
+		tasks[i] = hit.Todo.Task
+	}
+	return tasks
+}
+
+func TestSearch(t *testing.T) {
+	cfg := testConfig(t)
+	addTodos(t, cfg, "work.json", map[string]string{
+		"write quarterly report": "",
+		"fix the server":         "deploy",
+		"deploy script cleanup":  "",
+	})
+	ids := addTodos(t, cfg, "home.json", map[string]string{
+		"rep meeting":        "",
+		"old report to file": "",
+	})
+
+	ts, err := service.NewWorkspace(cfg).Open("home.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ts.Archive([]string{ids["old report to file"]}); err != nil {
+		t.Fatal(err)
+	}
+
+	idx, err := Open(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := idx.Refresh(); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name, query string
+		want        []string
+	}{
+		{name: "every term must match", query: "quarterly REPORT", want: []string{"write quarterly report"}},
+		{name: "labels weigh more than tasks", query: "deploy", want: []string{"fix the server", "deploy script cleanup"}},
+		{name: "exact before prefix, archives included", query: "rep", want: []string{"rep meeting", "old report to file", "write quarterly report"}},
+		{name: "prefix", query: "clean", want: []string{"deploy script cleanup"}},
+		{name: "no match", query: "holiday", want: []string{}},
+		{name: "no terms", query: " - ", want: []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tasksOf(idx.Search(tt.query, 0)); !slices.Equal(got, tt.want) {
+				t.Errorf("Search(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+
+	if got := idx.Search("rep", 1); len(got) != 1 || got[0].File != "home.json" {
+		t.Errorf("Search(\"rep\", 1) = %v, want the best hit only", got)
+	}
+	if got := idx.Search("old report", 0); len(got) != 1 || got[0].File != filepath.Join("archive", "home.json") {
+		t.Errorf("Search(\"old report\") = %v, want the hit in the archive", got)
+	}
+}
+
+func TestRefreshIsIncremental(t *testing.T) {
+	cfg := testConfig(t)
+	addTodos(t, cfg, "work.json", map[string]string{"write report": ""})
+	addTodos(t, cfg, "home.json", map[string]string{"water plants": ""})
+
+	idx, err := Open(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changed, err := idx.Refresh(); err != nil || !changed {
+		t.Fatalf("first Refresh() = %v, %v, want changes", changed, err)
+	}
+	if err := idx.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	idx, err = Open(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changed, err := idx.Refresh(); err != nil || changed {
+		t.Errorf("Refresh() of the saved index = %v, %v, want nothing to do", changed, err)
+	}
+	if idx.Count() != 2 {
+		t.Errorf("Count() = %d, want 2", idx.Count())
+	}
+
+	addTodos(t, cfg, "work.json", map[string]string{"review report": ""})
+	if err := os.Remove(cfg.GetFullPath("home.json")); err != nil {
+		t.Fatal(err)
+	}
+	if changed, err := idx.Refresh(); err != nil || !changed {
+		t.Fatalf("Refresh() after changes = %v, %v, want changes", changed, err)
+	}
+	if got := tasksOf(idx.Search("report", 0)); len(got) != 2 {
+		t.Errorf("Search(\"report\") = %v, want both todos of the changed file", got)
+	}
+	if got := idx.Search("plants", 0); len(got) != 0 {
+		t.Errorf("Search(\"plants\") = %v, want nothing from the removed file", tasksOf(got))
+	}
+}
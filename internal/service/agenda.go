@@ -0,0 +1,153 @@
+package service
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Ng1n3/go-todo/internal/types"
+)
+
+// Agenda group names, in the order Agenda returns them.
+const (
+	GroupOverdue  = "Overdue"
+	GroupToday    = "Today"
+	GroupTomorrow = "Tomorrow"
+	GroupThisWeek = "This week"
+	GroupLater    = "Later"
+	GroupNoDate   = "No date"
+)
+
+// Entry is a todo together with the file it belongs to.
+type Entry struct {
+	File string
+	Todo types.Todo
+}
+
+type AgendaGroup struct {
+	Name    string
+	Entries []Entry
+}
+
+// Agenda groups the todos of files matching filter by when they are due,
+// relative to now. Weeks end on Sunday. Unless the filter asks for done
+// todos, only open todos are included. Every group is returned, empty or not.
+func Agenda(files []FileTodos, filter Filter, now time.Time) []AgendaGroup {
+	filter = openByDefault(filter)
+
+	today := types.DayOf(now)
+	tomorrow := today.AddDate(0, 0, 1)
+	weekEnd := today.AddDate(0, 0, 7-(int(today.Weekday())+6)%7)
+
+	groups := []AgendaGroup{
+		{Name: GroupOverdue}, {Name: GroupToday}, {Name: GroupTomorrow},
+		{Name: GroupThisWeek}, {Name: GroupLater}, {Name: GroupNoDate},
+	}
+
+	for _, entry := range matching(files, filter, now) {
+		todo := entry.Todo
+		due := dateOf(todo.DueDate)
+
+		var i int
+		switch {
+		case !todo.HasDueDate():
+			i = 5
+		case todo.IsOverdue(now):
+			i = 0
+		case !due.After(today):
+			i = 1
+		case due.Equal(tomorrow):
+			i = 2
+		case due.Before(weekEnd):
+			i = 3
+		default:
+			i = 4
+		}
+		groups[i].Entries = append(groups[i].Entries, entry)
+	}
+
+	for _, group := range groups {
+		sortEntries(group.Entries)
+	}
+	return groups
+}
+
+// CalendarDay holds the todos due on one day of a calendar month.
+type CalendarDay struct {
+	Date    time.Time
+	Entries []Entry
+	// Highest is the highest priority among Entries, or "" if there are none.
+	Highest types.Priority
+}
+
+// Calendar returns one CalendarDay for every day of the month containing
+// month, with the todos of files matching filter that are due on it. Unless
+// the filter asks for done todos, only open todos are included.
+func Calendar(files []FileTodos, filter Filter, month, now time.Time) []CalendarDay {
+	filter = openByDefault(filter)
+
+	first := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, time.UTC)
+	days := make([]CalendarDay, first.AddDate(0, 1, -1).Day())
+	for i := range days {
+		days[i].Date = first.AddDate(0, 0, i)
+	}
+
+	for _, entry := range matching(files, filter, now) {
+		due := dateOf(entry.Todo.DueDate)
+		if !entry.Todo.HasDueDate() || due.Year() != first.Year() || due.Month() != first.Month() {
+			continue
+		}
+
+		day := &days[due.Day()-1]
+		day.Entries = append(day.Entries, entry)
+		if entry.Todo.Priority.Rank() > day.Highest.Rank() {
+			day.Highest = entry.Todo.Priority.Normalize()
+		}
+	}
+
+	for _, day := range days {
+		sortEntries(day.Entries)
+	}
+	return days
+}
+
+func matching(files []FileTodos, filter Filter, now time.Time) []Entry {
+	var entries []Entry
+	for _, file := range files {
+		for _, todo := range filter.Apply(file.Todos, now) {
+			entries = append(entries, Entry{File: file.File, Todo: todo})
+		}
+	}
+	return entries
+}
+
+// openByDefault restricts a filter to open todos unless it already selects
+// by completion.
+func openByDefault(filter Filter) Filter {
+	if filter.Completed == nil {
+		open := false
+		filter.Completed = &open
+	}
+	return filter
+}
+
+// sortEntries orders entries by due date, then highest priority first, then
+// task.
+func sortEntries(entries []Entry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i].Todo, entries[j].Todo
+		if !a.DueDate.Equal(b.DueDate) {
+			return a.DueDate.Before(b.DueDate)
+		}
+		if a.Priority.Rank() != b.Priority.Rank() {
+			return a.Priority.Rank() > b.Priority.Rank()
+		}
+		return strings.ToLower(a.Task) < strings.ToLower(b.Task)
+	})
+}
+
+// dateOf returns the calendar day of t as midnight UTC, the form due dates
+// are stored in.
+func dateOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
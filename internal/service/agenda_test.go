@@ -0,0 +1,61 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Ng1n3/go-todo/internal/types"
+)
+
+func TestAgendaUsesLocalDays(t *testing.T) {
+	saved := time.Local
+	time.Local = time.FixedZone("UTC-5", -5*60*60)
+	t.Cleanup(func() { time.Local = saved })
+
+	day := func(d int) time.Time { return time.Date(2026, 3, d, 0, 0, 0, 0, time.UTC) }
+	files := []FileTodos{{File: "work.json", Todos: []types.Todo{
+		{ID: "yester", Task: "yesterday", DueDate: day(4)},
+		{ID: "today", Task: "today", DueDate: day(5)},
+		{ID: "tomorr", Task: "tomorrow", DueDate: day(6)},
+	}}}
+	// 21:00 on March 5th locally, already March 6th in UTC.
+	now := time.Date(2026, 3, 5, 21, 0, 0, 0, time.Local)
+
+	want := map[string]string{"yester": GroupOverdue, "today": GroupToday, "tomorr": GroupTomorrow}
+	for _, group := range Agenda(files, Filter{}, now) {
+		for _, entry := range group.Entries {
+			if want[entry.Todo.ID] != group.Name {
+				t.Errorf("%s is in %q, want %q", entry.Todo.ID, group.Name, want[entry.Todo.ID])
+			}
+			delete(want, entry.Todo.ID)
+		}
+	}
+	if len(want) > 0 {
+		t.Errorf("missing from the agenda: %v", want)
+	}
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{query: "due:today", want: []string{"today"}},
+		{query: "due:tomorrow", want: []string{"tomorr"}},
+		{query: "due:+1d", want: []string{"tomorr"}},
+		{query: "due:overdue", want: []string{"yester"}},
+	}
+	for _, tt := range tests {
+		filter, err := parseFilter(tt.query, now)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.query, err)
+		}
+		var got []string
+		for _, todo := range files[0].Todos {
+			if filter.Match(todo, now) {
+				got = append(got, todo.ID)
+			}
+		}
+		if len(got) != len(tt.want) || (len(got) > 0 && got[0] != tt.want[0]) {
+			t.Errorf("%s matched %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/Ng1n3/go-todo/internal/config"
+	"github.com/Ng1n3/go-todo/internal/errors"
+)
+
+// IsArchive reports whether the service holds the archive of a todo file
+// rather than the todo file itself.
+func (ts *TodoService) IsArchive() bool {
+	archiveDir := filepath.Join(ts.config.StorageDir, config.ArchiveDir)
+	return filepath.Clean(filepath.Dir(ts.storage.File())) == filepath.Clean(archiveDir)
+}
+
+// OpenArchive returns a TodoService for the archive of the todo file. The
+// archive is created on its first save; when the todo file is encrypted, a
+// new archive is encrypted with the same key.
+func (ts *TodoService) OpenArchive() (*TodoService, error) {
+	if ts.IsArchive() {
+		return nil, fmt.Errorf("%w: %s is already an archive", errors.ErrInvalidInput, ts.File())
+	}
+
+	path := ts.config.GetArchivePath(ts.storage.File())
+	_, statErr := os.Stat(path)
+
+	archive, err := NewTodoService(path, ts.config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open archive of %s: %w", ts.File(), err)
+	}
+	if os.IsNotExist(statErr) && ts.Encrypted() {
+		archive.storage.ShareKey(ts.storage)
+	}
+	return archive, nil
+}
+
+// Archive moves the todos with the given IDs into the archive of the file.
+func (ts *TodoService) Archive(ids []string) ([]TransferResult, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	archive, err := ts.OpenArchive()
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(archive.storage.File()), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create archive directory: %w", err)
+	}
+	return MoveTodos(ts, archive, ids, ConflictOverwrite)
+}
+
+// ArchiveCompleted archives every todo that was completed at least age
+// before now.
+func (ts *TodoService) ArchiveCompleted(age time.Duration, now time.Time) ([]TransferResult, error) {
+	cutoff := now.Add(-age)
+
+	var ids []string
+	for _, todo := range ts.ListTodos() {
+		if !todo.Completed {
+			continue
+		}
+		completedAt := todo.CompletedAt
+		if completedAt.IsZero() {
+			completedAt = todo.UpdatedAt
+		}
+		if !completedAt.After(cutoff) {
+			ids = append(ids, todo.ID)
+		}
+	}
+	return ts.Archive(ids)
+}
+
+// AutoArchive applies the archive.after setting: completed todos older than
+// it are archived. It does nothing when the setting is zero.
+func (ts *TodoService) AutoArchive() ([]TransferResult, error) {
+	if ts.config.ArchiveAfter <= 0 || ts.IsArchive() {
+		return nil, nil
+	}
+	return ts.ArchiveCompleted(ts.config.ArchiveAfter, time.Now())
+}
+
+// Unarchive moves the todos with the given IDs from archive back into the
+// todo file. Todos whose ID was taken in the meantime get a new one.
+func (ts *TodoService) Unarchive(archive *TodoService, ids []string) ([]TransferResult, error) {
+	return MoveTodos(archive, ts, ids, ConflictRename)
+}
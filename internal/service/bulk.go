@@ -0,0 +1,341 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Ng1n3/go-todo/internal/errors"
+	"github.com/Ng1n3/go-todo/internal/store"
+	"github.com/Ng1n3/go-todo/internal/types"
+	"github.com/Ng1n3/go-todo/internal/utils"
+)
+
+// BulkUpdate is a change applied to many todos at once. Zero fields leave
+// the todos alone; Delete ignores the other fields.
+type BulkUpdate struct {
+	Priority     types.Priority
+	AddLabels    []string
+	RemoveLabels []string
+	// ShiftDue moves due dates by a whole number of days. Todos without a
+	// due date are left alone.
+	ShiftDue time.Duration
+	Complete bool
+	Delete   bool
+}
+
+// IsZero reports whether the update changes nothing.
+func (u BulkUpdate) IsZero() bool {
+	return u.Priority == "" && len(u.AddLabels) == 0 && len(u.RemoveLabels) == 0 && u.ShiftDue == 0 && !u.Complete && !u.Delete
+}
+
+// String describes the update, e.g. "priority HIGH, add labels urgent".
+func (u BulkUpdate) String() string {
+	if u.Delete {
+		return "delete"
+	}
+	var parts []string
+	if u.Priority != "" {
+		parts = append(parts, "priority "+string(u.Priority.Normalize()))
+	}
+	if len(u.AddLabels) > 0 {
+		parts = append(parts, "add labels "+strings.Join(u.AddLabels, ","))
+	}
+	if len(u.RemoveLabels) > 0 {
+		parts = append(parts, "remove labels "+strings.Join(u.RemoveLabels, ","))
+	}
+	if u.ShiftDue != 0 {
+		shift := utils.FormatDuration(u.ShiftDue)
+		if u.ShiftDue > 0 {
+			shift = "+" + shift
+		}
+		parts = append(parts, "shift due "+shift)
+	}
+	if u.Complete {
+		parts = append(parts, "complete")
+	}
+	return strings.Join(parts, ", ")
+}
+
+func (u BulkUpdate) validate() error {
+	if u.IsZero() {
+		return fmt.Errorf("%w: the bulk update changes nothing", errors.ErrInvalidInput)
+	}
+	if u.Priority != "" {
+		if err := u.Priority.Validate(); err != nil {
+			return err
+		}
+	}
+	if u.ShiftDue%(24*time.Hour) != 0 {
+		return fmt.Errorf("%w: due dates can only be shifted by whole days", errors.ErrInvalidDuration)
+	}
+	return nil
+}
+
+// BulkChange is the effect of a bulk update on one todo.
+type BulkChange struct {
+	Before types.Todo
+	// After is the zero Todo when the todo is deleted.
+	After   types.Todo
+	Deleted bool
+}
+
+// Description lists what changes, e.g. "priority LOW->HIGH".
+func (c BulkChange) Description() string {
+	if c.Deleted {
+		return "deleted"
+	}
+	return describeUpdate(c.Before, c.After)
+}
+
+// Changed reports whether the todo is affected at all.
+func (c BulkChange) Changed() bool {
+	return c.Deleted || c.Description() != "no changes"
+}
+
+// PreviewBulk returns the changes applying update to the todos with the
+// given IDs would make, without changing them. Todos the update leaves as
+// they are aren't included.
+func (ts *TodoService) PreviewBulk(ids []string, update BulkUpdate) ([]BulkChange, error) {
+	if err := update.validate(); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	changes := make([]BulkChange, 0, len(ids))
+	for _, id := range ids {
+		todo, err := ts.storage.Get(id)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", id, err)
+		}
+
+		change := BulkChange{Before: todo, Deleted: update.Delete}
+		if !update.Delete {
+			if change.After, err = ts.bulkApply(todo, update, now); err != nil {
+				return nil, fmt.Errorf("%s: %w", todo.ID, err)
+			}
+		}
+		if change.Changed() {
+			changes = append(changes, change)
+		}
+	}
+	return changes, nil
+}
+
+// bulkApply returns todo with update applied. todo itself isn't modified.
+func (ts *TodoService) bulkApply(todo types.Todo, update BulkUpdate, now time.Time) (types.Todo, error) {
+	if update.Priority != "" {
+		todo.Priority = update.Priority.Normalize()
+	}
+
+	if len(update.AddLabels) > 0 || len(update.RemoveLabels) > 0 {
+		labels := make([]string, 0, len(todo.Labels)+len(update.AddLabels))
+		for _, label := range todo.Labels {
+			if !hasLabel(update.RemoveLabels, label) {
+				labels = append(labels, label)
+			}
+		}
+		for _, label := range update.AddLabels {
+			if !hasLabel(labels, label) {
+				labels = append(labels, label)
+			}
+		}
+		var err error
+		if todo.Labels, err = ts.allowLabels(labels); err != nil {
+			return todo, err
+		}
+	}
+
+	if update.ShiftDue != 0 && todo.HasDueDate() {
+		todo.DueDate = todo.DueDate.Add(update.ShiftDue)
+	}
+
+	if update.Complete {
+		if err := ts.storage.Workflow().Complete(&todo, true, now); err != nil {
+			return todo, err
+		}
+	}
+	return todo, nil
+}
+
+// maxUndo is how many bulk updates of a file can be undone.
+const maxUndo = 10
+
+// ApplyBulk applies update to the todos with the given IDs and saves the
+// file once. The file as it was before is kept as an undo point, so the
+// whole update can be reverted with UndoBulk. If any todo can't be updated,
+// nothing is changed.
+func (ts *TodoService) ApplyBulk(ids []string, update BulkUpdate) ([]BulkChange, error) {
+	changes, err := ts.PreviewBulk(ids, update)
+	if err != nil {
+		return nil, err
+	}
+	if len(changes) == 0 {
+		return changes, nil
+	}
+
+	points, err := ts.undoPoints()
+	if err != nil {
+		return nil, err
+	}
+	before, err := os.ReadFile(ts.storage.File())
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read %s: %w", ts.File(), err)
+	}
+
+	for _, change := range changes {
+		if change.Deleted {
+			err = ts.storage.Delete(change.Before.ID)
+		} else {
+			err = ts.storage.Save(&change.After)
+		}
+		if err != nil {
+			if reloadErr := ts.storage.Reload(); reloadErr != nil {
+				return nil, fmt.Errorf("%w (and failed to discard the changes: %v)", err, reloadErr)
+			}
+			return nil, fmt.Errorf("%s: %w", change.Before.ID, err)
+		}
+	}
+
+	description := fmt.Sprintf("bulk %s on %d todos", update, len(changes))
+	ts.record("%s", description)
+	if err := ts.Save(); err != nil {
+		return nil, err
+	}
+
+	point := UndoPoint{File: ts.File(), Description: description, CreatedAt: time.Now(), Data: before}
+	if point.After, err = fileHash(ts.storage.File()); err == nil {
+		points = append(points, point)
+		if len(points) > maxUndo {
+			points = points[len(points)-maxUndo:]
+		}
+		err = ts.writeUndoPoints(points)
+	}
+	if err != nil {
+		return changes, fmt.Errorf("the bulk update was saved, but it can't be undone: %w", err)
+	}
+	return changes, nil
+}
+
+// UndoPoint is the content of a todo file before a bulk update. Encrypted
+// files stay encrypted.
+type UndoPoint struct {
+	File        string    `json:"file"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+	// Data is empty when the file didn't exist before the update.
+	Data []byte `json:"data"`
+	// After is the SHA-256 of the file right after the update, to notice
+	// changes made since.
+	After string `json:"after"`
+}
+
+func (ts *TodoService) undoPath() string {
+	return ts.config.GetStatePath(filepath.Join("undo", ts.File()))
+}
+
+// undoPoints returns the bulk updates of the file that can be undone,
+// oldest first.
+func (ts *TodoService) undoPoints() ([]UndoPoint, error) {
+	data, err := os.ReadFile(ts.undoPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read undo points: %w", err)
+	}
+
+	var points []UndoPoint
+	if err := json.Unmarshal(data, &points); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal undo points: %w", err)
+	}
+	return points, nil
+}
+
+// UndoBulk puts the file back as it was before its last bulk update; called
+// again it reverts the one before, up to the last ten. It refuses when the
+// file was changed after the update, as those changes would be lost, unless
+// force is set. Todos the update deleted also stay in the trash.
+func (ts *TodoService) UndoBulk(force bool) (UndoPoint, error) {
+	points, err := ts.undoPoints()
+	if err != nil {
+		return UndoPoint{}, err
+	}
+	if len(points) == 0 {
+		return UndoPoint{}, fmt.Errorf("%w: no bulk update of %s", errors.ErrNothingToUndo, ts.File())
+	}
+	point := points[len(points)-1]
+
+	if !force {
+		current, err := fileHash(ts.storage.File())
+		if err != nil && !os.IsNotExist(err) {
+			return UndoPoint{}, err
+		}
+		if current != point.After {
+			return UndoPoint{}, fmt.Errorf("%s was changed after %q; undoing would lose those changes", ts.File(), point.Description)
+		}
+	}
+
+	if len(point.Data) == 0 {
+		err = os.Remove(ts.storage.File())
+	} else {
+		mode := ts.config.FileMode
+		if info, statErr := os.Stat(ts.storage.File()); statErr == nil {
+			mode = info.Mode().Perm()
+		}
+		err = store.WriteFileAtomic(ts.storage.File(), point.Data, mode)
+	}
+	if err != nil && !os.IsNotExist(err) {
+		return UndoPoint{}, fmt.Errorf("failed to restore %s: %w", ts.File(), err)
+	}
+	if err := ts.writeUndoPoints(points[:len(points)-1]); err != nil {
+		return UndoPoint{}, err
+	}
+
+	if err := ts.Reload(); err != nil {
+		return UndoPoint{}, err
+	}
+	ts.record("undo %s", point.Description)
+	return point, ts.afterPersist()
+}
+
+func (ts *TodoService) writeUndoPoints(points []UndoPoint) error {
+	if len(points) == 0 {
+		if err := os.Remove(ts.undoPath()); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to clear undo points: %w", err)
+		}
+		return nil
+	}
+
+	data, err := json.Marshal(points)
+	if err != nil {
+		return fmt.Errorf("failed to marshal undo points: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(ts.undoPath()), 0755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+	// Undo points hold whole copies of the file, so they get the same
+	// protection.
+	mode := ts.config.FileMode
+	if ts.Encrypted() {
+		mode = 0600
+	}
+	if err := store.WriteFileAtomic(ts.undoPath(), data, mode); err != nil {
+		return fmt.Errorf("failed to write undo points: %w", err)
+	}
+	return nil
+}
+
+func fileHash(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
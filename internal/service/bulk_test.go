@@ -0,0 +1,123 @@
+package service
+
+import (
+	"bytes"
+	stderrors "errors"
+	"os"
+	"testing"
+
+	"github.com/Ng1n3/go-todo/internal/errors"
+	"github.com/Ng1n3/go-todo/internal/types"
+)
+
+func TestBulkUndo(t *testing.T) {
+	tests := []struct {
+		name   string
+		update BulkUpdate
+	}{
+		{name: "priority and labels", update: BulkUpdate{Priority: types.High, AddLabels: []string{"urgent"}, RemoveLabels: []string{"a"}}},
+		{name: "complete", update: BulkUpdate{Complete: true}},
+		{name: "delete", update: BulkUpdate{Delete: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := NewWorkspace(testConfig(t))
+			ts := openFile(t, w, "work.json", "a", "b", "c")
+			before, err := os.ReadFile(ts.storage.File())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var ids []string
+			for _, todo := range ts.ListTodos() {
+				ids = append(ids, todo.ID)
+			}
+			changes, err := ts.ApplyBulk(ids, tt.update)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(changes) != len(ids) {
+				t.Errorf("ApplyBulk changed %d todos, want %d", len(changes), len(ids))
+			}
+			after, _ := os.ReadFile(ts.storage.File())
+			if bytes.Equal(after, before) {
+				t.Fatal("ApplyBulk didn't change the file")
+			}
+
+			point, err := ts.UndoBulk(false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if point.Description == "" {
+				t.Error("the undo point has no description")
+			}
+			restored, _ := os.ReadFile(ts.storage.File())
+			if !bytes.Equal(restored, before) {
+				t.Errorf("after undo the file is\n%s\nwant\n%s", restored, before)
+			}
+			if got := len(ts.ListTodos()); got != len(ids) {
+				t.Errorf("after undo the service lists %d todos, want %d", got, len(ids))
+			}
+
+			if _, err := ts.UndoBulk(false); !stderrors.Is(err, errors.ErrNothingToUndo) {
+				t.Errorf("second undo = %v, want ErrNothingToUndo", err)
+			}
+		})
+	}
+}
+
+func TestBulkUndoRefusesAfterLaterChanges(t *testing.T) {
+	w := NewWorkspace(testConfig(t))
+	ts := openFile(t, w, "work.json", "a", "b")
+	id := ts.ListTodos()[0].ID
+
+	if _, err := ts.ApplyBulk([]string{id}, BulkUpdate{Priority: types.High}); err != nil {
+		t.Fatal(err)
+	}
+	if err := ts.UpdateTodo(id, map[string]any{"task": "changed afterwards"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := ts.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := ts.UndoBulk(false); err == nil {
+		t.Fatal("UndoBulk succeeded although the file changed after the update")
+	}
+	if _, err := ts.UndoBulk(true); err != nil {
+		t.Fatalf("UndoBulk(force): %v", err)
+	}
+	todo, err := ts.GetTodo(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if todo.Priority != types.Medium || todo.Task == "changed afterwards" {
+		t.Errorf("after a forced undo the todo is %q with priority %s", todo.Task, todo.Priority)
+	}
+}
+
+func TestBulkChangesNothingWhenATodoFails(t *testing.T) {
+	w := NewWorkspace(testConfig(t))
+	ts := openFile(t, w, "work.json", "work", "work")
+	if err := ts.SetAllowedLabels([]string{"work", "urgent"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := ts.Save(); err != nil {
+		t.Fatal(err)
+	}
+	before, _ := os.ReadFile(ts.storage.File())
+
+	var ids []string
+	for _, todo := range ts.ListTodos() {
+		ids = append(ids, todo.ID)
+	}
+	if _, err := ts.ApplyBulk(ids, BulkUpdate{AddLabels: []string{"home"}}); !stderrors.Is(err, errors.ErrLabelNotAllowed) {
+		t.Fatalf("ApplyBulk = %v, want ErrLabelNotAllowed", err)
+	}
+	if after, _ := os.ReadFile(ts.storage.File()); !bytes.Equal(after, before) {
+		t.Error("a failed bulk update changed the file")
+	}
+	if _, err := ts.UndoBulk(false); !stderrors.Is(err, errors.ErrNothingToUndo) {
+		t.Errorf("UndoBulk after a failed update = %v, want ErrNothingToUndo", err)
+	}
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/Ng1n3/go-todo/internal/gitsync"
+	"github.com/Ng1n3/go-todo/internal/types"
+)
+
+// record remembers a description of a change for the next commit message.
+func (ts *TodoService) record(format string, args ...any) {
+	ts.changes = append(ts.changes, fmt.Sprintf(format, args...))
+}
+
+// describeUpdate lists the fields that differ between two versions of a
+// todo, e.g. "priority LOW->HIGH, labels work->work,urgent".
+func describeUpdate(before, after types.Todo) string {
+	var diffs []string
+	add := func(field, from, to string) {
+		if from != to {
+			diffs = append(diffs, fmt.Sprintf("%s %s->%s", field, from, to))
+		}
+	}
+
+	add("task", fmt.Sprintf("%q", before.Task), fmt.Sprintf("%q", after.Task))
+	add("due", formatDate(before), formatDate(after))
+	add("priority", string(before.Priority), string(after.Priority))
+	add("labels", formatLabels(before.Labels), formatLabels(after.Labels))
+	add("estimate", formatEstimate(before.Estimate), formatEstimate(after.Estimate))
+	add("status", string(before.Status), string(after.Status))
+	if before.Notes != after.Notes {
+		diffs = append(diffs, "notes edited")
+	}
+
+	if len(diffs) == 0 {
+		return "no changes"
+	}
+	return strings.Join(diffs, ", ")
+}
+
+func formatDate(todo types.Todo) string {
+	if !todo.HasDueDate() {
+		return "none"
+	}
+	return todo.DueDate.Format("2006-01-02")
+}
+
+func formatLabels(labels []string) string {
+	if len(labels) == 0 {
+		return "none"
+	}
+	return strings.Join(labels, ",")
+}
+
+func formatEstimate(estimate *types.Estimate) string {
+	if estimate == nil {
+		return "none"
+	}
+	return estimate.String()
+}
+
+// commitMessage summarises the changes recorded since the last save.
+func (ts *TodoService) commitMessage() string {
+	name := filepath.Base(ts.storage.File())
+	switch len(ts.changes) {
+	case 0:
+		return "update " + name
+	case 1:
+		return ts.changes[0]
+	default:
+		return fmt.Sprintf("%d changes to %s\n\n- %s", len(ts.changes), name, strings.Join(ts.changes, "\n- "))
+	}
+}
+
+// autoCommit commits the todo file to the git repository in the storage
+// directory when autocommit is enabled.
+func (ts *TodoService) autoCommit() error {
+	defer func() { ts.changes = nil }()
+
+	if !ts.config.GitAutoCommit {
+		return nil
+	}
+
+	rel, err := filepath.Rel(ts.config.StorageDir, ts.storage.File())
+	if err != nil {
+		return fmt.Errorf("todo file is outside the storage directory: %w", err)
+	}
+
+	if _, err := gitsync.Open(ts.config.StorageDir).Commit(ts.commitMessage(), rel); err != nil {
+		return fmt.Errorf("failed to commit changes: %w", err)
+	}
+	return nil
+}
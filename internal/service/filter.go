@@ -0,0 +1,266 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Ng1n3/go-todo/internal/errors"
+	"github.com/Ng1n3/go-todo/internal/types"
+	"github.com/Ng1n3/go-todo/internal/utils"
+)
+
+// Filter selects todos. The zero Filter matches every todo. Filters are
+// usually built from a query with ParseFilter, which is the syntax shared by
+// every command that accepts -filter.
+type Filter struct {
+	// Text must appear in the task, ignoring case.
+	Text []string
+	// Labels must all be present on the todo.
+	Labels   []string
+	Priority types.Priority
+	// Completed, when set, restricts to done or open todos.
+	Completed *bool
+	// Statuses, when set, restricts to todos in one of the statuses.
+	Statuses []types.Status
+	Overdue  bool
+	// NoDue restricts to todos without a due date.
+	NoDue bool
+	// DueFrom and DueTo bound the due date, inclusive. Zero means unbounded.
+	DueFrom time.Time
+	DueTo   time.Time
+	IDs     []string
+}
+
+// ParseFilter parses a filter query made of space separated terms:
+//
+//	label:work          has the label "work" or one below it, e.g. "work/ops" (repeatable)
+//	priority:high       has the priority
+//	is:open, is:done    completion state
+//	status:in_progress  in the workflow status (repeatable, matches any)
+//	due:overdue         open and past its due date
+//	due:none            has no due date
+//	due:today           due today (also "tomorrow", or a YYYY-MM-DD date)
+//	due<2026-01-31      due on or before the date (also "today", "+3d")
+//	due>2026-01-01      due on or after the date
+//	id:abc123           has the ID (repeatable)
+//	anything else       must appear in the task text
+func ParseFilter(query string) (Filter, error) {
+	return parseFilter(query, time.Now())
+}
+
+func parseFilter(query string, now time.Time) (Filter, error) {
+	var f Filter
+	for _, term := range strings.Fields(query) {
+		lower := strings.ToLower(term)
+
+		switch {
+		case strings.HasPrefix(lower, "due<"), strings.HasPrefix(lower, "due>"):
+			date, err := parseFilterDate(term[4:], now)
+			if err != nil {
+				return Filter{}, err
+			}
+			if lower[3] == '<' {
+				f.DueTo = date
+			} else {
+				f.DueFrom = date
+			}
+			continue
+		}
+
+		key, value, ok := strings.Cut(term, ":")
+		if !ok || value == "" {
+			f.Text = append(f.Text, strings.ToLower(term))
+			continue
+		}
+
+		switch strings.ToLower(key) {
+		case "label", "l":
+			f.Labels = append(f.Labels, value)
+		case "priority", "p":
+			p := types.Priority(value).Normalize()
+			switch p {
+			case "H":
+				p = types.High
+			case "M":
+				p = types.Medium
+			case "L":
+				p = types.Low
+			}
+			if err := p.Validate(); err != nil {
+				return Filter{}, err
+			}
+			f.Priority = p
+		case "status", "s":
+			f.Statuses = append(f.Statuses, types.ParseStatus(value))
+		case "is":
+			var done bool
+			switch strings.ToLower(value) {
+			case "done", "completed":
+				done = true
+			case "open", "pending":
+				done = false
+			default:
+				return Filter{}, fmt.Errorf("%w: unknown state %q, use is:open or is:done", errors.ErrInvalidFilter, value)
+			}
+			f.Completed = &done
+		case "due":
+			switch strings.ToLower(value) {
+			case "overdue":
+				f.Overdue = true
+			case "none":
+				f.NoDue = true
+			default:
+				date, err := parseFilterDate(value, now)
+				if err != nil {
+					return Filter{}, err
+				}
+				f.DueFrom, f.DueTo = date, date
+			}
+		case "id":
+			f.IDs = append(f.IDs, value)
+		default:
+			f.Text = append(f.Text, strings.ToLower(term))
+		}
+	}
+	return f, nil
+}
+
+// ParseDate parses a date the way filters do: YYYY-MM-DD, "today",
+// "tomorrow", "yesterday" or an offset from today such as "-4w".
+func ParseDate(value string) (time.Time, error) {
+	return parseFilterDate(value, time.Now())
+}
+
+// parseFilterDate accepts YYYY-MM-DD, "today", "tomorrow", "yesterday" and
+// offsets from today such as "+3d" or "-1w".
+func parseFilterDate(value string, now time.Time) (time.Time, error) {
+	today := types.DayOf(now)
+
+	switch strings.ToLower(value) {
+	case "today":
+		return today, nil
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), nil
+	}
+
+	if strings.HasPrefix(value, "+") || strings.HasPrefix(value, "-") {
+		offset, err := utils.ParseDuration(value)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("%w: invalid date offset %q", errors.ErrInvalidFilter, value)
+		}
+		return today.Add(offset).Truncate(24 * time.Hour), nil
+	}
+
+	date, err := utils.ValidateDate(value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: invalid date %q", errors.ErrInvalidFilter, value)
+	}
+	return date, nil
+}
+
+// IsZero reports whether the filter matches every todo.
+func (f Filter) IsZero() bool {
+	return len(f.Text) == 0 && len(f.Labels) == 0 && f.Priority == "" && f.Completed == nil && len(f.Statuses) == 0 &&
+		!f.Overdue && !f.NoDue && f.DueFrom.IsZero() && f.DueTo.IsZero() && len(f.IDs) == 0
+}
+
+// Match reports whether todo satisfies every condition of the filter at now.
+func (f Filter) Match(todo types.Todo, now time.Time) bool {
+	task := strings.ToLower(todo.Task)
+	for _, text := range f.Text {
+		if !strings.Contains(task, text) {
+			return false
+		}
+	}
+
+	for _, label := range f.Labels {
+		if !hasLabelWithin(todo.Labels, label) {
+			return false
+		}
+	}
+
+	if f.Priority != "" && todo.Priority.Normalize() != f.Priority {
+		return false
+	}
+	if f.Completed != nil && todo.Completed != *f.Completed {
+		return false
+	}
+	if len(f.Statuses) > 0 && !containsStatus(f.Statuses, todo.Status) {
+		return false
+	}
+	if f.Overdue && !todo.IsOverdue(now) {
+		return false
+	}
+	if f.NoDue && todo.HasDueDate() {
+		return false
+	}
+
+	if !f.DueFrom.IsZero() || !f.DueTo.IsZero() {
+		if !todo.HasDueDate() {
+			return false
+		}
+		if !f.DueFrom.IsZero() && todo.DueDate.Before(f.DueFrom) {
+			return false
+		}
+		if !f.DueTo.IsZero() && todo.DueDate.After(f.DueTo) {
+			return false
+		}
+	}
+
+	if len(f.IDs) > 0 {
+		found := false
+		for _, id := range f.IDs {
+			if todo.ID == id {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// Apply returns the todos matching the filter, keeping their order.
+func (f Filter) Apply(todos []types.Todo, now time.Time) []types.Todo {
+	var out []types.Todo
+	for _, todo := range todos {
+		if f.Match(todo, now) {
+			out = append(out, todo)
+		}
+	}
+	return out
+}
+
+func hasLabel(labels []string, want string) bool {
+	for _, label := range labels {
+		if utils.SameLabel(label, want) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasLabelWithin reports whether one of labels is want or below it in the
+// label hierarchy.
+func hasLabelWithin(labels []string, want string) bool {
+	for _, label := range labels {
+		if utils.LabelWithin(label, want) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsStatus(statuses []types.Status, s types.Status) bool {
+	for _, candidate := range statuses {
+		if candidate == s {
+			return true
+		}
+	}
+	return false
+}
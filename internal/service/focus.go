@@ -0,0 +1,261 @@
+package service
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/Ng1n3/go-todo/internal/config"
+	"github.com/Ng1n3/go-todo/internal/errors"
+	"github.com/Ng1n3/go-todo/internal/store"
+	"github.com/Ng1n3/go-todo/internal/types"
+)
+
+type FocusPhase string
+
+const (
+	FocusWork       FocusPhase = "work"
+	FocusShortBreak FocusPhase = "short_break"
+	FocusLongBreak  FocusPhase = "long_break"
+)
+
+// Focus is a running focus session: work intervals on a todo separated by
+// breaks. Like the timer it is kept in the state directory, so a session
+// can be resumed after the terminal was closed.
+type Focus struct {
+	File   string `json:"file"`
+	TodoID string `json:"todo_id"`
+	// Task is empty when the todo is in an encrypted file.
+	Task     string       `json:"task,omitempty"`
+	Settings config.Focus `json:"settings"`
+	// Rounds is the number of work intervals to run; zero runs until the
+	// session is interrupted. Done counts the completed ones.
+	Rounds int `json:"rounds"`
+	Done   int `json:"done"`
+
+	Phase      FocusPhase `json:"phase"`
+	PhaseStart time.Time  `json:"phase_start"`
+	PhaseEnd   time.Time  `json:"phase_end"`
+}
+
+// Remaining returns how much of the current phase is left at now.
+func (f Focus) Remaining(now time.Time) time.Duration {
+	if now.After(f.PhaseEnd) {
+		return 0
+	}
+	return f.PhaseEnd.Sub(now)
+}
+
+func (f *Focus) begin(phase FocusPhase, now time.Time) {
+	length := f.Settings.Work
+	switch phase {
+	case FocusShortBreak:
+		length = f.Settings.ShortBreak
+	case FocusLongBreak:
+		length = f.Settings.LongBreak
+	}
+	f.Phase, f.PhaseStart, f.PhaseEnd = phase, now, now.Add(length)
+}
+
+func (w *Workspace) focusPath() string {
+	return w.config.GetStatePath("focus.json")
+}
+
+// CurrentFocus returns the focus session in progress, or nil if there is
+// none.
+func (w *Workspace) CurrentFocus() (*Focus, error) {
+	data, err := os.ReadFile(w.focusPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read focus session: %w", err)
+	}
+
+	var focus Focus
+	if err := json.Unmarshal(data, &focus); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal focus session: %w", err)
+	}
+	return &focus, nil
+}
+
+// StartFocus starts a focus session of rounds work intervals on the todo
+// with the given ID in the file of ts, using the configured interval
+// lengths. It fails with ErrFocusRunning if a session is in progress.
+func (w *Workspace) StartFocus(ts *TodoService, id string, rounds int, now time.Time) (Focus, error) {
+	current, err := w.CurrentFocus()
+	if err != nil {
+		return Focus{}, err
+	}
+	if current != nil {
+		return Focus{}, fmt.Errorf("%w on %s in %s; resume, stop or cancel it first", errors.ErrFocusRunning, current.TodoID, current.File)
+	}
+
+	todo, err := ts.GetTodo(id)
+	if err != nil {
+		return Focus{}, fmt.Errorf("%s in %s: %w", id, ts.File(), err)
+	}
+
+	focus := Focus{File: ts.File(), TodoID: todo.ID, Settings: w.config.Focus, Rounds: rounds}
+	if !ts.Encrypted() {
+		focus.Task = todo.Task
+	}
+	focus.begin(FocusWork, now)
+	if err := w.writeFocus(focus); err != nil {
+		return Focus{}, err
+	}
+	return focus, nil
+}
+
+// AdvanceFocus ends the current phase of the session, which must be over at
+// now, and starts the next one at now. A finished work interval is recorded
+// on the todo, wherever it has been moved to. It reports whether the session
+// is over, in which case it is no longer in progress. When the todo is gone
+// the session is over as well, and the error says it was not recorded.
+func (w *Workspace) AdvanceFocus(now time.Time) (Focus, bool, error) {
+	focus, err := w.requireFocus()
+	if err != nil {
+		return Focus{}, false, err
+	}
+	if now.Before(focus.PhaseEnd) {
+		return focus, false, fmt.Errorf("%w: the %s phase is not over yet", errors.ErrInvalidInput, focus.Phase)
+	}
+
+	if focus.Phase != FocusWork {
+		focus.begin(FocusWork, now)
+		return focus, false, w.writeFocus(focus)
+	}
+
+	session := types.FocusSession{Start: focus.PhaseStart, End: focus.PhaseEnd}
+	if err := w.recordFocus(&focus, session); err != nil {
+		if lost, err := w.dropLostFocus(err); lost {
+			return focus, true, err
+		}
+		return focus, false, err
+	}
+	focus.Done++
+
+	if focus.Rounds > 0 && focus.Done >= focus.Rounds {
+		return focus, true, w.clearFocus()
+	}
+	if focus.Settings.LongBreakEvery > 0 && focus.Done%focus.Settings.LongBreakEvery == 0 {
+		focus.begin(FocusLongBreak, now)
+	} else {
+		focus.begin(FocusShortBreak, now)
+	}
+	return focus, false, w.writeFocus(focus)
+}
+
+// InterruptFocus ends the session in progress. A work interval that was
+// under way is recorded on the todo as interrupted. When the todo is gone
+// the session still ends, and the error says it was not recorded.
+func (w *Workspace) InterruptFocus(now time.Time) (Focus, error) {
+	focus, err := w.requireFocus()
+	if err != nil {
+		return Focus{}, err
+	}
+
+	if focus.Phase == FocusWork {
+		end := now
+		if end.After(focus.PhaseEnd) {
+			end = focus.PhaseEnd
+		}
+		session := types.FocusSession{Start: focus.PhaseStart, End: end, Interrupted: true}
+		if err := w.recordFocus(&focus, session); err != nil {
+			_, err = w.dropLostFocus(err)
+			return focus, err
+		}
+	}
+	return focus, w.clearFocus()
+}
+
+// CancelFocus ends the session in progress without recording anything.
+func (w *Workspace) CancelFocus() (Focus, error) {
+	focus, err := w.requireFocus()
+	if err != nil {
+		return Focus{}, err
+	}
+	return focus, w.clearFocus()
+}
+
+// recordFocus records a session on the focused todo and points focus at the
+// file the todo is in now.
+func (w *Workspace) recordFocus(focus *Focus, session types.FocusSession) error {
+	ts, err := w.findTodo(focus.File, focus.TodoID, focus.Task)
+	if err != nil {
+		return err
+	}
+	focus.File = ts.File()
+	if err := ts.RecordFocus(focus.TodoID, session); err != nil {
+		return fmt.Errorf("failed to record focus session in %s: %w", focus.File, err)
+	}
+	return ts.Save()
+}
+
+// dropLostFocus ends the session in progress when err says its todo can no
+// longer be found, so that a new session can be started. It reports whether
+// it did.
+func (w *Workspace) dropLostFocus(err error) (bool, error) {
+	if !stderrors.Is(err, errors.ErrTodoNotFound) && !stderrors.Is(err, errors.ErrAmbiguousTodo) {
+		return false, err
+	}
+	if clearErr := w.clearFocus(); clearErr != nil {
+		return false, fmt.Errorf("%w; %v", err, clearErr)
+	}
+	return true, fmt.Errorf("%w; the focus session has ended without being recorded", err)
+}
+
+func (w *Workspace) requireFocus() (Focus, error) {
+	focus, err := w.CurrentFocus()
+	if err != nil {
+		return Focus{}, err
+	}
+	if focus == nil {
+		return Focus{}, errors.ErrNoFocus
+	}
+	return *focus, nil
+}
+
+func (w *Workspace) writeFocus(focus Focus) error {
+	data, err := json.MarshalIndent(focus, "", " ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal focus session: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(w.focusPath()), 0755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+	if err := store.WriteFileAtomic(w.focusPath(), data, w.config.FileMode); err != nil {
+		return fmt.Errorf("failed to write focus session: %w", err)
+	}
+	return nil
+}
+
+func (w *Workspace) clearFocus() error {
+	if err := os.Remove(w.focusPath()); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to clear focus session: %w", err)
+	}
+	return nil
+}
+
+// RecordFocus adds a focus session to a todo. The file is not saved.
+func (ts *TodoService) RecordFocus(id string, session types.FocusSession) error {
+	todo, err := ts.storage.Get(id)
+	if err != nil {
+		return err
+	}
+
+	todo.FocusSessions = append(todo.FocusSessions, session)
+	if err := ts.storage.Save(&todo); err != nil {
+		return err
+	}
+
+	state := "complete"
+	if session.Interrupted {
+		state = "interrupted"
+	}
+	ts.record("record %s focus session of %s on todo %s: %s", state, session.Duration().Round(time.Second), todo.ID, todo.Task)
+	return nil
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	stderrors "errors"
+	"testing"
+	"time"
+
+	"github.com/Ng1n3/go-todo/internal/errors"
+)
+
+func TestFocusOnDeletedTodo(t *testing.T) {
+	tests := []struct {
+		name string
+		end  func(w *Workspace, start time.Time) error
+	}{
+		{name: "stop", end: func(w *Workspace, start time.Time) error {
+			_, err := w.InterruptFocus(start.Add(10 * time.Minute))
+			return err
+		}},
+		{name: "advance", end: func(w *Workspace, start time.Time) error {
+			_, finished, err := w.AdvanceFocus(start.Add(time.Hour))
+			if !finished {
+				t.Error("AdvanceFocus() finished = false, want the session over")
+			}
+			return err
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := NewWorkspace(testConfig(t))
+			ts := openFile(t, w, "work.json", "a", "b")
+			id := ts.ListTodos()[0].ID
+			start := time.Date(2026, 3, 5, 9, 0, 0, 0, time.UTC)
+			if _, err := w.StartFocus(ts, id, 4, start); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := ts.DeleteTodo(id); err != nil {
+				t.Fatal(err)
+			}
+			if err := ts.Save(); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := tt.end(w, start); !stderrors.Is(err, errors.ErrTodoNotFound) {
+				t.Errorf("ending the session error = %v, want %v", err, errors.ErrTodoNotFound)
+			}
+			if current, err := w.CurrentFocus(); err != nil || current != nil {
+				t.Fatalf("CurrentFocus() = %v, %v, want the session ended", current, err)
+			}
+			if _, err := w.StartFocus(ts, ts.ListTodos()[0].ID, 4, start); err != nil {
+				t.Errorf("StartFocus() after the session ended: %v", err)
+			}
+		})
+	}
+}
+
+func TestFocusFollowsMovedTodo(t *testing.T) {
+	w := NewWorkspace(testConfig(t))
+	ts := openFile(t, w, "work.json", "a")
+	id := ts.ListTodos()[0].ID
+	start := time.Date(2026, 3, 5, 9, 0, 0, 0, time.UTC)
+	if _, err := w.StartFocus(ts, id, 2, start); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := MoveTodos(ts, openFile(t, w, "home.json"), []string{id}, ConflictRename); err != nil {
+		t.Fatal(err)
+	}
+
+	focus, finished, err := w.AdvanceFocus(start.Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if finished || focus.File != "home.json" {
+		t.Errorf("AdvanceFocus() = %s, %v, want the session to carry on in home.json", focus.File, finished)
+	}
+	if _, err := w.InterruptFocus(focus.PhaseEnd); err != nil {
+		t.Fatal(err)
+	}
+
+	home, err := w.Open("home.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	todo, err := home.GetTodo(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(todo.FocusSessions) != 1 {
+		t.Errorf("focus sessions in home.json = %v, want the finished work interval", todo.FocusSessions)
+	}
+}
+
+func TestCancelFocus(t *testing.T) {
+	w := NewWorkspace(testConfig(t))
+	ts := openFile(t, w, "work.json", "a")
+	id := ts.ListTodos()[0].ID
+	if _, err := w.CancelFocus(); !stderrors.Is(err, errors.ErrNoFocus) {
+		t.Errorf("CancelFocus() without a session error = %v, want %v", err, errors.ErrNoFocus)
+	}
+	if _, err := w.StartFocus(ts, id, 4, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.CancelFocus(); err != nil {
+		t.Fatal(err)
+	}
+	if current, err := w.CurrentFocus(); err != nil || current != nil {
+		t.Errorf("CurrentFocus() = %v, %v after cancelling, want none", current, err)
+	}
+
+	reloaded, err := w.Open("work.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if todo, _ := reloaded.GetTodo(id); len(todo.FocusSessions) != 0 {
+		t.Errorf("focus sessions = %v after cancelling, want none", todo.FocusSessions)
+	}
+}
@@ -0,0 +1,366 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Ng1n3/go-todo/internal/errors"
+	"github.com/Ng1n3/go-todo/internal/types"
+	"github.com/Ng1n3/go-todo/internal/utils"
+)
+
+// LabelUsage counts the todos carrying a label across the workspace,
+// archives included. Labels are told apart in their normalized form, so
+// "Work" and "work" are counted together.
+type LabelUsage struct {
+	// Label is the most used spelling.
+	Label string
+	// Spellings lists every spelling in use when there is more than one.
+	Spellings []string
+	Open      int
+	Done      int
+	Files     []string
+}
+
+// LabelEdit reports how many todos a label change touched in a file.
+type LabelEdit struct {
+	File  string
+	Todos int
+}
+
+// LabelUsage returns every label used in the workspace, most used first.
+func (w *Workspace) LabelUsage() ([]LabelUsage, error) {
+	files, err := w.LoadWithArchives(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	usage := make(map[string]*LabelUsage)
+	spellings := make(map[string]map[string]int)
+	for _, file := range files {
+		for _, todo := range file.Todos {
+			for _, label := range todo.Labels {
+				key := utils.NormalizeLabel(label)
+				u := usage[key]
+				if u == nil {
+					u = &LabelUsage{}
+					usage[key] = u
+					spellings[key] = make(map[string]int)
+				}
+				spellings[key][label]++
+				if todo.Completed {
+					u.Done++
+				} else {
+					u.Open++
+				}
+				if len(u.Files) == 0 || u.Files[len(u.Files)-1] != file.File {
+					u.Files = append(u.Files, file.File)
+				}
+			}
+		}
+	}
+
+	out := make([]LabelUsage, 0, len(usage))
+	for key, u := range usage {
+		for spelling, n := range spellings[key] {
+			if u.Label == "" || n > spellings[key][u.Label] || (n == spellings[key][u.Label] && spelling < u.Label) {
+				u.Label = spelling
+			}
+		}
+		if len(spellings[key]) > 1 {
+			for spelling := range spellings[key] {
+				u.Spellings = append(u.Spellings, spelling)
+			}
+			sort.Strings(u.Spellings)
+		}
+		out = append(out, *u)
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		a, b := out[i], out[j]
+		if a.Open+a.Done != b.Open+b.Done {
+			return a.Open+a.Done > b.Open+b.Done
+		}
+		return utils.NormalizeLabel(a.Label) < utils.NormalizeLabel(b.Label)
+	})
+	return out, nil
+}
+
+// RenameLabel renames a label on every todo of every file and archive, and
+// in the label allow-lists. Every spelling of the label is renamed, and so
+// are the labels below it: renaming "clients/acme" to "customers/acme"
+// turns "clients/acme/billing" into "customers/acme/billing".
+func (w *Workspace) RenameLabel(from, to string) ([]LabelEdit, error) {
+	target, err := singleLabel(to)
+	if err != nil {
+		return nil, err
+	}
+	return w.editLabels(fmt.Sprintf("rename label %s to %s", from, target), func(label string) (string, bool) {
+		if utils.LabelWithin(label, from) {
+			return utils.ReparentLabel(label, from, target), true
+		}
+		return label, true
+	})
+}
+
+// MergeLabels replaces the given labels with into everywhere, moving the
+// labels below them under into. A todo that had several of them ends up
+// with into once.
+func (w *Workspace) MergeLabels(labels []string, into string) ([]LabelEdit, error) {
+	target, err := singleLabel(into)
+	if err != nil {
+		return nil, err
+	}
+	return w.editLabels(fmt.Sprintf("merge labels %s into %s", strings.Join(labels, ","), target), func(label string) (string, bool) {
+		for _, source := range labels {
+			if utils.LabelWithin(label, source) {
+				return utils.ReparentLabel(label, source, target), true
+			}
+		}
+		return label, true
+	})
+}
+
+// DeleteLabel removes a label from every todo of every file and archive,
+// and from the label allow-lists. Labels below it are kept.
+func (w *Workspace) DeleteLabel(label string) ([]LabelEdit, error) {
+	return w.editLabels("delete label "+label, func(l string) (string, bool) {
+		return l, !utils.SameLabel(l, label)
+	})
+}
+
+func singleLabel(input string) (string, error) {
+	labels := utils.ValidateLabels(input)
+	if len(labels) != 1 {
+		return "", fmt.Errorf("%w: %q is not a single label", errors.ErrInvalidInput, input)
+	}
+	return labels[0], nil
+}
+
+// editLabels maps the labels of every todo file and archive through edit,
+// which returns the new label and whether to keep it, and saves the files
+// that changed. Every file is loaded and edited before any is saved, so a
+// file that can't be loaded, e.g. an encrypted one without its passphrase,
+// leaves the workspace untouched. It fails if no todo or allow-list had a
+// matching label.
+func (w *Workspace) editLabels(description string, edit func(label string) (string, bool)) ([]LabelEdit, error) {
+	names, err := w.Files()
+	if err != nil {
+		return nil, err
+	}
+
+	var files []*TodoService
+	for _, name := range names {
+		ts, err := w.Open(name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load %s: %w", name, err)
+		}
+		archive, err := ts.OpenArchive()
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, ts, archive)
+	}
+
+	var edits []LabelEdit
+	var dirty []*TodoService
+	for _, file := range files {
+		n, listChanged, err := file.editLabels(description, edit)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file.File(), err)
+		}
+		if n == 0 && !listChanged {
+			continue
+		}
+		dirty = append(dirty, file)
+		if n > 0 {
+			edits = append(edits, LabelEdit{File: file.File(), Todos: n})
+		}
+	}
+	if len(dirty) == 0 {
+		return nil, fmt.Errorf("%w: no todo has the label", errors.ErrInvalidInput)
+	}
+
+	for i, file := range dirty {
+		if err := file.Save(); err != nil {
+			return edits, fmt.Errorf("%w (%d of %d files saved)", err, i, len(dirty))
+		}
+	}
+	return edits, nil
+}
+
+// editLabels applies a label edit to the todos and the allow-list of the
+// file. It returns how many todos changed and whether the allow-list did.
+func (ts *TodoService) editLabels(description string, edit func(label string) (string, bool)) (int, bool, error) {
+	apply := func(labels []string) ([]string, bool) {
+		out := make([]string, 0, len(labels))
+		for _, label := range labels {
+			if mapped, keep := edit(label); keep {
+				out = append(out, mapped)
+			}
+		}
+		out = utils.ValidateLabels(strings.Join(out, ","))
+		return out, strings.Join(out, ",") != strings.Join(labels, ",")
+	}
+
+	n := 0
+	for _, todo := range ts.storage.List() {
+		labels, changed := apply(todo.Labels)
+		if !changed {
+			continue
+		}
+		todo.Labels = labels
+		if err := ts.storage.Save(&todo); err != nil {
+			return n, false, err
+		}
+		n++
+	}
+
+	allowed, listChanged := apply(ts.storage.AllowedLabels())
+	if listChanged {
+		ts.storage.SetAllowedLabels(allowed)
+	}
+	if n > 0 || listChanged {
+		ts.record("%s in %s (%d todos)", description, ts.File(), n)
+	}
+	return n, listChanged, nil
+}
+
+// AllowedLabels returns the label allow-list of the file, or nil if any
+// label is allowed.
+func (ts *TodoService) AllowedLabels() []string {
+	return ts.storage.AllowedLabels()
+}
+
+// SetAllowedLabels restricts the labels todos of the file may have; an
+// empty list allows any label. An allowed label also allows the labels
+// below it. Every label already in use must be allowed.
+func (ts *TodoService) SetAllowedLabels(labels []string) error {
+	labels = utils.ValidateLabels(strings.Join(labels, ","))
+
+	if len(labels) > 0 {
+		var missing []string
+		for _, todo := range ts.storage.List() {
+			for _, label := range todo.Labels {
+				if allowedSpelling(labels, label) == "" && !hasLabel(missing, label) {
+					missing = append(missing, label)
+				}
+			}
+		}
+		if len(missing) > 0 {
+			sort.Strings(missing)
+			return fmt.Errorf("%w: todos of %s use %s; rename or delete them first", errors.ErrLabelNotAllowed, ts.File(), strings.Join(missing, ", "))
+		}
+	}
+
+	ts.storage.SetAllowedLabels(labels)
+	if len(labels) == 0 {
+		ts.record("allow any label in %s", ts.File())
+	} else {
+		ts.record("allow labels %s in %s", strings.Join(labels, ","), ts.File())
+	}
+	return nil
+}
+
+// allowLabels checks labels against the allow-list of the file, if it has
+// one, and returns them spelled as in the allow-list.
+func (ts *TodoService) allowLabels(labels []string) ([]string, error) {
+	allowed := ts.storage.AllowedLabels()
+	if len(allowed) == 0 {
+		return labels, nil
+	}
+
+	out := make([]string, 0, len(labels))
+	var rejected []string
+	for _, label := range labels {
+		match := allowedSpelling(allowed, label)
+		if match == "" {
+			rejected = append(rejected, label)
+			continue
+		}
+		out = append(out, match)
+	}
+	if len(rejected) > 0 {
+		return nil, fmt.Errorf("%w in %s: %s (allowed: %s)", errors.ErrLabelNotAllowed, ts.File(), strings.Join(rejected, ", "), strings.Join(allowed, ", "))
+	}
+	return out, nil
+}
+
+// allowedSpelling returns label spelled as in allowed, or "" if it isn't
+// allowed. An exact match wins over an allowed ancestor.
+func allowedSpelling(allowed []string, label string) string {
+	for _, a := range allowed {
+		if utils.SameLabel(a, label) {
+			return a
+		}
+	}
+	for _, a := range allowed {
+		if utils.LabelWithin(label, a) {
+			return utils.ReparentLabel(label, a, a)
+		}
+	}
+	return ""
+}
+
+// LabelNode is a level of the label hierarchy. Open and Done count the todos
+// with the label or one below it, each todo once.
+type LabelNode struct {
+	// Name is the last level of Label, e.g. "billing" for
+	// "clients/acme/billing".
+	Name     string
+	Label    string
+	Open     int
+	Done     int
+	Children []*LabelNode
+}
+
+// LabelTree arranges the labels of todos into their hierarchy, with the
+// counts rolled up at each level. Roots and children are sorted by name.
+func LabelTree(todos []types.Todo) []*LabelNode {
+	root := &LabelNode{}
+	nodes := make(map[string]*LabelNode)
+
+	for _, todo := range todos {
+		counted := make(map[*LabelNode]bool)
+		for _, label := range utils.ValidateLabels(strings.Join(todo.Labels, ",")) {
+			parent := root
+			levels := strings.Split(label, "/")
+			for i, level := range levels {
+				key := utils.NormalizeLabel(strings.Join(levels[:i+1], "/"))
+				node := nodes[key]
+				if node == nil {
+					// Spell the path as the parent is spelled, so a level
+					// reads the same in the labels of all its children.
+					node = &LabelNode{Name: level, Label: level}
+					if parent != root {
+						node.Label = parent.Label + "/" + level
+					}
+					nodes[key] = node
+					parent.Children = append(parent.Children, node)
+				}
+				if !counted[node] {
+					counted[node] = true
+					if todo.Completed {
+						node.Done++
+					} else {
+						node.Open++
+					}
+				}
+				parent = node
+			}
+		}
+	}
+
+	var sortNodes func(nodes []*LabelNode)
+	sortNodes = func(nodes []*LabelNode) {
+		sort.Slice(nodes, func(i, j int) bool {
+			return utils.NormalizeLabel(nodes[i].Name) < utils.NormalizeLabel(nodes[j].Name)
+		})
+		for _, node := range nodes {
+			sortNodes(node.Children)
+		}
+	}
+	sortNodes(root.Children)
+	return root.Children
+}
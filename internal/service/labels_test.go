@@ -0,0 +1,243 @@
+package service
+
+import (
+	stderrors "errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/Ng1n3/go-todo/internal/config"
+	"github.com/Ng1n3/go-todo/internal/errors"
+	"github.com/Ng1n3/go-todo/internal/types"
+)
+
+// testConfig returns a configuration keeping everything in a temporary
+// directory.
+func testConfig(t *testing.T) *config.Config {
+	t.Helper()
+	dir := t.TempDir()
+	cfg := config.Default()
+	cfg.StorageDir = filepath.Join(dir, "storage")
+	cfg.SummaryFile = filepath.Join(dir, "summary.json")
+	if err := os.MkdirAll(cfg.StorageDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	return cfg
+}
+
+// openFile opens a todo file of the workspace and adds todos with the given
+// labels to it.
+func openFile(t *testing.T, w *Workspace, name string, labels ...string) *TodoService {
+	t.Helper()
+	ts, err := w.Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, l := range labels {
+		if _, err := ts.CreateTodo("task "+l, "2026-03-05", "no", types.Medium, l, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := ts.Save(); err != nil {
+		t.Fatal(err)
+	}
+	return ts
+}
+
+func labelsOf(t *testing.T, w *Workspace, name string) []string {
+	t.Helper()
+	ts, err := w.Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var labels []string
+	for _, todo := range ts.ListTodos() {
+		labels = append(labels, todo.Labels...)
+	}
+	slices.Sort(labels)
+	return labels
+}
+
+func TestTransferChecksAllowedLabels(t *testing.T) {
+	tests := []struct {
+		name    string
+		label   string
+		want    []string
+		wantErr bool
+	}{
+		{name: "allowed", label: "work", want: []string{"work"}},
+		{name: "allowed below, respelled", label: "WORK/Release", want: []string{"work/Release"}},
+		{name: "not allowed", label: "home", wantErr: true},
+	}
+	for _, tt := range tests {
+		for _, verb := range []string{"move", "copy"} {
+			t.Run(verb+" "+tt.name, func(t *testing.T) {
+				w := NewWorkspace(testConfig(t))
+				src := openFile(t, w, "src.json", tt.label)
+				dst := openFile(t, w, "dst.json")
+				if err := dst.SetAllowedLabels([]string{"work"}); err != nil {
+					t.Fatal(err)
+				}
+				if err := dst.Save(); err != nil {
+					t.Fatal(err)
+				}
+
+				id := src.ListTodos()[0].ID
+				transfer := CopyTodos
+				if verb == "move" {
+					transfer = MoveTodos
+				}
+				_, err := transfer(src, dst, []string{id}, ConflictRename)
+				if tt.wantErr {
+					if !stderrors.Is(err, errors.ErrLabelNotAllowed) {
+						t.Fatalf("transfer = %v, want ErrLabelNotAllowed", err)
+					}
+					if got := labelsOf(t, w, "dst.json"); len(got) != 0 {
+						t.Errorf("dst.json has labels %v after a failed transfer", got)
+					}
+					if got := labelsOf(t, w, "src.json"); !slices.Equal(got, []string{tt.label}) {
+						t.Errorf("src.json has labels %v after a failed transfer, want %v", got, []string{tt.label})
+					}
+					return
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got := labelsOf(t, w, "dst.json"); !slices.Equal(got, tt.want) {
+					t.Errorf("dst.json has labels %v, want %v", got, tt.want)
+				}
+			})
+		}
+	}
+}
+
+func TestRestoreTrashChecksAllowedLabels(t *testing.T) {
+	w := NewWorkspace(testConfig(t))
+	ts := openFile(t, w, "work.json", "home")
+	if err := ts.DeleteTodo(ts.ListTodos()[0].ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := ts.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if err := ts.SetAllowedLabels([]string{"work"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := ts.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	items, err := w.Trash()
+	if err != nil || len(items) != 1 {
+		t.Fatalf("Trash() = %v, %v; want one item", items, err)
+	}
+	if _, _, err := w.RestoreTrash(items[0].ID); !stderrors.Is(err, errors.ErrLabelNotAllowed) {
+		t.Fatalf("RestoreTrash = %v, want ErrLabelNotAllowed", err)
+	}
+	if got := labelsOf(t, w, "work.json"); len(got) != 0 {
+		t.Errorf("work.json has labels %v after a failed restore", got)
+	}
+	if items, _ := w.Trash(); len(items) != 1 {
+		t.Errorf("the trash has %d items after a failed restore, want 1", len(items))
+	}
+}
+
+func TestRenameLabelChangesNothingWhenAFileFailsToLoad(t *testing.T) {
+	cfg := testConfig(t)
+	w := NewWorkspace(cfg)
+	openFile(t, w, "a.json", "clients")
+	locked := openFile(t, w, "b.json", "clients")
+	if err := locked.Encrypt("secret"); err != nil {
+		t.Fatal(err)
+	}
+	if err := locked.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg.Passphrase = func(string, int) (string, error) { return "", errors.ErrPassphraseRequired }
+	if _, err := w.RenameLabel("clients", "customers"); !stderrors.Is(err, errors.ErrPassphraseRequired) {
+		t.Fatalf("RenameLabel = %v, want ErrPassphraseRequired", err)
+	}
+	if got := labelsOf(t, w, "a.json"); !slices.Equal(got, []string{"clients"}) {
+		t.Errorf("a.json has labels %v after a failed rename, want [clients]", got)
+	}
+
+	cfg.Passphrase = func(string, int) (string, error) { return "secret", nil }
+	if _, err := w.RenameLabel("Clients", "customers"); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"a.json", "b.json"} {
+		if got := labelsOf(t, w, name); !slices.Equal(got, []string{"customers"}) {
+			t.Errorf("%s has labels %v, want [customers]", name, got)
+		}
+	}
+}
+
+func TestLabelTree(t *testing.T) {
+	todos := []types.Todo{
+		{Labels: []string{"clients/acme/billing", "clients/acme"}},
+		{Labels: []string{"Clients/Globex"}, Completed: true},
+		{Labels: []string{"clients/acme/support"}, Completed: true},
+		{Labels: []string{"home"}},
+		{},
+	}
+
+	type count struct{ open, done int }
+	got := make(map[string]count)
+	var walk func(nodes []*LabelNode)
+	var order []string
+	walk = func(nodes []*LabelNode) {
+		for _, node := range nodes {
+			got[node.Label] = count{node.Open, node.Done}
+			order = append(order, node.Name)
+			walk(node.Children)
+		}
+	}
+	walk(LabelTree(todos))
+
+	want := map[string]count{
+		"clients":              {1, 2},
+		"clients/acme":         {1, 1},
+		"clients/acme/billing": {1, 0},
+		"clients/acme/support": {0, 1},
+		"clients/Globex":       {0, 1}, // spelled as its parent
+		"home":                 {1, 0},
+	}
+	if len(got) != len(want) {
+		t.Errorf("LabelTree() nodes = %v, want %v", got, want)
+	}
+	for label, c := range want {
+		if got[label] != c {
+			t.Errorf("%s: open %d, done %d, want %d and %d", label, got[label].open, got[label].done, c.open, c.done)
+		}
+	}
+	if wantOrder := []string{"clients", "acme", "billing", "support", "Globex", "home"}; !slices.Equal(order, wantOrder) {
+		t.Errorf("LabelTree() order = %v, want %v", order, wantOrder)
+	}
+}
+
+func TestRenameLabelToDescendant(t *testing.T) {
+	w := NewWorkspace(testConfig(t))
+	ts := openFile(t, w, "work.json", "a", "a/b", "a/c", "ab")
+	if err := ts.SetAllowedLabels([]string{"a", "ab"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := ts.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := w.RenameLabel("a", "a/b"); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := labelsOf(t, w, "work.json"), []string{"a/b", "a/b/b", "a/b/c", "ab"}; !slices.Equal(got, want) {
+		t.Errorf("labels = %v, want %v", got, want)
+	}
+	ts, err := w.Open("work.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := ts.AllowedLabels(), []string{"a/b", "ab"}; !slices.Equal(got, want) {
+		t.Errorf("allowed labels = %v, want %v", got, want)
+	}
+}
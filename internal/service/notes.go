@@ -0,0 +1,89 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Ng1n3/go-todo/internal/errors"
+	"github.com/Ng1n3/go-todo/internal/types"
+	"github.com/Ng1n3/go-todo/internal/utils"
+)
+
+// SetNotes replaces the notes of a todo. Blank lines around the text are
+// dropped, so notes written in an editor don't grow a trailing newline.
+func (ts *TodoService) SetNotes(id, notes string) error {
+	return ts.UpdateTodo(id, map[string]any{"notes": notes})
+}
+
+func cleanNotes(notes string) string {
+	return strings.TrimRight(strings.TrimLeft(notes, "\r\n"), " \t\r\n")
+}
+
+// AddLink attaches a link to a todo. When link.Kind is empty it is guessed
+// from the target: a URL, the ID of another todo in the file, or else a
+// file path. Todo links must point to another todo of the same file.
+func (ts *TodoService) AddLink(id string, link types.Link) error {
+	todo, err := ts.storage.Get(id)
+	if err != nil {
+		return err
+	}
+
+	if link.Kind == "" {
+		link.Kind = ts.guessLinkKind(strings.TrimSpace(link.Target))
+	}
+	link, err = utils.ValidateLink(link)
+	if err != nil {
+		return err
+	}
+
+	if link.Kind == types.LinkTodo {
+		target, err := ts.storage.Get(link.Target)
+		if err != nil {
+			return fmt.Errorf("%w: %s: %w", errors.ErrInvalidLink, link.Target, err)
+		}
+		if target.ID == todo.ID {
+			return fmt.Errorf("%w: a todo can't link to itself", errors.ErrInvalidLink)
+		}
+	}
+	for _, existing := range todo.Links {
+		if existing.Kind == link.Kind && existing.Target == link.Target {
+			return fmt.Errorf("%w: %s already links to %s", errors.ErrInvalidLink, todo.ID, link.Target)
+		}
+	}
+
+	todo.Links = append(todo.Links, link)
+	if err := ts.storage.Save(&todo); err != nil {
+		return err
+	}
+	ts.record("link todo %s to %s %s", todo.ID, link.Kind, link.Target)
+	return nil
+}
+
+// RemoveLink removes the link at index n, counting from 1, from a todo.
+func (ts *TodoService) RemoveLink(id string, n int) (types.Link, error) {
+	todo, err := ts.storage.Get(id)
+	if err != nil {
+		return types.Link{}, err
+	}
+	if n < 1 || n > len(todo.Links) {
+		return types.Link{}, fmt.Errorf("%w: %s has no link %d", errors.ErrInvalidInput, todo.ID, n)
+	}
+
+	link := todo.Links[n-1]
+	todo.Links = append(todo.Links[:n-1:n-1], todo.Links[n:]...)
+	if err := ts.storage.Save(&todo); err != nil {
+		return types.Link{}, err
+	}
+	ts.record("unlink todo %s from %s %s", todo.ID, link.Kind, link.Target)
+	return link, nil
+}
+
+func (ts *TodoService) guessLinkKind(target string) types.LinkKind {
+	if strings.Contains(target, "://") || strings.HasPrefix(target, "mailto:") {
+		return types.LinkURL
+	}
+	if _, err := ts.storage.Get(target); err == nil {
+		return types.LinkTodo
+	}
+	return types.LinkFile
+}
@@ -0,0 +1,185 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/Ng1n3/go-todo/internal/errors"
+	"github.com/Ng1n3/go-todo/internal/types"
+)
+
+// maxCandidates caps the number of todos returned with an ambiguous match.
+const maxCandidates = 10
+
+// AmbiguousRefError is returned when a reference matches several todos.
+// Candidates are ordered from best to worst match.
+type AmbiguousRefError struct {
+	Ref        string
+	Candidates []types.Todo
+}
+
+func (e *AmbiguousRefError) Error() string {
+	return fmt.Sprintf("%q matches %d todos", e.Ref, len(e.Candidates))
+}
+
+func (e *AmbiguousRefError) Unwrap() error {
+	return errors.ErrAmbiguousTodo
+}
+
+// Resolver turns a user supplied reference into a todo. A reference can be,
+// in order of precedence:
+//
+//   - an exact todo ID
+//   - a row number from the last displayed table, e.g. "3" or "#3"
+//   - a unique ID prefix
+//   - a fuzzy match on the task text
+type Resolver struct {
+	todos []types.Todo
+	rows  []types.Todo
+}
+
+// NewResolver creates a resolver over todos. rows are the todos in the order
+// they were last displayed and may be nil.
+func NewResolver(todos, rows []types.Todo) *Resolver {
+	return &Resolver{todos: todos, rows: rows}
+}
+
+func (r *Resolver) Resolve(ref string) (types.Todo, error) {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return types.Todo{}, errors.ErrInvalidInput
+	}
+
+	for _, todo := range r.todos {
+		if todo.ID == ref {
+			return todo, nil
+		}
+	}
+
+	if todo, ok, err := r.row(ref); ok || err != nil {
+		return todo, err
+	}
+
+	if matches := r.byIDPrefix(ref); len(matches) > 0 {
+		return r.pick(ref, matches)
+	}
+
+	return r.pick(ref, r.byTask(ref))
+}
+
+// row resolves "#n" or "n" against the displayed rows. A bare number only
+// counts as a row if it is within range.
+func (r *Resolver) row(ref string) (types.Todo, bool, error) {
+	explicit := strings.HasPrefix(ref, "#")
+	n, err := strconv.Atoi(strings.TrimPrefix(ref, "#"))
+	if err != nil {
+		return types.Todo{}, false, nil
+	}
+
+	if n >= 1 && n <= len(r.rows) {
+		return r.rows[n-1], true, nil
+	}
+	if explicit {
+		return types.Todo{}, false, fmt.Errorf("row %d is not in the last displayed table: %w", n, errors.ErrTodoNotFound)
+	}
+	return types.Todo{}, false, nil
+}
+
+func (r *Resolver) byIDPrefix(ref string) []types.Todo {
+	var exact, folded []types.Todo
+	for _, todo := range r.todos {
+		if strings.HasPrefix(todo.ID, ref) {
+			exact = append(exact, todo)
+		} else if strings.HasPrefix(strings.ToLower(todo.ID), strings.ToLower(ref)) {
+			folded = append(folded, todo)
+		}
+	}
+	if len(exact) > 0 {
+		return exact
+	}
+	return folded
+}
+
+// byTask returns the todos whose task contains ref, or failing that, the
+// todos whose task contains the characters of ref in order.
+func (r *Resolver) byTask(ref string) []types.Todo {
+	needle := strings.ToLower(ref)
+
+	var contains []types.Todo
+	for _, todo := range r.todos {
+		if strings.Contains(strings.ToLower(todo.Task), needle) {
+			contains = append(contains, todo)
+		}
+	}
+	if len(contains) > 0 {
+		return contains
+	}
+
+	type scored struct {
+		todo  types.Todo
+		score int
+	}
+	var fuzzy []scored
+	for _, todo := range r.todos {
+		if score, ok := FuzzyScore(needle, todo.Task); ok {
+			fuzzy = append(fuzzy, scored{todo, score})
+		}
+	}
+	sort.SliceStable(fuzzy, func(i, j int) bool { return fuzzy[i].score > fuzzy[j].score })
+
+	matches := make([]types.Todo, len(fuzzy))
+	for i, s := range fuzzy {
+		matches[i] = s.todo
+	}
+	return matches
+}
+
+func (r *Resolver) pick(ref string, matches []types.Todo) (types.Todo, error) {
+	switch len(matches) {
+	case 0:
+		return types.Todo{}, fmt.Errorf("no todo matches %q: %w", ref, errors.ErrTodoNotFound)
+	case 1:
+		return matches[0], nil
+	}
+
+	if len(matches) > maxCandidates {
+		matches = matches[:maxCandidates]
+	}
+	return types.Todo{}, &AmbiguousRefError{Ref: ref, Candidates: matches}
+}
+
+// FuzzyScore reports whether every character of pattern appears in text in
+// order, ignoring case, and scores the match. Consecutive characters and
+// characters at the start of a word score higher.
+func FuzzyScore(pattern, text string) (int, bool) {
+	p := []rune(strings.ToLower(pattern))
+	t := []rune(strings.ToLower(text))
+	if len(p) == 0 {
+		return 0, false
+	}
+
+	score, pi, prev := 0, 0, -2
+	for ti := 0; ti < len(t) && pi < len(p); ti++ {
+		if t[ti] != p[pi] {
+			continue
+		}
+
+		score++
+		if ti == prev+1 {
+			score += 2
+		}
+		if ti == 0 || !unicode.IsLetter(t[ti-1]) && !unicode.IsDigit(t[ti-1]) {
+			score += 3
+		}
+		prev = ti
+		pi++
+	}
+
+	if pi < len(p) {
+		return 0, false
+	}
+	return score, true
+}
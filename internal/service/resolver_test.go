@@ -0,0 +1,80 @@
+package service
+
+import (
+	stderrors "errors"
+	"slices"
+	"testing"
+
+	"github.com/Ng1n3/go-todo/internal/errors"
+	"github.com/Ng1n3/go-todo/internal/types"
+)
+
+func TestResolve(t *testing.T) {
+	write := types.Todo{ID: "a1b2c3", Task: "write report"}
+	review := types.Todo{ID: "a1x9y8", Task: "review report"}
+	milk := types.Todo{ID: "123456", Task: "buy milk"}
+	odd := types.Todo{ID: "2", Task: "todo with a short id"}
+	todos := []types.Todo{write, review, milk, odd}
+	rows := []types.Todo{review, write, milk, odd}
+
+	tests := []struct {
+		name       string
+		ref        string
+		want       string
+		wantErr    error
+		candidates []string
+	}{
+		{name: "exact id", ref: "a1b2c3", want: "a1b2c3"},
+		{name: "exact id before row", ref: "2", want: "2"},
+		{name: "row before id prefix", ref: "1", want: "a1x9y8"},
+		{name: "explicit row", ref: "#3", want: "123456"},
+		{name: "explicit row out of range", ref: "#9", wantErr: errors.ErrTodoNotFound},
+		{name: "number past the rows is an id prefix", ref: "12", want: "123456"},
+		{name: "unique id prefix", ref: "a1b", want: "a1b2c3"},
+		{name: "id prefix ignoring case", ref: "A1X", want: "a1x9y8"},
+		{name: "ambiguous id prefix", ref: "a1", wantErr: errors.ErrAmbiguousTodo, candidates: []string{"a1b2c3", "a1x9y8"}},
+		{name: "task substring", ref: "MILK", want: "123456"},
+		{name: "ambiguous task substring", ref: "report", wantErr: errors.ErrAmbiguousTodo, candidates: []string{"a1b2c3", "a1x9y8"}},
+		{name: "fuzzy task", ref: "wtrp", want: "a1b2c3"},
+		{name: "no match", ref: "zzz", wantErr: errors.ErrTodoNotFound},
+		{name: "empty", ref: "  ", wantErr: errors.ErrInvalidInput},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewResolver(todos, rows).Resolve(tt.ref)
+			if tt.wantErr != nil {
+				if !stderrors.Is(err, tt.wantErr) {
+					t.Fatalf("Resolve(%q) error = %v, want %v", tt.ref, err, tt.wantErr)
+				}
+				var ambiguous *AmbiguousRefError
+				if stderrors.As(err, &ambiguous) {
+					var ids []string
+					for _, c := range ambiguous.Candidates {
+						ids = append(ids, c.ID)
+					}
+					if !slices.Equal(ids, tt.candidates) {
+						t.Errorf("candidates = %v, want %v", ids, tt.candidates)
+					}
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Resolve(%q): %v", tt.ref, err)
+			}
+			if got.ID != tt.want {
+				t.Errorf("Resolve(%q) = %s, want %s", tt.ref, got.ID, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveWithoutRows(t *testing.T) {
+	todos := []types.Todo{{ID: "123456", Task: "buy milk"}, {ID: "777777", Task: "call 1 person"}}
+	got, err := NewResolver(todos, nil).Resolve("1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.ID != "123456" {
+		t.Errorf("Resolve(\"1\") without rows = %s, want the id prefix match", got.ID)
+	}
+}
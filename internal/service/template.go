@@ -0,0 +1,226 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Ng1n3/go-todo/internal/config"
+	"github.com/Ng1n3/go-todo/internal/errors"
+	"github.com/Ng1n3/go-todo/internal/store"
+	"github.com/Ng1n3/go-todo/internal/types"
+	"github.com/Ng1n3/go-todo/internal/utils"
+)
+
+// Templates returns the templates in the storage directory, sorted by name.
+func (w *Workspace) Templates() ([]types.Template, error) {
+	files, err := store.ListFiles(filepath.Join(w.config.StorageDir, config.TemplateDir))
+	if err != nil {
+		return nil, err
+	}
+
+	templates := make([]types.Template, 0, len(files))
+	for _, file := range files {
+		t, err := w.Template(strings.TrimSuffix(file, ".json"))
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, *t)
+	}
+	return templates, nil
+}
+
+// Template loads the named template.
+func (w *Workspace) Template(name string) (*types.Template, error) {
+	filename, err := utils.NormalizeFileName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(w.config.GetTemplatePath(filename))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%w: %s", errors.ErrTemplateNotFound, strings.TrimSuffix(filename, ".json"))
+		}
+		return nil, fmt.Errorf("failed to read template: %w", err)
+	}
+
+	var t types.Template
+	if err := json.Unmarshal(data, &t); err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", errors.ErrInvalidTemplate, filename, err)
+	}
+	// The file name is authoritative, so a copied file doesn't shadow the
+	// template it was copied from.
+	t.Name = strings.TrimSuffix(filename, ".json")
+	return &t, nil
+}
+
+// SaveTemplate validates a template and writes it to the templates
+// directory under its name, replacing a template of the same name.
+func (w *Workspace) SaveTemplate(t *types.Template) error {
+	filename, err := utils.NormalizeFileName(t.Name)
+	if err != nil {
+		return fmt.Errorf("%w: the template needs a name", errors.ErrInvalidTemplate)
+	}
+	t.Name = strings.TrimSuffix(filename, ".json")
+	if err := validateTemplate(t); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(t, "", " ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal template: %w", err)
+	}
+	path := w.config.GetTemplatePath(filename)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create templates directory: %w", err)
+	}
+	if err := store.WriteFileAtomic(path, data, w.config.FileMode); err != nil {
+		return fmt.Errorf("failed to write template: %w", err)
+	}
+	return nil
+}
+
+// DeleteTemplate removes the named template.
+func (w *Workspace) DeleteTemplate(name string) error {
+	filename, err := utils.NormalizeFileName(name)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(w.config.GetTemplatePath(filename)); err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("%w: %s", errors.ErrTemplateNotFound, strings.TrimSuffix(filename, ".json"))
+		}
+		return fmt.Errorf("failed to delete template: %w", err)
+	}
+	return nil
+}
+
+// validateTemplate checks the parts of a template that don't depend on its
+// variables, so mistakes show up when it is saved rather than applied.
+func validateTemplate(t *types.Template) error {
+	if len(t.Todos) == 0 {
+		return fmt.Errorf("%w: %s has no todos", errors.ErrInvalidTemplate, t.Name)
+	}
+	for _, v := range t.Variables {
+		if strings.TrimSpace(v.Name) == "" {
+			return fmt.Errorf("%w: a variable has no name", errors.ErrInvalidTemplate)
+		}
+	}
+
+	for i, todo := range t.Todos {
+		if _, err := utils.ValidateTask(todo.Task); err != nil {
+			return fmt.Errorf("%w: todo %d: %w", errors.ErrInvalidTemplate, i+1, err)
+		}
+		if _, err := templateDue(todo, time.Now()); err != nil {
+			return fmt.Errorf("%w: todo %d: %w", errors.ErrInvalidTemplate, i+1, err)
+		}
+		if todo.Priority != "" {
+			if err := todo.Priority.Validate(); err != nil {
+				return fmt.Errorf("%w: todo %d: %w", errors.ErrInvalidTemplate, i+1, err)
+			}
+		}
+		if _, err := utils.ValidateEstimate(todo.Estimate); err != nil {
+			return fmt.Errorf("%w: todo %d: %w", errors.ErrInvalidTemplate, i+1, err)
+		}
+	}
+	return nil
+}
+
+// templateDue returns the due date of a template todo applied on start.
+func templateDue(todo types.TemplateTodo, start time.Time) (time.Time, error) {
+	day := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC)
+	if strings.TrimSpace(todo.Due) == "" {
+		return day, nil
+	}
+	offset, err := utils.ParseDuration(todo.Due)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: due %q, use an offset such as +3d or -1w", errors.ErrInvalidDuration, todo.Due)
+	}
+	return day.Add(offset).Truncate(24 * time.Hour), nil
+}
+
+// ApplyTemplate creates the todos of a template, due relative to start, and
+// saves the file once. Placeholders are filled in from values, falling back
+// to the defaults of the template. If a placeholder has no value or a todo
+// is invalid, no todo is created; other unsaved changes to the file are
+// kept.
+func (ts *TodoService) ApplyTemplate(t *types.Template, values map[string]string, start time.Time) ([]types.Todo, error) {
+	filled := make(map[string]string)
+	var missing []string
+	for _, v := range t.Placeholders() {
+		value := strings.TrimSpace(values[v.Name])
+		if value == "" {
+			value = v.Default
+		}
+		if value == "" {
+			missing = append(missing, v.Name)
+			continue
+		}
+		filled[v.Name] = value
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("%w: %s", errors.ErrMissingVariable, strings.Join(missing, ", "))
+	}
+
+	existing := make(map[string]bool, ts.storage.Count())
+	for _, todo := range ts.storage.List() {
+		existing[todo.ID] = true
+	}
+	changes := len(ts.changes)
+
+	created, err := ts.createFromTemplate(t, filled, start)
+	if err != nil {
+		// Take back just the todos created so far.
+		for _, todo := range ts.storage.List() {
+			if !existing[todo.ID] {
+				ts.storage.Remove(todo.ID)
+			}
+		}
+		ts.changes = ts.changes[:changes]
+		return nil, err
+	}
+	if err := ts.Save(); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+func (ts *TodoService) createFromTemplate(t *types.Template, values map[string]string, start time.Time) ([]types.Todo, error) {
+	created := make([]types.Todo, 0, len(t.Todos))
+	for i, item := range t.Todos {
+		due, err := templateDue(item, start)
+		if err != nil {
+			return nil, fmt.Errorf("%w: todo %d: %w", errors.ErrInvalidTemplate, i+1, err)
+		}
+
+		labels := types.Fill(strings.Join(item.Labels, ","), values)
+		todo, err := ts.CreateTodo(types.Fill(item.Task, values), due.Format("2006-01-02"), "", item.Priority, labels, item.Estimate)
+		if err != nil {
+			return nil, fmt.Errorf("todo %d of template %s: %w", i+1, t.Name, err)
+		}
+
+		notes := types.Fill(item.Notes, values)
+		if len(item.Checklist) > 0 {
+			var list strings.Builder
+			for _, entry := range item.Checklist {
+				fmt.Fprintf(&list, "- [ ] %s\n", types.Fill(entry, values))
+			}
+			if notes != "" {
+				notes += "\n\n"
+			}
+			notes += list.String()
+		}
+		if notes != "" {
+			todo.Notes = cleanNotes(notes)
+			if err := ts.storage.Save(todo); err != nil {
+				return nil, fmt.Errorf("failed to save todo: %w", err)
+			}
+		}
+		created = append(created, *todo)
+	}
+	return created, nil
+}
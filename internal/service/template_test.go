@@ -0,0 +1,37 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Ng1n3/go-todo/internal/types"
+)
+
+func TestApplyTemplateFailureKeepsOtherChanges(t *testing.T) {
+	w := NewWorkspace(testConfig(t))
+	ts := openFile(t, w, "work.json", "saved")
+	unsaved, err := ts.CreateTodo("not saved yet", "2026-03-05", "no", types.Medium, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	changes := len(ts.changes)
+
+	tmpl := &types.Template{Name: "release", Todos: []types.TemplateTodo{
+		{Task: "release {{version}}"},
+		{Task: "{{version}}"}, // too short once filled in
+	}}
+	if _, err := ts.ApplyTemplate(tmpl, map[string]string{"version": "2"}, time.Now()); err == nil {
+		t.Fatal("ApplyTemplate() error = nil, want the invalid todo reported")
+	}
+
+	var tasks []string
+	for _, todo := range ts.ListTodos() {
+		tasks = append(tasks, todo.Task)
+	}
+	if len(tasks) != 2 || tasks[0] != "task saved" || tasks[1] != unsaved.Task {
+		t.Errorf("todos after a failed ApplyTemplate = %v, want the saved and the unsaved todo", tasks)
+	}
+	if len(ts.changes) != changes {
+		t.Errorf("recorded changes = %v, want the template's taken back", ts.changes)
+	}
+}
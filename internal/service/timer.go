@@ -0,0 +1,166 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/Ng1n3/go-todo/internal/errors"
+	"github.com/Ng1n3/go-todo/internal/store"
+	"github.com/Ng1n3/go-todo/internal/types"
+)
+
+// Timer is the running time tracking timer. Only one timer runs at a time
+// across all todo files. It is kept in the state directory, so it keeps
+// running when the app exits.
+type Timer struct {
+	File   string `json:"file"`
+	TodoID string `json:"todo_id"`
+	// Task is empty when the todo is in an encrypted file.
+	Task  string    `json:"task,omitempty"`
+	Start time.Time `json:"start"`
+}
+
+// Elapsed returns how long the timer has been running at now.
+func (t Timer) Elapsed(now time.Time) time.Duration {
+	return now.Sub(t.Start)
+}
+
+func (w *Workspace) timerPath() string {
+	return w.config.GetStatePath("timer.json")
+}
+
+// RunningTimer returns the running timer, or nil if there is none.
+func (w *Workspace) RunningTimer() (*Timer, error) {
+	data, err := os.ReadFile(w.timerPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read timer: %w", err)
+	}
+
+	var timer Timer
+	if err := json.Unmarshal(data, &timer); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal timer: %w", err)
+	}
+	return &timer, nil
+}
+
+// StartTimer starts timing the todo with the given ID in the file of ts. It
+// fails with ErrTimerRunning if a timer is already running.
+func (w *Workspace) StartTimer(ts *TodoService, id string, now time.Time) (Timer, error) {
+	running, err := w.RunningTimer()
+	if err != nil {
+		return Timer{}, err
+	}
+	if running != nil {
+		return Timer{}, fmt.Errorf("%w on %s in %s since %s", errors.ErrTimerRunning,
+			running.TodoID, running.File, running.Start.Format("2006-01-02 15:04"))
+	}
+
+	todo, err := ts.GetTodo(id)
+	if err != nil {
+		return Timer{}, fmt.Errorf("%s in %s: %w", id, ts.File(), err)
+	}
+
+	timer := Timer{File: ts.File(), TodoID: todo.ID, Start: now}
+	if !ts.Encrypted() {
+		timer.Task = todo.Task
+	}
+	if err := w.writeTimer(timer); err != nil {
+		return Timer{}, err
+	}
+	return timer, nil
+}
+
+// StopTimer stops the running timer and logs the time on its todo with an
+// optional note. If the todo is no longer in the file the timer was started
+// in, the time goes to the todo file or archive it was moved to, and the
+// returned timer names that file. The timer keeps running when the todo
+// can't be found anywhere, so the time can still be logged by hand.
+func (w *Workspace) StopTimer(note string, now time.Time) (Timer, types.TimeEntry, error) {
+	timer, err := w.requireTimer()
+	if err != nil {
+		return Timer{}, types.TimeEntry{}, err
+	}
+
+	entry := types.TimeEntry{Start: timer.Start, End: now, Note: note}
+	ts, err := w.findTodo(timer.File, timer.TodoID, timer.Task)
+	if err != nil {
+		return timer, entry, fmt.Errorf("%w; log the time by hand or cancel the timer", err)
+	}
+	timer.File = ts.File()
+	if err := ts.LogTime(timer.TodoID, entry); err != nil {
+		return timer, entry, fmt.Errorf("failed to log time in %s: %w", timer.File, err)
+	}
+	if err := ts.Save(); err != nil {
+		return timer, entry, err
+	}
+	return timer, entry, w.clearTimer()
+}
+
+// CancelTimer stops the running timer without logging any time.
+func (w *Workspace) CancelTimer() (Timer, error) {
+	timer, err := w.requireTimer()
+	if err != nil {
+		return Timer{}, err
+	}
+	return timer, w.clearTimer()
+}
+
+func (w *Workspace) requireTimer() (Timer, error) {
+	timer, err := w.RunningTimer()
+	if err != nil {
+		return Timer{}, err
+	}
+	if timer == nil {
+		return Timer{}, errors.ErrNoTimer
+	}
+	return *timer, nil
+}
+
+func (w *Workspace) writeTimer(timer Timer) error {
+	data, err := json.MarshalIndent(timer, "", " ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal timer: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(w.timerPath()), 0755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+	if err := store.WriteFileAtomic(w.timerPath(), data, w.config.FileMode); err != nil {
+		return fmt.Errorf("failed to write timer: %w", err)
+	}
+	return nil
+}
+
+func (w *Workspace) clearTimer() error {
+	if err := os.Remove(w.timerPath()); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to clear timer: %w", err)
+	}
+	return nil
+}
+
+// LogTime adds a time entry to a todo. The file is not saved.
+func (ts *TodoService) LogTime(id string, entry types.TimeEntry) error {
+	if !entry.End.After(entry.Start) {
+		return fmt.Errorf("%w: a time entry must end after it starts", errors.ErrInvalidInput)
+	}
+
+	todo, err := ts.storage.Get(id)
+	if err != nil {
+		return err
+	}
+
+	todo.TimeEntries = append(todo.TimeEntries, entry)
+	sort.Slice(todo.TimeEntries, func(i, j int) bool { return todo.TimeEntries[i].Start.Before(todo.TimeEntries[j].Start) })
+	if err := ts.storage.Save(&todo); err != nil {
+		return err
+	}
+
+	ts.record("log %s on todo %s: %s", entry.Duration().Round(time.Second), todo.ID, todo.Task)
+	return nil
+}
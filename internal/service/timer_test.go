@@ -0,0 +1,121 @@
+package service
+
+import (
+	stderrors "errors"
+	"testing"
+	"time"
+
+	"github.com/Ng1n3/go-todo/internal/errors"
+)
+
+func TestStopTimerFollowsMovedTodo(t *testing.T) {
+	tests := []struct {
+		name     string
+		change   func(t *testing.T, w *Workspace, ts *TodoService, id string)
+		wantFile string
+		wantErr  error
+	}{
+		{
+			name:     "unchanged",
+			change:   func(t *testing.T, w *Workspace, ts *TodoService, id string) {},
+			wantFile: "work.json",
+		},
+		{
+			name: "moved",
+			change: func(t *testing.T, w *Workspace, ts *TodoService, id string) {
+				if _, err := MoveTodos(ts, openFile(t, w, "home.json"), []string{id}, ConflictRename); err != nil {
+					t.Fatal(err)
+				}
+			},
+			wantFile: "home.json",
+		},
+		{
+			name: "archived",
+			change: func(t *testing.T, w *Workspace, ts *TodoService, id string) {
+				if _, err := ts.Archive([]string{id}); err != nil {
+					t.Fatal(err)
+				}
+			},
+			wantFile: "archive/work.json",
+		},
+		{
+			name: "deleted",
+			change: func(t *testing.T, w *Workspace, ts *TodoService, id string) {
+				if err := ts.DeleteTodo(id); err != nil {
+					t.Fatal(err)
+				}
+				if err := ts.Save(); err != nil {
+					t.Fatal(err)
+				}
+			},
+			wantErr: errors.ErrTodoNotFound,
+		},
+		{
+			name: "file deleted",
+			change: func(t *testing.T, w *Workspace, ts *TodoService, id string) {
+				if _, err := w.DeleteFile("work.json"); err != nil {
+					t.Fatal(err)
+				}
+			},
+			wantErr: errors.ErrTodoNotFound,
+		},
+		{
+			name: "copied twice",
+			change: func(t *testing.T, w *Workspace, ts *TodoService, id string) {
+				for _, name := range []string{"home.json", "side.json"} {
+					if _, err := CopyTodos(ts, openFile(t, w, name), []string{id}, ConflictRename); err != nil {
+						t.Fatal(err)
+					}
+				}
+				if _, err := w.DeleteFile("work.json"); err != nil {
+					t.Fatal(err)
+				}
+			},
+			wantErr: errors.ErrAmbiguousTodo,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := NewWorkspace(testConfig(t))
+			ts := openFile(t, w, "work.json", "a")
+			id := ts.ListTodos()[0].ID
+			start := time.Date(2026, 3, 5, 9, 0, 0, 0, time.UTC)
+			if _, err := w.StartTimer(ts, id, start); err != nil {
+				t.Fatal(err)
+			}
+
+			tt.change(t, w, ts, id)
+			timer, entry, err := w.StopTimer("note", start.Add(time.Hour))
+			if tt.wantErr != nil {
+				if !stderrors.Is(err, tt.wantErr) {
+					t.Fatalf("StopTimer() error = %v, want %v", err, tt.wantErr)
+				}
+				if running, err := w.RunningTimer(); err != nil || running == nil {
+					t.Errorf("RunningTimer() = %v, %v after a failed stop, want the timer kept", running, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if timer.File != tt.wantFile {
+				t.Errorf("timer logged in %s, want %s", timer.File, tt.wantFile)
+			}
+
+			logged, err := w.Open(tt.wantFile)
+			if err != nil {
+				t.Fatal(err)
+			}
+			todo, err := logged.GetTodo(id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(todo.TimeEntries) != 1 || todo.TimeEntries[0] != entry {
+				t.Errorf("time entries in %s = %v, want [%v]", tt.wantFile, todo.TimeEntries, entry)
+			}
+			if running, err := w.RunningTimer(); err != nil || running != nil {
+				t.Errorf("RunningTimer() = %v, %v after stopping, want none", running, err)
+			}
+		})
+	}
+}
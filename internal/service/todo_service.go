@@ -4,7 +4,8 @@
 // The TodoService type exposes high-level operations for managing todos such as
 // creating, updating, deleting, and listing tasks. It validates input using the
 // utils package, interacts with the store package for persistence, and applies
-// application-level rules like default priorities and summary file generation.
+// application-level rules like default priorities and keeps the workspace
+// summary index up to date.
 //
 // In short, service orchestrates todo management while keeping validation and
 // persistence concerns separated into their respective packages.
@@ -12,10 +13,13 @@ package service
 
 import (
 	"fmt"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/Ng1n3/go-todo/internal/config"
 	"github.com/Ng1n3/go-todo/internal/store"
+	"github.com/Ng1n3/go-todo/internal/summary"
 	"github.com/Ng1n3/go-todo/internal/types"
 	"github.com/Ng1n3/go-todo/internal/utils"
 )
@@ -23,6 +27,9 @@ import (
 type TodoService struct {
 	storage *store.TodoStorage
 	config  *config.Config
+	// changes describes the modifications since the last Save, for the
+	// commit message when git autocommit is enabled.
+	changes []string
 }
 
 func NewTodoService(filename string, cfg *config.Config) (*TodoService, error) {
@@ -41,7 +48,7 @@ func NewTodoService(filename string, cfg *config.Config) (*TodoService, error) {
 	}, nil
 }
 
-func (ts *TodoService) CreateTodo(task, dueDate, completed string, priority types.Priority, labels string) (*types.Todo, error) {
+func (ts *TodoService) CreateTodo(task, dueDate, completed string, priority types.Priority, labels, estimate string) (*types.Todo, error) {
 
 	validTask, err := utils.ValidateTask(task)
 	if err != nil {
@@ -66,7 +73,15 @@ func (ts *TodoService) CreateTodo(task, dueDate, completed string, priority type
 		return nil, err
 	}
 
-	validLabels := utils.ValidateLabels(labels)
+	validLabels, err := ts.allowLabels(utils.ValidateLabels(labels))
+	if err != nil {
+		return nil, err
+	}
+
+	validEstimate, err := utils.ValidateEstimate(estimate)
+	if err != nil {
+		return nil, err
+	}
 
 	todo := &types.Todo{
 		ID:        utils.GenerateID(6),
@@ -75,14 +90,17 @@ func (ts *TodoService) CreateTodo(task, dueDate, completed string, priority type
 		Completed: validCompleted,
 		DueDate:   validDate,
 		Priority:  priority,
+		Estimate:  validEstimate,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
+	ts.storage.Workflow().Adopt(todo, todo.CreatedAt)
 
 	if err := ts.storage.Save(todo); err != nil {
 		return nil, fmt.Errorf("failed to save todo: %w", err)
 	}
 
+	ts.record("add todo %s: %s", todo.ID, todo.Task)
 	return todo, nil
 
 }
@@ -92,6 +110,8 @@ func (ts *TodoService) UpdateTodo(id string, updates map[string]any) error {
 	if err != nil {
 		return err
 	}
+	before := todo
+	workflow := ts.storage.Workflow()
 
 	for field, value := range updates {
 		switch field {
@@ -103,6 +123,10 @@ func (ts *TodoService) UpdateTodo(id string, updates map[string]any) error {
 				}
 				todo.Task = validTask
 			}
+		case "notes":
+			if notes, ok := value.(string); ok {
+				todo.Notes = cleanNotes(notes)
+			}
 		case "due_date":
 			if dateStr, ok := value.(string); ok {
 				validDate, err := utils.ValidateDate(dateStr)
@@ -120,25 +144,109 @@ func (ts *TodoService) UpdateTodo(id string, updates map[string]any) error {
 			}
 
 		case "labels":
-			if labels, ok := value.(string); ok {
-				validatedLabels := utils.ValidateLabels(labels)
-				todo.Labels = validatedLabels
+			var validLabels []string
+			switch labels := value.(type) {
+			case string:
+				validLabels = utils.ValidateLabels(labels)
+			case []string:
+				validLabels = utils.ValidateLabels(strings.Join(labels, ","))
+			default:
+				continue
+			}
+			if todo.Labels, err = ts.allowLabels(validLabels); err != nil {
+				return err
+			}
+		case "estimate":
+			switch estimate := value.(type) {
+			case string:
+				validEstimate, err := utils.ValidateEstimate(estimate)
+				if err != nil {
+					return err
+				}
+				todo.Estimate = validEstimate
+			case *types.Estimate:
+				if estimate != nil {
+					if err := utils.CheckEstimate(*estimate); err != nil {
+						return err
+					}
+				}
+				todo.Estimate = estimate
 			}
 		case "completed":
-			if completed, ok := value.(string); ok {
+			switch completed := value.(type) {
+			case string:
 				validatedCompleted, err := utils.ValidateCompleted(completed)
 				if err != nil {
 					return err
 				}
-				todo.Completed = validatedCompleted
+				if err := workflow.Complete(&todo, validatedCompleted, time.Now()); err != nil {
+					return err
+				}
+			case bool:
+				if err := workflow.Complete(&todo, completed, time.Now()); err != nil {
+					return err
+				}
+			}
+		case "status":
+			var status types.Status
+			switch s := value.(type) {
+			case string:
+				status = types.ParseStatus(s)
+			case types.Status:
+				status = s
+			default:
+				continue
+			}
+			if err := workflow.Transition(&todo, status, time.Now()); err != nil {
+				return err
 			}
 		}
 	}
-	return ts.storage.Save(&todo)
+
+	if err := ts.storage.Save(&todo); err != nil {
+		return err
+	}
+
+	ts.record("update todo %s: %s", todo.ID, describeUpdate(before, todo))
+	return nil
+}
+
+// SetStatus moves a todo to another status of the file's workflow.
+func (ts *TodoService) SetStatus(id string, status types.Status) error {
+	return ts.UpdateTodo(id, map[string]any{"status": status})
+}
+
+// Workflow returns the status workflow of the file.
+func (ts *TodoService) Workflow() *types.Workflow {
+	return ts.storage.Workflow()
+}
+
+// SetWorkflow replaces the status workflow of the file; nil restores the
+// default. The file is not saved.
+func (ts *TodoService) SetWorkflow(w *types.Workflow) error {
+	if err := ts.storage.SetWorkflow(w); err != nil {
+		return err
+	}
+	if w == nil {
+		ts.record("reset workflow of %s", ts.File())
+	} else {
+		ts.record("set workflow of %s", ts.File())
+	}
+	return nil
 }
 
 func (ts *TodoService) DeleteTodo(id string) error {
-	return ts.storage.Delete(id)
+	todo, err := ts.storage.Get(id)
+	if err != nil {
+		return err
+	}
+
+	if err := ts.storage.Delete(id); err != nil {
+		return err
+	}
+
+	ts.record("delete todo %s: %s", todo.ID, todo.Task)
+	return nil
 }
 
 func (ts *TodoService) GetTodo(id string) (types.Todo, error) {
@@ -149,13 +257,78 @@ func (ts *TodoService) ListTodos() []types.Todo {
 	return ts.storage.List()
 }
 
+// ResolveTodo finds the todo referred to by ref, which may be an ID, an ID
+// prefix, a row number from rows (the last displayed table) or part of the
+// task text. See Resolver for the matching rules.
+func (ts *TodoService) ResolveTodo(ref string, rows []types.Todo) (types.Todo, error) {
+	return NewResolver(ts.ListTodos(), rows).Resolve(ref)
+}
+
+// ListFiltered returns the todos matching filter.
+func (ts *TodoService) ListFiltered(filter Filter) []types.Todo {
+	return filter.Apply(ts.ListTodos(), time.Now())
+}
+
+// File returns the base name of the todo file, prefixed with the archive
+// directory for archives.
+func (ts *TodoService) File() string {
+	if ts.IsArchive() {
+		return filepath.Join(config.ArchiveDir, filepath.Base(ts.storage.File()))
+	}
+	return filepath.Base(ts.storage.File())
+}
+
 func (ts *TodoService) Save() error {
 	if err := ts.storage.Persist(); err != nil {
 		return fmt.Errorf("failed to persist todos: %w", err)
 	}
+	return ts.afterPersist()
+}
+
+// Reload discards unsaved changes and reads the todo file again, picking up
+// changes saved through another TodoService.
+func (ts *TodoService) Reload() error {
+	ts.changes = nil
+	return ts.storage.Reload()
+}
 
-	if err := ts.storage.SaveSummary(ts.config.SummaryFile); err != nil {
+// afterPersist updates the workspace summary and commits the file once it
+// has been written. Archives are left out of the summary.
+func (ts *TodoService) afterPersist() error {
+	if ts.IsArchive() {
+		return ts.autoCommit()
+	}
+	if err := summary.Record(ts.config, ts.storage.File(), ts.storage.List(), ts.storage.Encrypted()); err != nil {
 		return fmt.Errorf("failed to save summary: %w", err)
 	}
-	return nil
+
+	return ts.autoCommit()
+}
+
+// Encrypted reports whether the todo file is encrypted.
+func (ts *TodoService) Encrypted() bool {
+	return ts.storage.Encrypted()
+}
+
+// Encrypt encrypts the todo file with passphrase and saves it. On a file
+// that is already encrypted this rotates the passphrase.
+func (ts *TodoService) Encrypt(passphrase string) error {
+	encrypted := ts.storage.Encrypted()
+	if err := ts.storage.SetPassphrase(passphrase); err != nil {
+		return err
+	}
+
+	if encrypted {
+		ts.record("rotate passphrase of %s", filepath.Base(ts.storage.File()))
+	} else {
+		ts.record("encrypt %s", filepath.Base(ts.storage.File()))
+	}
+	return ts.Save()
+}
+
+// Decrypt saves the todo file as plain JSON again.
+func (ts *TodoService) Decrypt() error {
+	ts.storage.ClearPassphrase()
+	ts.record("decrypt %s", filepath.Base(ts.storage.File()))
+	return ts.Save()
 }
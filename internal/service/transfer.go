@@ -0,0 +1,195 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/Ng1n3/go-todo/internal/errors"
+	"github.com/Ng1n3/go-todo/internal/store"
+	"github.com/Ng1n3/go-todo/internal/types"
+	"github.com/Ng1n3/go-todo/internal/utils"
+)
+
+// ConflictPolicy decides what happens when a moved or copied todo has the
+// same ID as a todo already in the target file.
+type ConflictPolicy string
+
+const (
+	// ConflictRename gives the incoming todo a new ID.
+	ConflictRename ConflictPolicy = "rename"
+	// ConflictSkip leaves the todo where it is.
+	ConflictSkip ConflictPolicy = "skip"
+	// ConflictOverwrite replaces the todo in the target file, which goes to
+	// the trash.
+	ConflictOverwrite ConflictPolicy = "overwrite"
+)
+
+func ParseConflictPolicy(value string) (ConflictPolicy, error) {
+	switch p := ConflictPolicy(value); p {
+	case ConflictRename, ConflictSkip, ConflictOverwrite:
+		return p, nil
+	case "":
+		return ConflictRename, nil
+	default:
+		return "", fmt.Errorf("%w: unknown conflict policy %q, use rename, skip or overwrite", errors.ErrInvalidInput, value)
+	}
+}
+
+// TransferResult describes what happened to one todo in a move or copy.
+type TransferResult struct {
+	// Todo is the todo as stored in the target file.
+	Todo types.Todo
+	// OldID is the todo's ID in the source file; it differs from Todo.ID
+	// when the todo was renamed to avoid a collision.
+	OldID   string
+	Skipped bool
+}
+
+// MoveTodos moves the todos with the given IDs from src to dst, keeping their
+// IDs and timestamps. Both files are saved; if either write fails neither
+// file is changed. Labels must be allowed by dst's allow-list.
+func MoveTodos(src, dst *TodoService, ids []string, policy ConflictPolicy) ([]TransferResult, error) {
+	return transfer(src, dst, ids, policy, true)
+}
+
+// CopyTodos copies the todos with the given IDs from src to dst, keeping
+// their IDs and timestamps, and saves dst.
+func CopyTodos(src, dst *TodoService, ids []string, policy ConflictPolicy) ([]TransferResult, error) {
+	return transfer(src, dst, ids, policy, false)
+}
+
+func transfer(src, dst *TodoService, ids []string, policy ConflictPolicy, move bool) ([]TransferResult, error) {
+	if src.storage.File() == dst.storage.File() {
+		return nil, fmt.Errorf("%w: source and target are the same file", errors.ErrInvalidInput)
+	}
+
+	todos := make([]types.Todo, 0, len(ids))
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		todo, err := src.storage.Get(id)
+		if err != nil {
+			return nil, fmt.Errorf("%s in %s: %w", id, src.File(), err)
+		}
+		todos = append(todos, todo)
+	}
+
+	// Keep the target's current content so it can be put back if the
+	// source can't be written.
+	dstFile := dst.storage.File()
+	original, readErr := os.ReadFile(dstFile)
+	if readErr != nil && !os.IsNotExist(readErr) {
+		return nil, fmt.Errorf("failed to read %s: %w", dst.File(), readErr)
+	}
+	originalMode := dst.config.FileMode
+	if info, err := os.Stat(dstFile); err == nil {
+		originalMode = info.Mode().Perm()
+	}
+
+	verb := "copy"
+	if move {
+		verb = "move"
+	}
+
+	results := make([]TransferResult, 0, len(todos))
+	for _, todo := range todos {
+		result := TransferResult{Todo: todo, OldID: todo.ID}
+
+		if _, err := dst.storage.Get(todo.ID); err == nil {
+			switch policy {
+			case ConflictSkip:
+				result.Skipped = true
+				results = append(results, result)
+				continue
+			case ConflictRename:
+				result.Todo.ID = unusedID(dst.storage)
+			case ConflictOverwrite:
+				// The replaced todo goes to the trash like a deleted one.
+				if err := dst.storage.Delete(todo.ID); err != nil {
+					return nil, rollback(err, src, dst)
+				}
+			}
+		}
+
+		labels, err := dst.allowLabels(result.Todo.Labels)
+		if err != nil {
+			return nil, rollback(fmt.Errorf("todo %s: %w", todo.ID, err), src, dst)
+		}
+		result.Todo.Labels = labels
+
+		dst.storage.Workflow().Adopt(&result.Todo, time.Now())
+		if err := dst.storage.Put(result.Todo); err != nil {
+			return nil, rollback(err, src, dst)
+		}
+		if move {
+			if err := src.storage.Remove(todo.ID); err != nil {
+				return nil, rollback(err, src, dst)
+			}
+			src.record("move todo %s to %s: %s", todo.ID, dst.File(), todo.Task)
+		}
+		dst.record("%s todo %s from %s: %s", verb, result.Todo.ID, src.File(), todo.Task)
+		results = append(results, result)
+	}
+
+	if err := dst.storage.Write(); err != nil {
+		return nil, rollback(fmt.Errorf("failed to write %s: %w", dst.File(), err), src, dst)
+	}
+
+	if move {
+		if err := src.storage.Write(); err != nil {
+			err = fmt.Errorf("failed to write %s: %w", src.File(), err)
+			if readErr != nil {
+				os.Remove(dstFile)
+			} else if restoreErr := store.WriteFileAtomic(dstFile, original, originalMode); restoreErr != nil {
+				err = fmt.Errorf("%w; restoring %s also failed: %v", err, dst.File(), restoreErr)
+			}
+			return nil, rollback(err, src, dst)
+		}
+	}
+
+	// Both files are written, so the transfer has happened even if the
+	// trash can't be updated. That is reported after the files have been
+	// committed, like any other failure from here on.
+	written := []*TodoService{dst}
+	if move {
+		written = append(written, src)
+	}
+	var trashErr error
+	for _, ts := range written {
+		if err := ts.storage.FlushDeleted(); err != nil && trashErr == nil {
+			trashErr = fmt.Errorf("%s was written, but updating the trash failed: %w", ts.File(), err)
+		}
+	}
+	for _, ts := range written {
+		if err := ts.afterPersist(); err != nil {
+			return results, err
+		}
+	}
+	return results, trashErr
+}
+
+// rollback discards the in-memory changes of both services by reloading
+// them from disk, and returns err.
+func rollback(err error, services ...*TodoService) error {
+	for _, ts := range services {
+		ts.changes = nil
+		if reloadErr := ts.storage.Reload(); reloadErr != nil {
+			err = fmt.Errorf("%w; reloading %s also failed: %v", err, ts.File(), reloadErr)
+		}
+	}
+	return err
+}
+
+// unusedID returns a new todo ID that is not taken in storage.
+func unusedID(storage *store.TodoStorage) string {
+	for {
+		id := utils.GenerateID(6)
+		if _, err := storage.Get(id); err != nil {
+			return id
+		}
+	}
+}
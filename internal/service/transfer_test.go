@@ -0,0 +1,88 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Ng1n3/go-todo/internal/store"
+)
+
+func TestOverwriteSendsReplacedTodoToTrash(t *testing.T) {
+	cfg := testConfig(t)
+	w := NewWorkspace(cfg)
+	src := openFile(t, w, "work.json", "new")
+	id := src.ListTodos()[0].ID
+
+	dst := openFile(t, w, "home.json")
+	old := src.ListTodos()[0]
+	old.Task = "old version"
+	if err := dst.storage.Put(old); err != nil {
+		t.Fatal(err)
+	}
+	if err := dst.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := CopyTodos(src, dst, []string{id}, ConflictOverwrite); err != nil {
+		t.Fatal(err)
+	}
+	if todo, _ := dst.GetTodo(id); todo.Task != "task new" {
+		t.Errorf("task in home.json = %q, want the copied todo", todo.Task)
+	}
+
+	items, err := w.Trash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 1 || items[0].Task != "old version" || items[0].File != "home.json" {
+		t.Errorf("trash = %+v, want the replaced todo of home.json", items)
+	}
+}
+
+func TestMoveNamesTodoOnce(t *testing.T) {
+	w := NewWorkspace(testConfig(t))
+	src := openFile(t, w, "work.json", "a", "b")
+	id := src.ListTodos()[0].ID
+	dst := openFile(t, w, "home.json")
+
+	results, err := MoveTodos(src, dst, []string{id, id}, ConflictRename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || dst.storage.Count() != 1 || src.storage.Count() != 1 {
+		t.Errorf("moved %d todos, home.json has %d and work.json %d, want 1 each",
+			len(results), dst.storage.Count(), src.storage.Count())
+	}
+}
+
+func TestMoveKeepsTodoWhenTrashFails(t *testing.T) {
+	cfg := testConfig(t)
+	w := NewWorkspace(cfg)
+	src := openFile(t, w, "work.json", "a")
+	id := src.ListTodos()[0].ID
+	dst := openFile(t, w, "home.json")
+
+	// A file where the trash directory belongs makes every trash update fail.
+	if err := os.WriteFile(filepath.Join(cfg.StorageDir, store.TrashDir), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := MoveTodos(src, dst, []string{id}, ConflictRename)
+	if err == nil {
+		t.Error("MoveTodos() error = nil, want the trash failure reported")
+	}
+	if len(results) != 1 {
+		t.Errorf("MoveTodos() results = %v, want the moved todo", results)
+	}
+
+	for name, want := range map[string]bool{"work.json": false, "home.json": true} {
+		ts, err := w.Open(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := ts.GetTodo(id); (err == nil) != want {
+			t.Errorf("%s has the todo: %v, want %v", name, err == nil, want)
+		}
+	}
+}
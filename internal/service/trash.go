@@ -0,0 +1,97 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/Ng1n3/go-todo/internal/gitsync"
+	"github.com/Ng1n3/go-todo/internal/store"
+	"github.com/Ng1n3/go-todo/internal/types"
+)
+
+// DeleteFile moves the named todo file and its archive to the trash.
+func (w *Workspace) DeleteFile(name string) (store.TrashItem, error) {
+	item, err := store.NewTrash(w.config).AddFile(w.config.GetFullPath(name))
+	if err != nil {
+		return store.TrashItem{}, err
+	}
+	if _, err := store.NewTrash(w.config).Purge(item.DeletedAt); err != nil {
+		return item, fmt.Errorf("failed to purge trash: %w", err)
+	}
+	return item, w.commit("delete "+item.File, trashedFiles(item)...)
+}
+
+// Trash lists the deleted todos and files, most recent first, after purging
+// the ones past the retention period.
+func (w *Workspace) Trash() ([]store.TrashItem, error) {
+	return store.NewTrash(w.config).List()
+}
+
+// RestoreTrash puts the trash item with the given ID back where it was
+// deleted from. For a deleted todo it returns the restored todo, whose ID
+// differs from the original if that was taken in the meantime.
+func (w *Workspace) RestoreTrash(id string) (store.TrashItem, types.Todo, error) {
+	trash := store.NewTrash(w.config)
+	item, err := trash.Get(id)
+	if err != nil {
+		return store.TrashItem{}, types.Todo{}, err
+	}
+
+	if item.Kind == store.TrashFile {
+		if err := trash.RestoreFile(item); err != nil {
+			return item, types.Todo{}, err
+		}
+		return item, types.Todo{}, w.commit("restore "+item.File+" from trash", trashedFiles(item)...)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(w.config.GetFullPath(item.File)), 0755); err != nil {
+		return item, types.Todo{}, fmt.Errorf("failed to create directory for %s: %w", item.File, err)
+	}
+	ts, err := w.Open(item.File)
+	if err != nil {
+		return item, types.Todo{}, err
+	}
+
+	todo, err := trash.RestoreTodo(item, ts.storage)
+	if err != nil {
+		return item, types.Todo{}, err
+	}
+	// The file may have got a label allow-list since the todo was deleted.
+	if todo.Labels, err = ts.allowLabels(todo.Labels); err != nil {
+		return item, types.Todo{}, rollback(err, ts)
+	}
+	if err := ts.storage.Put(todo); err != nil {
+		return item, types.Todo{}, rollback(err, ts)
+	}
+	ts.record("restore todo %s from trash: %s", todo.ID, todo.Task)
+	if err := ts.Save(); err != nil {
+		return item, todo, err
+	}
+	return item, todo, trash.Remove(item.ID)
+}
+
+// EmptyTrash deletes everything in the trash for good and returns the number
+// of items removed.
+func (w *Workspace) EmptyTrash() (int, error) {
+	return store.NewTrash(w.config).Empty()
+}
+
+// trashedFiles returns the files a deleted file item was moved from.
+func trashedFiles(item store.TrashItem) []string {
+	if item.Archive {
+		return []string{item.File, item.ArchiveFile()}
+	}
+	return []string{item.File}
+}
+
+// commit commits paths when git autocommit is enabled.
+func (w *Workspace) commit(message string, paths ...string) error {
+	if !w.config.GitAutoCommit {
+		return nil
+	}
+	if _, err := gitsync.Open(w.config.StorageDir).Commit(message, paths...); err != nil {
+		return fmt.Errorf("failed to commit changes: %w", err)
+	}
+	return nil
+}
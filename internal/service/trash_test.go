@@ -0,0 +1,84 @@
+package service
+
+import (
+	stderrors "errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Ng1n3/go-todo/internal/errors"
+	"github.com/Ng1n3/go-todo/internal/store"
+)
+
+func archivedTasks(t *testing.T, w *Workspace, name string) []string {
+	t.Helper()
+	ts, err := w.Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	archive, err := ts.OpenArchive()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var tasks []string
+	for _, todo := range archive.ListTodos() {
+		tasks = append(tasks, todo.Task)
+	}
+	return tasks
+}
+
+func TestDeleteFileTakesArchiveAlong(t *testing.T) {
+	cfg := testConfig(t)
+	w := NewWorkspace(cfg)
+	ts := openFile(t, w, "work.json", "old")
+	if _, err := ts.Archive([]string{ts.ListTodos()[0].ID}); err != nil {
+		t.Fatal(err)
+	}
+
+	deleted, err := w.DeleteFile("work.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !deleted.Archive || deleted.ArchiveFile() != filepath.Join("archive", "work.json") {
+		t.Errorf("DeleteFile() = %+v, want the archive deleted along", deleted)
+	}
+	if _, err := os.Stat(cfg.GetArchivePath("work.json")); !os.IsNotExist(err) {
+		t.Errorf("archive still exists after deleting its file: %v", err)
+	}
+
+	// A new file of the same name doesn't inherit the old archive.
+	openFile(t, w, "work.json", "new")
+	if tasks := archivedTasks(t, w, "work.json"); len(tasks) != 0 {
+		t.Errorf("archive of the new work.json = %v, want it empty", tasks)
+	}
+	if _, _, err := w.RestoreTrash(deleted.ID); !stderrors.Is(err, errors.ErrFileExists) {
+		t.Errorf("RestoreTrash() over a new file error = %v, want %v", err, errors.ErrFileExists)
+	}
+
+	// The new file has no archive, so none goes to the trash with it.
+	second, err := w.DeleteFile("work.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.Archive {
+		t.Errorf("DeleteFile() = %+v, want no archive", second)
+	}
+
+	if _, _, err := w.RestoreTrash(deleted.ID); err != nil {
+		t.Fatal(err)
+	}
+	if tasks := archivedTasks(t, w, "work.json"); len(tasks) != 1 || tasks[0] != "task old" {
+		t.Errorf("archive after restoring = %v, want [task old]", tasks)
+	}
+
+	if _, err := w.EmptyTrash(); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := os.ReadDir(filepath.Join(cfg.StorageDir, store.TrashDir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		t.Errorf("%s left in the trash after emptying it", entry.Name())
+	}
+}
@@ -0,0 +1,167 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Ng1n3/go-todo/internal/config"
+	"github.com/Ng1n3/go-todo/internal/errors"
+	"github.com/Ng1n3/go-todo/internal/store"
+	"github.com/Ng1n3/go-todo/internal/types"
+)
+
+// Workspace gives access to every todo file in the configured storage
+// directory. Commands that look across all lists (reminders, search,
+// reports) go through it instead of walking the directory themselves.
+type Workspace struct {
+	config *config.Config
+}
+
+// FileTodos holds the todos loaded from a single todo file.
+type FileTodos struct {
+	File  string
+	Todos []types.Todo
+}
+
+func NewWorkspace(cfg *config.Config) *Workspace {
+	if cfg == nil {
+		cfg = config.Default()
+	}
+	return &Workspace{config: cfg}
+}
+
+// Config returns the configuration the workspace was created with.
+func (w *Workspace) Config() *config.Config {
+	return w.config
+}
+
+// Files returns the sorted names of the todo files in the storage directory.
+// Hidden entries and sub-directories are skipped.
+func (w *Workspace) Files() ([]string, error) {
+	return store.ListFiles(w.config.StorageDir)
+}
+
+// ArchiveFiles returns the sorted names of the archive files, relative to
+// the storage directory (e.g. "archive/work.json"), so they can be passed to
+// Open.
+func (w *Workspace) ArchiveFiles() ([]string, error) {
+	files, err := store.ListFiles(filepath.Join(w.config.StorageDir, config.ArchiveDir))
+	if err != nil {
+		return nil, err
+	}
+	for i, name := range files {
+		files[i] = filepath.Join(config.ArchiveDir, name)
+	}
+	return files, nil
+}
+
+// Open returns a TodoService for the named file in the storage directory.
+func (w *Workspace) Open(name string) (*TodoService, error) {
+	return NewTodoService(w.config.GetFullPath(name), w.config)
+}
+
+// LoadAll reads every todo file in the storage directory.
+func (w *Workspace) LoadAll() ([]FileTodos, error) {
+	return w.Load(nil)
+}
+
+// Load reads the named todo files, or every todo file when names is empty.
+func (w *Workspace) Load(names []string) ([]FileTodos, error) {
+	if len(names) == 0 {
+		var err error
+		if names, err = w.Files(); err != nil {
+			return nil, err
+		}
+	}
+
+	all := make([]FileTodos, 0, len(names))
+	for _, name := range names {
+		ts, err := w.Open(name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load %s: %w", name, err)
+		}
+		all = append(all, FileTodos{File: name, Todos: ts.ListTodos()})
+	}
+	return all, nil
+}
+
+// LoadWithArchives reads the named todo files, or every todo file when names
+// is empty, each together with the todos of its archive.
+func (w *Workspace) LoadWithArchives(names []string) ([]FileTodos, error) {
+	if len(names) == 0 {
+		var err error
+		if names, err = w.Files(); err != nil {
+			return nil, err
+		}
+	}
+
+	all := make([]FileTodos, 0, len(names))
+	for _, name := range names {
+		ts, err := w.Open(name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load %s: %w", name, err)
+		}
+		archive, err := ts.OpenArchive()
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, FileTodos{File: name, Todos: append(ts.ListTodos(), archive.ListTodos()...)})
+	}
+	return all, nil
+}
+
+// findTodo opens the file holding the todo with the given ID that was in
+// file when a timer or focus session started on it: file itself, or else
+// the one todo file or archive the todo was moved to. task, when known, has
+// to match as well, so that an unrelated todo with the same ID elsewhere is
+// not taken for it.
+func (w *Workspace) findTodo(file, id, task string) (*TodoService, error) {
+	if _, err := os.Stat(w.config.GetFullPath(file)); err == nil {
+		ts, err := w.Open(file)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := ts.GetTodo(id); err == nil {
+			return ts, nil
+		}
+	}
+
+	files, err := w.Files()
+	if err != nil {
+		return nil, err
+	}
+	archives, err := w.ArchiveFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	var found []*TodoService
+	for _, name := range append(files, archives...) {
+		if name == file {
+			continue
+		}
+		ts, err := w.Open(name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load %s: %w", name, err)
+		}
+		todo, err := ts.GetTodo(id)
+		if err != nil || (task != "" && !ts.Encrypted() && todo.Task != task) {
+			continue
+		}
+		found = append(found, ts)
+	}
+
+	switch len(found) {
+	case 0:
+		return nil, fmt.Errorf("%w: %s is no longer in %s or any other todo file", errors.ErrTodoNotFound, id, file)
+	case 1:
+		return found[0], nil
+	}
+	names := make([]string, len(found))
+	for i, ts := range found {
+		names[i] = ts.File()
+	}
+	return nil, fmt.Errorf("%w: %s moved from %s and is now in %s", errors.ErrAmbiguousTodo, id, file, strings.Join(names, ", "))
+}
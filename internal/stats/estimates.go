@@ -0,0 +1,157 @@
+package stats
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/Ng1n3/go-todo/internal/errors"
+	"github.com/Ng1n3/go-todo/internal/service"
+	"github.com/Ng1n3/go-todo/internal/types"
+	"github.com/Ng1n3/go-todo/internal/utils"
+)
+
+// Actual is what estimates are compared against.
+type Actual string
+
+const (
+	// ActualTracked is the time logged on the todo.
+	ActualTracked Actual = "tracked"
+	// ActualCompletion is the time from creating the todo to completing it.
+	ActualCompletion Actual = "completion"
+)
+
+// noLabel is the label row of todos without labels.
+const noLabel = "(no label)"
+
+func ParseActual(value string) (Actual, error) {
+	switch a := Actual(value); a {
+	case ActualTracked, ActualCompletion:
+		return a, nil
+	case "":
+		return ActualTracked, nil
+	default:
+		return "", fmt.Errorf("%w: unknown actual %q, use tracked or completion", errors.ErrInvalidInput, value)
+	}
+}
+
+// EstimateOptions selects the period of the comparison, by completion day,
+// and what the estimates are compared against.
+type EstimateOptions struct {
+	From   time.Time
+	To     time.Time
+	Actual Actual
+}
+
+// EstimateReport compares the estimates of the todos completed in a period
+// with how long they actually took, per label.
+type EstimateReport struct {
+	From   time.Time     `json:"from"`
+	To     time.Time     `json:"to"`
+	Actual Actual        `json:"actual"`
+	Labels []EstimateRow `json:"labels"`
+	Total  EstimateRow   `json:"total"`
+}
+
+// EstimateRow compares estimates and actual times of the todos with a
+// label. Todos estimated in time and in points are counted separately.
+type EstimateRow struct {
+	Label string `json:"label"`
+
+	// Timed counts the todos estimated in time. Ratio is ActualHours over
+	// EstimatedHours: above 1 means the work took longer than estimated.
+	Timed          int     `json:"timed"`
+	EstimatedHours float64 `json:"estimated_hours"`
+	ActualHours    float64 `json:"actual_hours"`
+	Ratio          float64 `json:"ratio"`
+
+	// Pointed counts the todos estimated in story points, and
+	// HoursPerPoint is their actual time per point.
+	Pointed       int     `json:"pointed"`
+	Points        float64 `json:"points"`
+	PointHours    float64 `json:"point_hours"`
+	HoursPerPoint float64 `json:"hours_per_point"`
+}
+
+func (r *EstimateRow) add(estimate types.Estimate, actual time.Duration) {
+	if estimate.IsPoints() {
+		r.Pointed++
+		r.Points += estimate.Points
+		r.PointHours += actual.Hours()
+		return
+	}
+	r.Timed++
+	r.EstimatedHours += estimate.Duration().Hours()
+	r.ActualHours += actual.Hours()
+}
+
+func (r *EstimateRow) finish() {
+	if r.EstimatedHours > 0 {
+		r.Ratio = r.ActualHours / r.EstimatedHours
+	}
+	if r.Points > 0 {
+		r.HoursPerPoint = r.PointHours / r.Points
+	}
+}
+
+// CompareEstimates builds the estimate report for the todos of files. Only
+// completed todos with an estimate and a non-zero actual time count; a todo
+// with several labels counts towards each of them.
+func CompareEstimates(files []service.FileTodos, opts EstimateOptions) *EstimateReport {
+	loc := time.Local
+	from := startOfDay(opts.From, loc)
+	end := startOfDay(opts.To, loc).AddDate(0, 0, 1)
+
+	r := &EstimateReport{From: from, To: end.AddDate(0, 0, -1), Actual: opts.Actual, Labels: []EstimateRow{}}
+	r.Total.Label = "Total"
+
+	// Labels are grouped in their normalized form and shown as first seen.
+	labels := make(map[string]*EstimateRow)
+	row := func(label string) *EstimateRow {
+		key := utils.NormalizeLabel(label)
+		if labels[key] == nil {
+			labels[key] = &EstimateRow{Label: label}
+		}
+		return labels[key]
+	}
+
+	for _, file := range files {
+		for _, todo := range file.Todos {
+			completedAt := completionTime(todo)
+			if !todo.Completed || todo.Estimate == nil || completedAt.Before(from) || !completedAt.Before(end) {
+				continue
+			}
+
+			actual := todo.Tracked()
+			if opts.Actual == ActualCompletion {
+				actual = completedAt.Sub(todo.CreatedAt)
+			}
+			if actual <= 0 {
+				continue
+			}
+
+			r.Total.add(*todo.Estimate, actual)
+			if len(todo.Labels) == 0 {
+				row(noLabel).add(*todo.Estimate, actual)
+			}
+			for _, label := range todo.Labels {
+				row(label).add(*todo.Estimate, actual)
+			}
+		}
+	}
+
+	for _, label := range labels {
+		label.finish()
+		r.Labels = append(r.Labels, *label)
+	}
+	r.Total.finish()
+
+	sort.Slice(r.Labels, func(i, j int) bool {
+		a, b := r.Labels[i], r.Labels[j]
+		if a.Timed+a.Pointed != b.Timed+b.Pointed {
+			return a.Timed+a.Pointed > b.Timed+b.Pointed
+		}
+		return a.Label < b.Label
+	})
+	return r
+}
@@ -0,0 +1,236 @@
+// Package stats computes productivity statistics from the timestamps of
+// todos: how many were created and completed per week, how long they took,
+// how often they ran past their due date, a breakdown by priority and label,
+// and the oldest open todos.
+package stats
+
+import (
+	"sort"
+	"time"
+
+	"github.com/Ng1n3/go-todo/internal/service"
+	"github.com/Ng1n3/go-todo/internal/types"
+	"github.com/Ng1n3/go-todo/internal/utils"
+)
+
+// oldestOpenLimit is how many open todos Report.OldestOpen lists.
+const oldestOpenLimit = 5
+
+// Options selects the period the statistics cover. From and To are
+// inclusive days; Now is used to decide what is overdue.
+type Options struct {
+	From time.Time
+	To   time.Time
+	Now  time.Time
+}
+
+type Report struct {
+	From  time.Time `json:"from"`
+	To    time.Time `json:"to"`
+	Files []string  `json:"files"`
+
+	// Created and Completed count the todos created and completed in the
+	// period; Open is the number of open todos now.
+	Created   int `json:"created"`
+	Completed int `json:"completed"`
+	Open      int `json:"open"`
+
+	// AvgCompletionHours is the mean time from creation to completion of
+	// the todos completed in the period.
+	AvgCompletionHours float64 `json:"avg_completion_hours"`
+
+	// Due counts the todos whose deadline fell in the period and has passed;
+	// Late counts those among them that were completed after the deadline or
+	// are still open. OverdueRate is Late/Due.
+	Due         int     `json:"due"`
+	Late        int     `json:"late"`
+	OverdueRate float64 `json:"overdue_rate"`
+
+	Weeks      []Week      `json:"weeks"`
+	ByPriority []Breakdown `json:"by_priority"`
+	ByLabel    []Breakdown `json:"by_label"`
+	OldestOpen []OpenTodo  `json:"oldest_open"`
+}
+
+// Week counts the todos created and completed in the week starting on
+// Monday Start.
+type Week struct {
+	Start     time.Time `json:"start"`
+	Created   int       `json:"created"`
+	Completed int       `json:"completed"`
+}
+
+// Breakdown counts the todos created in the period with a given priority or
+// label, and how many of them are completed, open and overdue now.
+type Breakdown struct {
+	Name      string `json:"name"`
+	Created   int    `json:"created"`
+	Completed int    `json:"completed"`
+	Open      int    `json:"open"`
+	Overdue   int    `json:"overdue"`
+}
+
+type OpenTodo struct {
+	File      string    `json:"file"`
+	ID        string    `json:"id"`
+	Task      string    `json:"task"`
+	CreatedAt time.Time `json:"created_at"`
+	AgeDays   int       `json:"age_days"`
+}
+
+// Compute builds the report for the todos of files.
+func Compute(files []service.FileTodos, opts Options) *Report {
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+	// Due dates end at local midnight, so the period is in local time too.
+	loc := time.Local
+	from := startOfDay(opts.From, loc)
+	end := startOfDay(opts.To, loc).AddDate(0, 0, 1)
+	inPeriod := func(t time.Time) bool {
+		return !t.IsZero() && !t.Before(from) && t.Before(end)
+	}
+
+	r := &Report{From: from, To: end.AddDate(0, 0, -1), Files: []string{}}
+
+	weeks := make(map[string]*Week)
+	week := func(t time.Time) *Week {
+		start := weekStart(t.In(loc))
+		key := start.Format("2006-01-02")
+		if weeks[key] == nil {
+			weeks[key] = &Week{Start: start}
+		}
+		return weeks[key]
+	}
+	for w := weekStart(from); w.Before(end); w = w.AddDate(0, 0, 7) {
+		week(w)
+	}
+	priorities := make(map[string]*Breakdown)
+	labels := make(map[string]*Breakdown)
+
+	var completionTotal time.Duration
+	var open []OpenTodo
+
+	for _, file := range files {
+		r.Files = append(r.Files, file.File)
+
+		for _, todo := range file.Todos {
+			if !todo.Completed {
+				r.Open++
+				open = append(open, OpenTodo{
+					File:      file.File,
+					ID:        todo.ID,
+					Task:      todo.Task,
+					CreatedAt: todo.CreatedAt,
+					AgeDays:   int(opts.Now.Sub(todo.CreatedAt).Hours() / 24),
+				})
+			}
+
+			if inPeriod(todo.CreatedAt) {
+				r.Created++
+				week(todo.CreatedAt).Created++
+
+				add(priorities, string(todo.Priority.Normalize()), todo, opts.Now)
+				for _, label := range todo.Labels {
+					add(labels, label, todo, opts.Now)
+				}
+			}
+
+			completedAt := completionTime(todo)
+			if todo.Completed && inPeriod(completedAt) {
+				r.Completed++
+				week(completedAt).Completed++
+				completionTotal += completedAt.Sub(todo.CreatedAt)
+			}
+
+			if deadline := todo.Deadline(); inPeriod(deadline.Add(-time.Nanosecond)) && !opts.Now.Before(deadline) {
+				r.Due++
+				if !todo.Completed || completedAt.After(deadline) {
+					r.Late++
+				}
+			}
+		}
+	}
+
+	if r.Completed > 0 {
+		r.AvgCompletionHours = (completionTotal / time.Duration(r.Completed)).Hours()
+	}
+	if r.Due > 0 {
+		r.OverdueRate = float64(r.Late) / float64(r.Due)
+	}
+
+	r.Weeks = make([]Week, 0, len(weeks))
+	for _, w := range weeks {
+		r.Weeks = append(r.Weeks, *w)
+	}
+	sort.Slice(r.Weeks, func(i, j int) bool { return r.Weeks[i].Start.Before(r.Weeks[j].Start) })
+
+	r.ByPriority = sortedBreakdowns(priorities)
+	r.ByLabel = sortedBreakdowns(labels)
+
+	sort.Slice(open, func(i, j int) bool { return open[i].CreatedAt.Before(open[j].CreatedAt) })
+	if len(open) > oldestOpenLimit {
+		open = open[:oldestOpenLimit]
+	}
+	r.OldestOpen = append([]OpenTodo{}, open...)
+	return r
+}
+
+// add counts todo in the breakdown called name. Names are compared in their
+// normalized label form, so "Work" and "work" are counted together under the
+// spelling seen first.
+func add(breakdowns map[string]*Breakdown, name string, todo types.Todo, now time.Time) {
+	key := utils.NormalizeLabel(name)
+	b, ok := breakdowns[key]
+	if !ok {
+		b = &Breakdown{Name: name}
+		breakdowns[key] = b
+	}
+
+	b.Created++
+	switch {
+	case todo.Completed:
+		b.Completed++
+	case todo.IsOverdue(now):
+		b.Open++
+		b.Overdue++
+	default:
+		b.Open++
+	}
+}
+
+// sortedBreakdowns orders breakdowns by the number of todos created, then by
+// name.
+func sortedBreakdowns(breakdowns map[string]*Breakdown) []Breakdown {
+	out := make([]Breakdown, 0, len(breakdowns))
+	for _, b := range breakdowns {
+		out = append(out, *b)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Created != out[j].Created {
+			return out[i].Created > out[j].Created
+		}
+		return out[i].Name < out[j].Name
+	})
+	return out
+}
+
+// completionTime returns when a completed todo was completed. Todos
+// completed before CompletedAt was recorded fall back to their last update.
+func completionTime(todo types.Todo) time.Time {
+	if !todo.CompletedAt.IsZero() {
+		return todo.CompletedAt
+	}
+	return todo.UpdatedAt
+}
+
+// startOfDay returns midnight in loc of the calendar day of t.
+func startOfDay(t time.Time, loc *time.Location) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+}
+
+// weekStart returns the Monday of the week t falls in.
+func weekStart(t time.Time) time.Time {
+	day := startOfDay(t, t.Location())
+	return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+}
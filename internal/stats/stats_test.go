@@ -0,0 +1,80 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Ng1n3/go-todo/internal/service"
+	"github.com/Ng1n3/go-todo/internal/types"
+)
+
+func TestCompute(t *testing.T) {
+	saved := time.Local
+	time.Local = time.FixedZone("UTC-5", -5*60*60)
+	t.Cleanup(func() { time.Local = saved })
+
+	at := func(day, hour, min int) time.Time { return time.Date(2026, 3, day, hour, min, 0, 0, time.Local) }
+	due := func(day int) time.Time { return time.Date(2026, 3, day, 0, 0, 0, 0, time.UTC) }
+	todo := func(id string, created time.Time, dueDay int) types.Todo {
+		todo := types.Todo{ID: id, Task: "task " + id, Priority: types.Medium, CreatedAt: created, UpdatedAt: created}
+		if dueDay > 0 {
+			todo.DueDate = due(dueDay)
+		}
+		return todo
+	}
+	complete := func(todo types.Todo, at time.Time) types.Todo {
+		todo.Completed, todo.CompletedAt, todo.Status = true, at, types.StatusDone
+		return todo
+	}
+
+	onTime := complete(todo("ontime", at(2, 9, 0), 4), at(3, 9, 0))
+	onTime.Labels = []string{"Work"}
+	lateOpen := todo("lateopen", at(3, 10, 0), 5)
+	lateOpen.Labels = []string{"work"}
+	lateDone := complete(todo("latedone", at(4, 10, 0), 5), at(7, 10, 0))
+	// Sunday evening locally, but already Monday in UTC.
+	sunday := todo("sunday", at(8, 23, 30), 0)
+	// Created before the period, due on its last day and still open.
+	older := todo("older", at(1, 23, 0), 8)
+	// Due after the period.
+	later := todo("later", at(1, 8, 0), 9)
+
+	files := []service.FileTodos{{File: "work.json", Todos: []types.Todo{onTime, lateOpen, lateDone, sunday, older, later}}}
+	r := Compute(files, Options{From: due(2), To: due(8), Now: at(10, 12, 0)})
+
+	if r.Created != 4 || r.Completed != 2 || r.Open != 4 {
+		t.Errorf("created %d, completed %d, open %d, want 4, 2 and 4", r.Created, r.Completed, r.Open)
+	}
+	if r.AvgCompletionHours != 48 {
+		t.Errorf("AvgCompletionHours = %v, want 48", r.AvgCompletionHours)
+	}
+	if r.Due != 4 || r.Late != 3 || r.OverdueRate != 0.75 {
+		t.Errorf("due %d, late %d, overdue rate %v, want 4, 3 and 0.75", r.Due, r.Late, r.OverdueRate)
+	}
+	if !r.From.Equal(at(2, 0, 0)) || !r.To.Equal(at(8, 0, 0)) {
+		t.Errorf("period %v to %v, want local days", r.From, r.To)
+	}
+
+	if len(r.Weeks) != 1 || !r.Weeks[0].Start.Equal(at(2, 0, 0)) || r.Weeks[0].Created != 4 || r.Weeks[0].Completed != 2 {
+		t.Errorf("weeks = %+v, want the local week of March 2 with 4 created and 2 completed", r.Weeks)
+	}
+
+	want := Breakdown{Name: "Work", Created: 2, Completed: 1, Open: 1, Overdue: 1}
+	if len(r.ByLabel) != 1 || r.ByLabel[0] != want {
+		t.Errorf("ByLabel = %+v, want [%+v]", r.ByLabel, want)
+	}
+	if len(r.OldestOpen) != 4 || r.OldestOpen[0].ID != "later" {
+		t.Errorf("OldestOpen = %+v, want the four open todos, oldest first", r.OldestOpen)
+	}
+}
+
+func TestComputeWithoutDueTodos(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	r := Compute(nil, Options{From: now.AddDate(0, 0, -7), To: now, Now: now})
+	if r.Due != 0 || r.OverdueRate != 0 || r.AvgCompletionHours != 0 {
+		t.Errorf("empty report = %+v, want zero rates", r)
+	}
+	if len(r.Weeks) == 0 {
+		t.Error("Weeks is empty, want every week of the period listed")
+	}
+}
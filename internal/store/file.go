@@ -0,0 +1,176 @@
+package store
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Ng1n3/go-todo/internal/config"
+	"github.com/Ng1n3/go-todo/internal/encryption"
+	"github.com/Ng1n3/go-todo/internal/errors"
+	"github.com/Ng1n3/go-todo/internal/types"
+)
+
+// maxPassphraseAttempts is how often Decode asks for the passphrase of an
+// encrypted file before giving up.
+const maxPassphraseAttempts = 3
+
+// FileMeta holds metadata about a todo file.
+type FileMeta struct {
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	// Workflow is the file's status workflow; nil means the default one.
+	Workflow *types.Workflow `json:"workflow,omitempty"`
+	// Labels is the file's label allow-list; empty allows any label.
+	Labels []string `json:"labels,omitempty"`
+}
+
+// envelope is the on-disk layout of a todo file.
+type envelope struct {
+	SchemaVersion int                   `json:"schema_version"`
+	Meta          FileMeta              `json:"meta"`
+	Todos         map[string]types.Todo `json:"todos"`
+}
+
+// Snapshot is the decoded content of a todo file. Tools that need to work on
+// file content directly, such as merging two versions of a file, use it
+// instead of a TodoStorage.
+type Snapshot struct {
+	Meta  FileMeta
+	Todos map[string]types.Todo
+	// key is set when the content was decrypted, so that Encode encrypts it
+	// again with the same passphrase.
+	key *encryption.Key
+}
+
+// Decode decrypts raw file content if necessary, asking for the passphrase
+// through cfg.Passphrase, and upgrades it to the current schema in memory.
+// It also returns the schema version the content had.
+func Decode(file string, raw []byte, cfg *config.Config) (*Snapshot, int, error) {
+	snap := &Snapshot{Todos: make(map[string]types.Todo)}
+
+	data := raw
+	if encryption.IsEncrypted(raw) {
+		plain, key, err := decrypt(file, raw, cfg)
+		if err != nil {
+			return nil, 0, err
+		}
+		data, snap.key = plain, key
+	}
+
+	data, from, err := migrate(data)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var env envelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		return nil, 0, fmt.Errorf("failed to unmarshal todos : %w", err)
+	}
+
+	snap.Meta = env.Meta
+	if env.Todos != nil {
+		snap.Todos = env.Todos
+	}
+	return snap, from, nil
+}
+
+// Encode serializes the snapshot in the current schema, encrypting it if it
+// was decrypted from an encrypted file.
+func (s *Snapshot) Encode() ([]byte, error) {
+	data, err := json.MarshalIndent(envelope{
+		SchemaVersion: SchemaVersion,
+		Meta:          s.Meta,
+		Todos:         s.Todos,
+	}, "", " ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal todos: %w", err)
+	}
+
+	if s.key != nil {
+		if data, err = encryption.Seal(s.key, data); err != nil {
+			return nil, fmt.Errorf("failed to encrypt todos: %w", err)
+		}
+	}
+	return data, nil
+}
+
+// Encrypted reports whether the snapshot came from an encrypted file.
+func (s *Snapshot) Encrypted() bool {
+	return s.key != nil
+}
+
+// mode returns the permissions for the todo file. Encrypted files are never
+// readable by group or others.
+func (s *Snapshot) mode(cfg *config.Config) os.FileMode {
+	if s.key != nil {
+		return cfg.FileMode &^ 0077
+	}
+	return cfg.FileMode
+}
+
+// WriteSnapshot encodes snap and atomically replaces file with it.
+func WriteSnapshot(file string, snap *Snapshot, cfg *config.Config) error {
+	data, err := snap.Encode()
+	if err != nil {
+		return err
+	}
+	return WriteFileAtomic(file, data, snap.mode(cfg))
+}
+
+func decrypt(file string, raw []byte, cfg *config.Config) ([]byte, *encryption.Key, error) {
+	var lastErr error
+	for attempt := 0; attempt < maxPassphraseAttempts; attempt++ {
+		passphrase, err := passphrase(file, attempt, cfg)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		data, key, err := encryption.Open(raw, passphrase)
+		if err == nil {
+			return data, key, nil
+		}
+		if !stderrors.Is(err, errors.ErrWrongPassphrase) {
+			return nil, nil, err
+		}
+		lastErr = err
+	}
+	return nil, nil, fmt.Errorf("failed to decrypt %s: %w", file, lastErr)
+}
+
+func passphrase(file string, attempt int, cfg *config.Config) (string, error) {
+	if cfg.Passphrase != nil {
+		return cfg.Passphrase(file, attempt)
+	}
+	if passphrase := os.Getenv(config.PassphraseEnv); passphrase != "" && attempt == 0 {
+		return passphrase, nil
+	}
+	return "", fmt.Errorf("%s is encrypted: %w (set %s)", file, errors.ErrPassphraseRequired, config.PassphraseEnv)
+}
+
+// ListFiles returns the sorted names of the todo files in dir. Hidden
+// entries, sub-directories and non-JSON files are skipped.
+func ListFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read storage directory: %w", err)
+	}
+
+	var files []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || strings.HasPrefix(name, ".") || !strings.HasSuffix(name, ".json") {
+			continue
+		}
+		files = append(files, name)
+	}
+	sort.Strings(files)
+	return files, nil
+}
@@ -0,0 +1,141 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Ng1n3/go-todo/internal/errors"
+)
+
+// SchemaVersion is the version of the on-disk format written by this build.
+const SchemaVersion = 3
+
+// Document is the generic JSON form of a todo file that migrations operate
+// on. Working on raw JSON rather than types.Todo keeps old migrations valid
+// after the Go types change.
+type Document map[string]any
+
+// Migration upgrades a document from schema version From to From+1.
+type Migration struct {
+	From        int
+	Description string
+	Apply       func(doc Document) (Document, error)
+}
+
+// migrations are applied in order; migrations[i] upgrades version i.
+var migrations = []Migration{
+	{
+		From:        0,
+		Description: "wrap the bare map of todos in a versioned envelope",
+		Apply: func(doc Document) (Document, error) {
+			now := time.Now().UTC().Format(time.RFC3339Nano)
+			return Document{
+				"schema_version": 1,
+				"meta": map[string]any{
+					"created_at": now,
+					"updated_at": now,
+				},
+				"todos": map[string]any(doc),
+			}, nil
+		},
+	},
+	{
+		From:        1,
+		Description: "record completed_at for completed todos, using updated_at",
+		Apply: func(doc Document) (Document, error) {
+			todos, _ := doc["todos"].(map[string]any)
+			for id, raw := range todos {
+				todo, ok := raw.(map[string]any)
+				if !ok {
+					return nil, fmt.Errorf("todo %s is not an object", id)
+				}
+				if completed, _ := todo["completed"].(bool); completed {
+					if _, ok := todo["completed_at"]; !ok {
+						todo["completed_at"] = todo["updated_at"]
+					}
+				}
+			}
+			doc["schema_version"] = 2
+			return doc, nil
+		},
+	},
+	{
+		From:        2,
+		Description: "derive a workflow status from the completed flag",
+		Apply: func(doc Document) (Document, error) {
+			todos, _ := doc["todos"].(map[string]any)
+			for id, raw := range todos {
+				todo, ok := raw.(map[string]any)
+				if !ok {
+					return nil, fmt.Errorf("todo %s is not an object", id)
+				}
+				if _, ok := todo["status"]; ok {
+					continue
+				}
+				if completed, _ := todo["completed"].(bool); completed {
+					todo["status"] = "done"
+				} else {
+					todo["status"] = "todo"
+				}
+			}
+			doc["schema_version"] = 3
+			return doc, nil
+		},
+	},
+}
+
+// schemaVersion returns the schema version of a decoded todo file. Files
+// without a version marker predate the envelope and are version 0.
+func schemaVersion(doc Document) (int, error) {
+	raw, ok := doc["schema_version"]
+	if !ok {
+		return 0, nil
+	}
+
+	version, ok := raw.(float64)
+	if !ok || version < 0 || version != float64(int(version)) {
+		return 0, fmt.Errorf("invalid schema_version %v", raw)
+	}
+	return int(version), nil
+}
+
+// migrate upgrades data to SchemaVersion. It returns the upgraded JSON and
+// the version the data had before, which equals SchemaVersion when nothing
+// needed to change.
+func migrate(data []byte) ([]byte, int, error) {
+	var doc Document
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, 0, fmt.Errorf("failed to unmarshal todos : %w", err)
+	}
+
+	from, err := schemaVersion(doc)
+	if err != nil {
+		return nil, 0, err
+	}
+	if from > SchemaVersion {
+		return nil, from, fmt.Errorf("%w: file has schema version %d, this build supports up to %d; please upgrade go-todo",
+			errors.ErrUnsupportedSchema, from, SchemaVersion)
+	}
+	if from == SchemaVersion {
+		return data, from, nil
+	}
+
+	for _, m := range migrations[from:] {
+		if doc, err = m.Apply(doc); err != nil {
+			return nil, from, fmt.Errorf("migration from schema version %d (%s) failed: %w", m.From, m.Description, err)
+		}
+	}
+
+	migrated, err := json.MarshalIndent(doc, "", " ")
+	if err != nil {
+		return nil, from, fmt.Errorf("failed to marshal migrated todos: %w", err)
+	}
+	return migrated, from, nil
+}
+
+// backupPath returns where the original of a file is kept before it is
+// migrated from schema version from, e.g. work.json.v0.bak.
+func backupPath(file string, from int) string {
+	return fmt.Sprintf("%s.v%d.bak", file, from)
+}
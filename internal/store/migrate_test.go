@@ -0,0 +1,204 @@
+package store
+
+import (
+	"bytes"
+	"encoding/json"
+	stderrors "errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Ng1n3/go-todo/internal/config"
+	"github.com/Ng1n3/go-todo/internal/encryption"
+	"github.com/Ng1n3/go-todo/internal/errors"
+	"github.com/Ng1n3/go-todo/internal/types"
+)
+
+const (
+	todoV0 = `{
+ "a1": {"id": "a1", "task": "open task", "labels": [], "completed": false, "priority": "LOW",
+        "due_date": "2026-03-05T00:00:00Z", "created_at": "2026-03-01T09:00:00Z", "updated_at": "2026-03-01T09:00:00Z"},
+ "b2": {"id": "b2", "task": "done task", "labels": ["x"], "completed": true, "priority": "HIGH",
+        "due_date": "2026-03-05T00:00:00Z", "created_at": "2026-03-01T09:00:00Z", "updated_at": "2026-03-02T10:00:00Z"}
+}`
+	todoV1 = `{"schema_version": 1, "meta": {"created_at": "2026-03-01T09:00:00Z", "updated_at": "2026-03-02T10:00:00Z"}, "todos": ` + todoV0 + `}`
+	todoV2 = `{"schema_version": 2, "meta": {"created_at": "2026-03-01T09:00:00Z", "updated_at": "2026-03-02T10:00:00Z"}, "todos": {
+ "b2": {"id": "b2", "task": "done task", "labels": [], "completed": true, "priority": "HIGH",
+        "created_at": "2026-03-01T09:00:00Z", "updated_at": "2026-03-02T10:00:00Z", "completed_at": "2026-03-01T12:00:00Z"}
+}}`
+)
+
+func TestMigrate(t *testing.T) {
+	completedAt := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		data     string
+		from     int
+		statuses map[string]types.Status
+		// completedAt is the expected completion time of b2.
+		completedAt time.Time
+	}{
+		{name: "bare map", data: todoV0, from: 0, statuses: map[string]types.Status{"a1": "todo", "b2": "done"}, completedAt: completedAt},
+		{name: "envelope", data: todoV1, from: 1, statuses: map[string]types.Status{"a1": "todo", "b2": "done"}, completedAt: completedAt},
+		{
+			// An existing completed_at is kept.
+			name: "with completion times", data: todoV2, from: 2,
+			statuses:    map[string]types.Status{"b2": "done"},
+			completedAt: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			migrated, from, err := migrate([]byte(tt.data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if from != tt.from {
+				t.Errorf("from = %d, want %d", from, tt.from)
+			}
+
+			var env envelope
+			if err := json.Unmarshal(migrated, &env); err != nil {
+				t.Fatal(err)
+			}
+			if env.SchemaVersion != SchemaVersion {
+				t.Errorf("schema_version = %d, want %d", env.SchemaVersion, SchemaVersion)
+			}
+			if len(env.Todos) != len(tt.statuses) {
+				t.Fatalf("got %d todos, want %d", len(env.Todos), len(tt.statuses))
+			}
+			for id, status := range tt.statuses {
+				if got := env.Todos[id].Status; got != status {
+					t.Errorf("%s: status %q, want %q", id, got, status)
+				}
+			}
+			if got := env.Todos["b2"].CompletedAt; !got.Equal(tt.completedAt) {
+				t.Errorf("b2: completed_at %v, want %v", got, tt.completedAt)
+			}
+			if got := env.Todos["a1"].CompletedAt; !got.IsZero() {
+				t.Errorf("a1: completed_at %v, want none", got)
+			}
+
+			// Migrating the result again changes nothing.
+			again, from, err := migrate(migrated)
+			if err != nil || from != SchemaVersion || !bytes.Equal(again, migrated) {
+				t.Errorf("migrating again = from %d, %v; changed: %v", from, err, !bytes.Equal(again, migrated))
+			}
+		})
+	}
+}
+
+func TestMigrateRejects(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr error
+	}{
+		{name: "newer schema", data: `{"schema_version": 99, "todos": {}}`, wantErr: errors.ErrUnsupportedSchema},
+		{name: "invalid version", data: `{"schema_version": "three", "todos": {}}`},
+		{name: "not JSON", data: `todos`},
+		{name: "todo not an object", data: `{"schema_version": 1, "todos": {"a": 1}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := migrate([]byte(tt.data))
+			if err == nil {
+				t.Fatal("migrate succeeded")
+			}
+			if tt.wantErr != nil && !stderrors.Is(err, tt.wantErr) {
+				t.Errorf("migrate = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func testConfig(t *testing.T) *config.Config {
+	t.Helper()
+	dir := t.TempDir()
+	cfg := config.Default()
+	cfg.StorageDir = filepath.Join(dir, "storage")
+	cfg.SummaryFile = filepath.Join(dir, "summary.json")
+	if err := os.MkdirAll(cfg.StorageDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	return cfg
+}
+
+func TestLoadMigratesAndBacksUp(t *testing.T) {
+	tests := []struct {
+		name       string
+		data       string
+		from       int
+		passphrase string
+	}{
+		{name: "v0", data: todoV0, from: 0},
+		{name: "v1", data: todoV1, from: 1},
+		{name: "v2", data: todoV2, from: 2},
+		{name: "encrypted v1", data: todoV1, from: 1, passphrase: "pw"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig(t)
+			cfg.Passphrase = func(string, int) (string, error) { return tt.passphrase, nil }
+			file := filepath.Join(cfg.StorageDir, "work.json")
+
+			original := []byte(tt.data)
+			if tt.passphrase != "" {
+				key, err := encryption.NewKey(tt.passphrase)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if original, err = encryption.Seal(key, original); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := os.WriteFile(file, original, 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			storage, err := NewTodoStorage(file, cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			todos := storage.List()
+
+			backup, err := os.ReadFile(backupPath(file, tt.from))
+			if err != nil {
+				t.Fatalf("no backup: %v", err)
+			}
+			if !bytes.Equal(backup, original) {
+				t.Error("the backup differs from the original file")
+			}
+
+			rewritten, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if encryption.IsEncrypted(rewritten) != (tt.passphrase != "") {
+				t.Errorf("rewritten file encrypted = %v, want %v", encryption.IsEncrypted(rewritten), tt.passphrase != "")
+			}
+			snap, from, err := Decode(file, rewritten, cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if from != SchemaVersion {
+				t.Errorf("rewritten file has schema version %d, want %d", from, SchemaVersion)
+			}
+			if len(snap.Todos) != len(todos) {
+				t.Errorf("rewritten file has %d todos, loaded %d", len(snap.Todos), len(todos))
+			}
+
+			// A second load finds nothing to migrate and leaves no new backup.
+			if err := os.Remove(backupPath(file, tt.from)); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := NewTodoStorage(file, cfg); err != nil {
+				t.Fatal(err)
+			}
+			if matches, _ := filepath.Glob(file + ".*.bak"); len(matches) > 0 {
+				t.Errorf("a second load left backups %v", matches)
+			}
+		})
+	}
+}
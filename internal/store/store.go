@@ -1,36 +1,49 @@
 // Package store provides functionality for managing and persisting todos.
 //
 // It defines the TodoStorage type, which handles CRUD operations (Create,
-// Read, Update, Delete) on todos, as well as saving them to disk and
-// generating summary files. The package abstracts away the low-level
+// Read, Update, Delete) on todos, as well as saving them to disk. The
+// package abstracts away the low-level
 // details of reading from and writing to JSON files, allowing higher-level
 // components (like the CLI menu) to interact with todos through a simple API.
 //
+// Todo files are stored as a versioned envelope holding the schema version,
+// file metadata and the todos. Files written by older versions are upgraded
+// in place on Load, after a backup of the original is written next to them.
+//
+// Files can optionally be encrypted with a passphrase; see the encryption
+// package. Encrypted files are detected on Load and the passphrase is obtained
+// through config.Config.Passphrase.
+//
 // Key features:
 //   - Load and persist todos to a JSON file
 //   - Save individual todos after validation
-//   - Delete todos by ID with error handling
+//   - Delete todos by ID into the trash, with error handling
 //   - List all stored todos
-//   - Save a summary file containing just the todo tasks
 //   - Retrieve todos by ID
 package store
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
-	"strings"
+	"sort"
 	"time"
 
 	"github.com/Ng1n3/go-todo/internal/config"
+	"github.com/Ng1n3/go-todo/internal/encryption"
 	"github.com/Ng1n3/go-todo/internal/errors"
 	"github.com/Ng1n3/go-todo/internal/types"
 )
 
 type TodoStorage struct {
 	store  map[string]types.Todo
+	meta   FileMeta
 	file   string
 	config *config.Config
+	// key is set when the file is encrypted.
+	key *encryption.Key
+	// deleted holds the todos deleted since the last Persist; they are moved
+	// to the trash once the file has been written.
+	deleted []types.Todo
 }
 
 func NewTodoStorage(file string, cfg *config.Config) (*TodoStorage, error) {
@@ -38,7 +51,13 @@ func NewTodoStorage(file string, cfg *config.Config) (*TodoStorage, error) {
 		cfg = config.Default()
 	}
 
-	ts := &TodoStorage{store: make(map[string]types.Todo), file: file, config: cfg}
+	now := time.Now()
+	ts := &TodoStorage{
+		store:  make(map[string]types.Todo),
+		meta:   FileMeta{CreatedAt: now, UpdatedAt: now},
+		file:   file,
+		config: cfg,
+	}
 	if err := ts.Load(); err != nil {
 		return nil, fmt.Errorf("failed to load todos: %w", err)
 	}
@@ -46,7 +65,7 @@ func NewTodoStorage(file string, cfg *config.Config) (*TodoStorage, error) {
 }
 
 func (ts *TodoStorage) Load() error {
-	data, err := os.ReadFile(ts.file)
+	raw, err := os.ReadFile(ts.file)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return nil
@@ -54,61 +73,129 @@ func (ts *TodoStorage) Load() error {
 		return fmt.Errorf("failed to read file: %w", err)
 	}
 
-	if len(data) == 0 {
+	if len(raw) == 0 {
 		return nil
 	}
 
-	if err := json.Unmarshal(data, &ts.store); err != nil {
-		return fmt.Errorf("failed to unmarshal todos : %w", err)
+	snap, from, err := Decode(ts.file, raw, ts.config)
+	if err != nil {
+		return err
+	}
+
+	ts.key = snap.key
+	ts.meta = snap.Meta
+	if snap.Todos != nil {
+		ts.store = snap.Todos
 	}
 
+	if from != SchemaVersion {
+		// Keep the original, encrypted or not, before rewriting the file.
+		if err := os.WriteFile(backupPath(ts.file, from), raw, snap.mode(ts.config)); err != nil {
+			return fmt.Errorf("failed to back up %s before migration: %w", ts.file, err)
+		}
+		if err := WriteSnapshot(ts.file, snap, ts.config); err != nil {
+			return fmt.Errorf("failed to write migrated file: %w", err)
+		}
+	}
 	return nil
 
 }
 
+func (ts *TodoStorage) snapshot() *Snapshot {
+	return &Snapshot{Meta: ts.meta, Todos: ts.store, key: ts.key}
+}
+
+// Persist writes the todos to the file and then moves the deleted ones to
+// the trash.
 func (ts *TodoStorage) Persist() error {
-	data, err := json.MarshalIndent(ts.store, "", " ")
-	if err != nil {
-		return fmt.Errorf("failed to marshal todos: %w", err)
+	if err := ts.Write(); err != nil {
+		return err
 	}
+	return ts.FlushDeleted()
+}
 
-	if err := os.WriteFile(ts.file, data, ts.config.FileMode); err != nil {
+// Write writes the todos to the file. Deleted todos stay queued for the
+// trash until FlushDeleted, so a caller writing several files can tell a
+// failed write, after which nothing was written, from a failure to fill the
+// trash once every file has been written.
+func (ts *TodoStorage) Write() error {
+	ts.meta.UpdatedAt = time.Now()
+	if err := WriteSnapshot(ts.file, ts.snapshot(), ts.config); err != nil {
 		return fmt.Errorf("failed to write file: %w", err)
 	}
 	return nil
 }
 
-func (ts *TodoStorage) Save(todo *types.Todo) error {
-	if err := todo.Validate(); err != nil {
-		return fmt.Errorf("invalid todo: %w", err)
+// FlushDeleted moves the todos deleted since the last write to the trash
+// and purges expired trash items.
+func (ts *TodoStorage) FlushDeleted() error {
+	trash := NewTrash(ts.config)
+	for len(ts.deleted) > 0 {
+		if _, err := trash.addTodo(ts.file, ts.deleted[0], ts.key); err != nil {
+			return err
+		}
+		ts.deleted = ts.deleted[1:]
 	}
 
-	todo.UpdatedAt = time.Now()
-	ts.store[todo.ID] = *todo
+	if _, err := trash.Purge(time.Now()); err != nil {
+		return fmt.Errorf("failed to purge trash: %w", err)
+	}
 	return nil
 }
 
-func (ts *TodoStorage) SaveSummary(summaryFile string) error {
-	tasks := make([]string, 0, len(ts.store)) // title arrays
-	for _, todo := range ts.store {
-		tasks = append(tasks, strings.TrimSpace(todo.Task))
-	}
+// Encrypted reports whether the todo file is encrypted.
+func (ts *TodoStorage) Encrypted() bool {
+	return ts.key != nil
+}
 
-	summary := map[string][]string{
-		ts.file: tasks,
+// SetPassphrase encrypts the file with a key derived from passphrase from the
+// next Persist on. Calling it on an encrypted file rotates the passphrase.
+func (ts *TodoStorage) SetPassphrase(passphrase string) error {
+	key, err := encryption.NewKey(passphrase)
+	if err != nil {
+		return err
 	}
+	ts.key = key
+	return nil
+}
 
-	data, err := json.MarshalIndent(summary, "", " ")
-	if err != nil {
-		return fmt.Errorf("failed to marashal summary: %w", err)
+// ShareKey encrypts the file with the same key as other from the next
+// Persist on, so companion files don't need a passphrase of their own.
+func (ts *TodoStorage) ShareKey(other *TodoStorage) {
+	ts.key = other.key
+}
+
+// ClearPassphrase stores the file as plain JSON from the next Persist on.
+func (ts *TodoStorage) ClearPassphrase() {
+	ts.key = nil
+}
+
+func (ts *TodoStorage) Save(todo *types.Todo) error {
+	if err := todo.Validate(); err != nil {
+		return fmt.Errorf("invalid todo: %w", err)
 	}
 
-	if err := os.WriteFile(summaryFile, data, ts.config.FileMode); err != nil {
-		return fmt.Errorf("failed to write summary file: %w", err)
+	todo.UpdatedAt = time.Now()
+	ts.store[todo.ID] = *todo
+	return nil
+}
+
+// Put stores todo exactly as given, keeping its timestamps. It is used when
+// todos are moved between files rather than edited.
+func (ts *TodoStorage) Put(todo types.Todo) error {
+	if err := todo.Validate(); err != nil {
+		return fmt.Errorf("invalid todo: %w", err)
 	}
 
+	ts.store[todo.ID] = todo
 	return nil
+}
 
+// Reload discards unsaved changes and reads the file again.
+func (ts *TodoStorage) Reload() error {
+	ts.store = make(map[string]types.Todo)
+	ts.deleted = nil
+	return ts.Load()
 }
 
 func (ts *TodoStorage) Get(id string) (types.Todo, error) {
@@ -120,7 +207,21 @@ func (ts *TodoStorage) Get(id string) (types.Todo, error) {
 	return todo, nil
 }
 
+// Delete removes a todo. It is moved to the trash on the next Persist.
 func (ts *TodoStorage) Delete(id string) error {
+	todo, exists := ts.store[id]
+	if !exists {
+		return errors.ErrTodoNotFound
+	}
+
+	delete(ts.store, id)
+	ts.deleted = append(ts.deleted, todo)
+	return nil
+}
+
+// Remove removes a todo without sending it to the trash. It is used when
+// todos are moved to another file rather than deleted.
+func (ts *TodoStorage) Remove(id string) error {
 	if _, exists := ts.store[id]; !exists {
 		return errors.ErrTodoNotFound
 	}
@@ -129,14 +230,85 @@ func (ts *TodoStorage) Delete(id string) error {
 	return nil
 }
 
+// List returns all todos ordered by creation time, so that repeated listings
+// show the rows in the same order.
 func (ts *TodoStorage) List() []types.Todo {
 	todos := make([]types.Todo, 0, len(ts.store))
 	for _, todo := range ts.store {
 		todos = append(todos, todo)
 	}
+
+	sort.Slice(todos, func(i, j int) bool {
+		if !todos[i].CreatedAt.Equal(todos[j].CreatedAt) {
+			return todos[i].CreatedAt.Before(todos[j].CreatedAt)
+		}
+		return todos[i].ID < todos[j].ID
+	})
 	return todos
 }
 
 func (ts *TodoStorage) Count() int {
 	return len(ts.store)
 }
+
+// Workflow returns the status workflow of the file.
+func (ts *TodoStorage) Workflow() *types.Workflow {
+	if ts.meta.Workflow != nil {
+		return ts.meta.Workflow
+	}
+	return types.DefaultWorkflow()
+}
+
+// SetWorkflow replaces the status workflow of the file from the next Persist
+// on; nil restores the default. Every todo must be in a status the new
+// workflow knows.
+func (ts *TodoStorage) SetWorkflow(w *types.Workflow) error {
+	check := w
+	if check == nil {
+		check = types.DefaultWorkflow()
+	}
+	if err := check.Validate(); err != nil {
+		return err
+	}
+
+	for _, todo := range ts.List() {
+		if !check.Has(todo.Status) {
+			return fmt.Errorf("%w: todo %s is in status %q, which the workflow doesn't have", errors.ErrInvalidWorkflow, todo.ID, todo.Status)
+		}
+	}
+
+	// Done statuses may have changed, so derive Completed again.
+	now := time.Now()
+	for id, todo := range ts.store {
+		check.Adopt(&todo, now)
+		ts.store[id] = todo
+	}
+
+	ts.meta.Workflow = w
+	return nil
+}
+
+// AllowedLabels returns the label allow-list of the file, or nil if any
+// label is allowed.
+func (ts *TodoStorage) AllowedLabels() []string {
+	return ts.meta.Labels
+}
+
+// SetAllowedLabels replaces the label allow-list of the file from the next
+// Persist on; an empty list allows any label.
+func (ts *TodoStorage) SetAllowedLabels(labels []string) {
+	if len(labels) == 0 {
+		labels = nil
+	}
+	ts.meta.Labels = labels
+}
+
+// Meta returns the metadata of the todo file.
+func (ts *TodoStorage) Meta() FileMeta {
+	return ts.meta
+}
+
+// File returns the path of the todo file.
+func (ts *TodoStorage) File() string {
+	return ts.file
+}
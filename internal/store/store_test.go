@@ -0,0 +1,68 @@
+package store
+
+import (
+	"bytes"
+	stderrors "errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Ng1n3/go-todo/internal/errors"
+	"github.com/Ng1n3/go-todo/internal/types"
+)
+
+func TestLoadEncryptedWithWrongPassphrase(t *testing.T) {
+	cfg := testConfig(t)
+	file := filepath.Join(cfg.StorageDir, "secret.json")
+
+	cfg.Passphrase = func(string, int) (string, error) { return "right", nil }
+	storage, err := NewTodoStorage(file, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := storage.SetPassphrase("right"); err != nil {
+		t.Fatal(err)
+	}
+	if err := storage.Put(types.Todo{ID: "a1", Task: "hidden", Priority: types.Low, Status: types.StatusTodo}); err != nil {
+		t.Fatal(err)
+	}
+	if err := storage.Persist(); err != nil {
+		t.Fatal(err)
+	}
+	raw, _ := os.ReadFile(file)
+	if bytes.Contains(raw, []byte("hidden")) {
+		t.Fatal("the encrypted file contains the task in plain text")
+	}
+
+	attempts := 0
+	cfg.Passphrase = func(string, int) (string, error) { attempts++; return "wrong", nil }
+	if _, err := NewTodoStorage(file, cfg); !stderrors.Is(err, errors.ErrWrongPassphrase) {
+		t.Fatalf("loading with a wrong passphrase = %v, want ErrWrongPassphrase", err)
+	}
+	if attempts != maxPassphraseAttempts {
+		t.Errorf("asked %d times for the passphrase, want %d", attempts, maxPassphraseAttempts)
+	}
+
+	// Rotating the passphrase makes the old one fail.
+	cfg.Passphrase = func(string, int) (string, error) { return "right", nil }
+	if storage, err = NewTodoStorage(file, cfg); err != nil {
+		t.Fatal(err)
+	}
+	if err := storage.SetPassphrase("new"); err != nil {
+		t.Fatal(err)
+	}
+	if err := storage.Persist(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewTodoStorage(file, cfg); !stderrors.Is(err, errors.ErrWrongPassphrase) {
+		t.Fatalf("loading with the old passphrase = %v, want ErrWrongPassphrase", err)
+	}
+	cfg.Passphrase = func(string, int) (string, error) { return "new", nil }
+	storage, err = NewTodoStorage(file, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if todo, err := storage.Get("a1"); err != nil || todo.Task != "hidden" {
+		t.Errorf("Get(a1) = %+v, %v after rekey", todo, err)
+	}
+}
@@ -0,0 +1,363 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Ng1n3/go-todo/internal/config"
+	"github.com/Ng1n3/go-todo/internal/encryption"
+	"github.com/Ng1n3/go-todo/internal/errors"
+	"github.com/Ng1n3/go-todo/internal/types"
+	"github.com/Ng1n3/go-todo/internal/utils"
+)
+
+// TrashDir is the hidden directory inside the storage directory that holds
+// deleted todos and todo files until they are restored or purged.
+const TrashDir = ".trash"
+
+type TrashKind string
+
+const (
+	TrashTodo TrashKind = "todo"
+	TrashFile TrashKind = "file"
+)
+
+// TrashItem describes one deleted todo or todo file. It is stored as
+// <id>.json in the trash directory, next to the deleted content in
+// <id>.data. The content of a deleted todo is a todo file holding just that
+// todo, encrypted with the key of the file it came from. A deleted file's
+// archive goes along with it into <id>.archive.
+type TrashItem struct {
+	ID        string    `json:"id"`
+	Kind      TrashKind `json:"kind"`
+	DeletedAt time.Time `json:"deleted_at"`
+	// File is the original location, relative to the storage directory.
+	File string `json:"file"`
+	// TodoID and Task describe a deleted todo. Task is empty when the todo
+	// came from an encrypted file.
+	TodoID    string `json:"todo_id,omitempty"`
+	Task      string `json:"task,omitempty"`
+	Encrypted bool   `json:"encrypted,omitempty"`
+	// Archive reports whether a deleted file had an archive, which was
+	// deleted with it.
+	Archive bool `json:"archive,omitempty"`
+}
+
+// ArchiveFile returns the original location of the archive deleted with a
+// file, relative to the storage directory, or "" if there was none.
+func (it TrashItem) ArchiveFile() string {
+	if !it.Archive {
+		return ""
+	}
+	return filepath.Join(config.ArchiveDir, filepath.Base(it.File))
+}
+
+// ExpiresAt returns when the item is purged for the given retention, or the
+// zero time if it is kept forever.
+func (it TrashItem) ExpiresAt(retention time.Duration) time.Time {
+	if retention <= 0 {
+		return time.Time{}
+	}
+	return it.DeletedAt.Add(retention)
+}
+
+// Trash is the trash area of a storage directory.
+type Trash struct {
+	dir    string
+	config *config.Config
+}
+
+func NewTrash(cfg *config.Config) *Trash {
+	if cfg == nil {
+		cfg = config.Default()
+	}
+	return &Trash{dir: filepath.Join(cfg.StorageDir, TrashDir), config: cfg}
+}
+
+func (t *Trash) metaPath(id string) string {
+	return filepath.Join(t.dir, id+".json")
+}
+
+// DataPath returns the path of the deleted content of an item.
+func (t *Trash) DataPath(id string) string {
+	return filepath.Join(t.dir, id+".data")
+}
+
+func (t *Trash) archivePath(id string) string {
+	return filepath.Join(t.dir, id+".archive")
+}
+
+// addTodo moves a todo deleted from file into the trash, encrypted with key
+// when the file is encrypted.
+func (t *Trash) addTodo(file string, todo types.Todo, key *encryption.Key) (TrashItem, error) {
+	item := TrashItem{
+		Kind:      TrashTodo,
+		File:      t.relative(file),
+		TodoID:    todo.ID,
+		Encrypted: key != nil,
+	}
+	if !item.Encrypted {
+		item.Task = todo.Task
+	}
+
+	return t.add(item, func(id string) error {
+		now := time.Now()
+		snap := &Snapshot{
+			Meta:  FileMeta{CreatedAt: now, UpdatedAt: now},
+			Todos: map[string]types.Todo{todo.ID: todo},
+			key:   key,
+		}
+		return WriteSnapshot(t.DataPath(id), snap, t.config)
+	})
+}
+
+// AddFile moves a whole todo file into the trash, together with its archive
+// so that a new file of the same name starts without one.
+func (t *Trash) AddFile(file string) (TrashItem, error) {
+	if _, err := os.Stat(file); err != nil {
+		return TrashItem{}, fmt.Errorf("failed to delete %s: %w", filepath.Base(file), err)
+	}
+
+	item := TrashItem{Kind: TrashFile, File: t.relative(file)}
+	archive := t.config.GetArchivePath(file)
+	if _, err := os.Stat(archive); err == nil && archive != file {
+		item.Archive = true
+	}
+	return t.add(item, func(id string) error {
+		if err := os.Rename(file, t.DataPath(id)); err != nil {
+			return err
+		}
+		if !item.Archive {
+			return nil
+		}
+		if err := os.Rename(archive, t.archivePath(id)); err != nil {
+			os.Rename(t.DataPath(id), file)
+			return err
+		}
+		return nil
+	})
+}
+
+// add writes item to the trash and calls write with its new ID to move the
+// deleted content in.
+func (t *Trash) add(item TrashItem, write func(id string) error) (TrashItem, error) {
+	if err := os.MkdirAll(t.dir, 0700); err != nil {
+		return TrashItem{}, fmt.Errorf("failed to create trash directory: %w", err)
+	}
+
+	item.ID = utils.GenerateID(8)
+	item.DeletedAt = time.Now()
+
+	data, err := json.MarshalIndent(item, "", " ")
+	if err != nil {
+		return TrashItem{}, fmt.Errorf("failed to marshal trash item: %w", err)
+	}
+	if err := WriteFileAtomic(t.metaPath(item.ID), data, t.config.FileMode); err != nil {
+		return TrashItem{}, fmt.Errorf("failed to write trash item: %w", err)
+	}
+	if err := write(item.ID); err != nil {
+		os.Remove(t.metaPath(item.ID))
+		return TrashItem{}, fmt.Errorf("failed to move %s to the trash: %w", item.File, err)
+	}
+	return item, nil
+}
+
+// List returns the items in the trash, most recently deleted first. Items
+// older than the configured retention are purged first.
+func (t *Trash) List() ([]TrashItem, error) {
+	if _, err := t.Purge(time.Now()); err != nil {
+		return nil, err
+	}
+	return t.list()
+}
+
+func (t *Trash) list() ([]TrashItem, error) {
+	entries, err := os.ReadDir(t.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read trash directory: %w", err)
+	}
+
+	var items []TrashItem
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || strings.HasPrefix(name, ".") || !strings.HasSuffix(name, ".json") {
+			continue
+		}
+		item, err := t.Get(strings.TrimSuffix(name, ".json"))
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	sort.Slice(items, func(i, j int) bool { return items[i].DeletedAt.After(items[j].DeletedAt) })
+	return items, nil
+}
+
+// Get returns the trash item with the given ID.
+func (t *Trash) Get(id string) (TrashItem, error) {
+	data, err := os.ReadFile(t.metaPath(id))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return TrashItem{}, fmt.Errorf("%s: %w", id, errors.ErrTrashItemNotFound)
+		}
+		return TrashItem{}, fmt.Errorf("failed to read trash item %s: %w", id, err)
+	}
+
+	var item TrashItem
+	if err := json.Unmarshal(data, &item); err != nil {
+		return TrashItem{}, fmt.Errorf("failed to unmarshal trash item %s: %w", id, err)
+	}
+	return item, nil
+}
+
+// RestoreFile moves a deleted todo file, and the archive deleted with it,
+// back to their original locations. It fails if a file with either name
+// exists again.
+func (t *Trash) RestoreFile(item TrashItem) error {
+	if item.Kind != TrashFile {
+		return fmt.Errorf("%w: %s is not a deleted file", errors.ErrInvalidInput, item.ID)
+	}
+
+	target := t.config.GetFullPath(item.File)
+	if _, err := os.Stat(target); err == nil {
+		return fmt.Errorf("%s: %w", item.File, errors.ErrFileExists)
+	}
+	archive := t.config.GetFullPath(item.ArchiveFile())
+	if item.Archive {
+		if _, err := os.Stat(archive); err == nil {
+			return fmt.Errorf("%s: %w", item.ArchiveFile(), errors.ErrFileExists)
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", item.File, err)
+	}
+	if err := os.Rename(t.DataPath(item.ID), target); err != nil {
+		return fmt.Errorf("failed to restore %s: %w", item.File, err)
+	}
+	if item.Archive {
+		err := os.MkdirAll(filepath.Dir(archive), 0755)
+		if err == nil {
+			err = os.Rename(t.archivePath(item.ID), archive)
+		}
+		if err != nil {
+			os.Rename(target, t.DataPath(item.ID))
+			return fmt.Errorf("failed to restore %s: %w", item.ArchiveFile(), err)
+		}
+	}
+	return t.Remove(item.ID)
+}
+
+// RestoreTodo puts a deleted todo back into storage, which is normally the
+// todo file it was deleted from, and returns it. The todo gets a new ID if
+// its ID has been taken since. When storage is a new file and the todo came
+// from an encrypted one, storage is encrypted with the same key. storage is
+// not persisted and the item stays in the trash until it is removed.
+func (t *Trash) RestoreTodo(item TrashItem, storage *TodoStorage) (types.Todo, error) {
+	if item.Kind != TrashTodo {
+		return types.Todo{}, fmt.Errorf("%w: %s is not a deleted todo", errors.ErrInvalidInput, item.ID)
+	}
+
+	raw, err := os.ReadFile(t.DataPath(item.ID))
+	if err != nil {
+		return types.Todo{}, fmt.Errorf("failed to read trash item %s: %w", item.ID, err)
+	}
+	snap, _, err := Decode(t.DataPath(item.ID), raw, t.config)
+	if err != nil {
+		return types.Todo{}, err
+	}
+
+	todo, ok := snap.Todos[item.TodoID]
+	if !ok {
+		return types.Todo{}, fmt.Errorf("trash item %s: %w", item.ID, errors.ErrTodoNotFound)
+	}
+
+	for {
+		if _, taken := storage.store[todo.ID]; !taken {
+			break
+		}
+		todo.ID = utils.GenerateID(6)
+	}
+
+	if _, err := os.Stat(storage.file); os.IsNotExist(err) && storage.key == nil {
+		storage.key = snap.key
+	}
+	storage.Workflow().Adopt(&todo, time.Now())
+	if err := storage.Put(todo); err != nil {
+		return types.Todo{}, err
+	}
+	return todo, nil
+}
+
+// Remove deletes an item from the trash for good.
+func (t *Trash) Remove(id string) error {
+	for _, path := range []string{t.DataPath(id), t.archivePath(id)} {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove trash item %s: %w", id, err)
+		}
+	}
+	if err := os.Remove(t.metaPath(id)); err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("%s: %w", id, errors.ErrTrashItemNotFound)
+		}
+		return fmt.Errorf("failed to remove trash item %s: %w", id, err)
+	}
+	return nil
+}
+
+// Empty deletes every item in the trash and returns how many there were.
+func (t *Trash) Empty() (int, error) {
+	items, err := t.list()
+	if err != nil {
+		return 0, err
+	}
+	for _, item := range items {
+		if err := t.Remove(item.ID); err != nil {
+			return 0, err
+		}
+	}
+	return len(items), nil
+}
+
+// Purge deletes the items that have been in the trash longer than the
+// configured retention at now, and returns how many were deleted.
+func (t *Trash) Purge(now time.Time) (int, error) {
+	if t.config.TrashRetention <= 0 {
+		return 0, nil
+	}
+
+	items, err := t.list()
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for _, item := range items {
+		if now.Before(item.ExpiresAt(t.config.TrashRetention)) {
+			continue
+		}
+		if err := t.Remove(item.ID); err != nil {
+			return purged, err
+		}
+		purged++
+	}
+	return purged, nil
+}
+
+// relative returns file relative to the storage directory.
+func (t *Trash) relative(file string) string {
+	rel, err := filepath.Rel(t.config.StorageDir, file)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return filepath.Base(file)
+	}
+	return rel
+}
@@ -0,0 +1,265 @@
+// Package summary maintains the workspace index stored in the summary file
+// (save_todos.json by default).
+//
+// The index has one entry per todo file in the storage directory with its
+// open and done counts, the due dates of its open todos, its labels and its
+// task titles. Saving a todo file updates only that file's entry; Refresh
+// picks up files that were changed, added or removed outside the app by
+// comparing modification times. Overdue counts and the next due date are
+// derived from the stored due dates when read, so they never go stale.
+package summary
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/Ng1n3/go-todo/internal/config"
+	"github.com/Ng1n3/go-todo/internal/store"
+	"github.com/Ng1n3/go-todo/internal/types"
+)
+
+// indexVersion is bumped whenever the layout of the summary file changes.
+const indexVersion = 1
+
+// FileSummary describes a single todo file.
+type FileSummary struct {
+	ModTime   time.Time `json:"mod_time"`
+	Size      int64     `json:"size"`
+	Encrypted bool      `json:"encrypted,omitempty"`
+	Total     int       `json:"total"`
+	Open      int       `json:"open"`
+	Done      int       `json:"done"`
+	// OpenDue holds the sorted due dates of the open todos that have one.
+	OpenDue []time.Time `json:"open_due"`
+	// Labels and Tasks are left empty for encrypted files.
+	Labels []string `json:"labels"`
+	Tasks  []string `json:"tasks"`
+}
+
+// Overdue counts the open todos whose deadline has passed at now.
+func (fs *FileSummary) Overdue(now time.Time) int {
+	n := 0
+	for _, due := range fs.OpenDue {
+		if !now.Before(types.Todo{DueDate: due}.Deadline()) {
+			n++
+		}
+	}
+	return n
+}
+
+// NextDue returns the earliest due date of an open todo that is not overdue
+// at now, or the zero time if there is none.
+func (fs *FileSummary) NextDue(now time.Time) time.Time {
+	for _, due := range fs.OpenDue {
+		if now.Before(types.Todo{DueDate: due}.Deadline()) {
+			return due
+		}
+	}
+	return time.Time{}
+}
+
+type Index struct {
+	Version   int                     `json:"version"`
+	UpdatedAt time.Time               `json:"updated_at"`
+	Files     map[string]*FileSummary `json:"files"`
+
+	config *config.Config
+}
+
+// Load reads the summary file. A missing file, or one in the old per-file
+// format, yields an empty index that Refresh fills in.
+func Load(cfg *config.Config) (*Index, error) {
+	if cfg == nil {
+		cfg = config.Default()
+	}
+
+	idx := &Index{config: cfg}
+	data, err := os.ReadFile(cfg.SummaryFile)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read summary file: %w", err)
+	}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, idx); err != nil || idx.Version != indexVersion {
+			idx.Files = nil
+		}
+	}
+
+	idx.Version = indexVersion
+	if idx.Files == nil {
+		idx.Files = make(map[string]*FileSummary)
+	}
+	return idx, nil
+}
+
+// Current loads the index, refreshes it against the storage directory and
+// saves it if anything changed.
+func Current(cfg *config.Config) (*Index, error) {
+	idx, err := Load(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	changed, err := idx.Refresh()
+	if err != nil {
+		return nil, err
+	}
+	if changed {
+		if err := idx.Save(); err != nil {
+			return nil, err
+		}
+	}
+	return idx, nil
+}
+
+// Record updates the entry of a single todo file after it was saved and
+// writes the summary file.
+func Record(cfg *config.Config, file string, todos []types.Todo, encrypted bool) error {
+	idx, err := Load(cfg)
+	if err != nil {
+		return err
+	}
+
+	info, err := os.Stat(file)
+	if err != nil {
+		return fmt.Errorf("failed to stat %s: %w", file, err)
+	}
+
+	idx.Files[filepath.Base(file)] = summarize(todos, info, encrypted)
+	return idx.Save()
+}
+
+// Refresh re-summarizes every todo file whose size or modification time
+// changed and drops files that no longer exist. It reports whether anything
+// changed.
+func (idx *Index) Refresh() (bool, error) {
+	files, err := store.ListFiles(idx.config.StorageDir)
+	if err != nil {
+		return false, err
+	}
+
+	changed := false
+	seen := make(map[string]bool, len(files))
+	for _, name := range files {
+		seen[name] = true
+		path := idx.config.GetFullPath(name)
+
+		info, err := os.Stat(path)
+		if err != nil {
+			return false, fmt.Errorf("failed to stat %s: %w", name, err)
+		}
+
+		if entry, ok := idx.Files[name]; ok && entry.ModTime.Equal(info.ModTime()) && entry.Size == info.Size() {
+			continue
+		}
+
+		storage, err := store.NewTodoStorage(path, idx.config)
+		if err != nil {
+			return false, fmt.Errorf("failed to summarize %s: %w", name, err)
+		}
+		idx.Files[name] = summarize(storage.List(), info, storage.Encrypted())
+		changed = true
+	}
+
+	for name := range idx.Files {
+		if !seen[name] {
+			delete(idx.Files, name)
+			changed = true
+		}
+	}
+	return changed, nil
+}
+
+func (idx *Index) Save() error {
+	idx.UpdatedAt = time.Now()
+	data, err := json.MarshalIndent(idx, "", " ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal summary: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(idx.config.SummaryFile), 0755); err != nil {
+		return fmt.Errorf("failed to create summary directory: %w", err)
+	}
+	if err := store.WriteFileAtomic(idx.config.SummaryFile, data, idx.config.FileMode); err != nil {
+		return fmt.Errorf("failed to write summary file: %w", err)
+	}
+	return nil
+}
+
+// Names returns the summarized file names in sorted order.
+func (idx *Index) Names() []string {
+	names := make([]string, 0, len(idx.Files))
+	for name := range idx.Files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Totals adds up the summaries of all files.
+func (idx *Index) Totals() *FileSummary {
+	total := &FileSummary{}
+	labels := make(map[string]bool)
+
+	for _, fs := range idx.Files {
+		total.Total += fs.Total
+		total.Open += fs.Open
+		total.Done += fs.Done
+		total.OpenDue = append(total.OpenDue, fs.OpenDue...)
+		for _, label := range fs.Labels {
+			labels[label] = true
+		}
+	}
+
+	sort.Slice(total.OpenDue, func(i, j int) bool { return total.OpenDue[i].Before(total.OpenDue[j]) })
+	total.Labels = sortedSet(labels)
+	return total
+}
+
+func summarize(todos []types.Todo, info os.FileInfo, encrypted bool) *FileSummary {
+	fs := &FileSummary{
+		ModTime:   info.ModTime(),
+		Size:      info.Size(),
+		Encrypted: encrypted,
+		Total:     len(todos),
+		OpenDue:   []time.Time{},
+		Labels:    []string{},
+		Tasks:     []string{},
+	}
+
+	labels := make(map[string]bool)
+	for _, todo := range todos {
+		if todo.Completed {
+			fs.Done++
+		} else {
+			fs.Open++
+			if todo.HasDueDate() {
+				fs.OpenDue = append(fs.OpenDue, todo.DueDate)
+			}
+		}
+
+		if encrypted {
+			continue
+		}
+		fs.Tasks = append(fs.Tasks, todo.Task)
+		for _, label := range todo.Labels {
+			labels[label] = true
+		}
+	}
+
+	sort.Slice(fs.OpenDue, func(i, j int) bool { return fs.OpenDue[i].Before(fs.OpenDue[j]) })
+	fs.Labels = sortedSet(labels)
+	return fs
+}
+
+func sortedSet(set map[string]bool) []string {
+	out := make([]string, 0, len(set))
+	for item := range set {
+		out = append(out, item)
+	}
+	sort.Strings(out)
+	return out
+}
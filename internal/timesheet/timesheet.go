@@ -0,0 +1,165 @@
+// Package timesheet totals the time logged on todos over a period, grouped
+// by todo, label, file or day, for display or as CSV for invoicing.
+package timesheet
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/Ng1n3/go-todo/internal/errors"
+	"github.com/Ng1n3/go-todo/internal/service"
+	"github.com/Ng1n3/go-todo/internal/types"
+	"github.com/Ng1n3/go-todo/internal/utils"
+)
+
+// Grouping is what the report totals time by.
+type Grouping string
+
+const (
+	ByTodo  Grouping = "todo"
+	ByLabel Grouping = "label"
+	ByFile  Grouping = "file"
+	ByDay   Grouping = "day"
+)
+
+// noLabel is the label row of time logged on todos without labels.
+const noLabel = "(no label)"
+
+func ParseGrouping(value string) (Grouping, error) {
+	switch g := Grouping(value); g {
+	case ByTodo, ByLabel, ByFile, ByDay:
+		return g, nil
+	case "":
+		return ByTodo, nil
+	default:
+		return "", fmt.Errorf("%w: unknown grouping %q, use todo, label, file or day", errors.ErrInvalidInput, value)
+	}
+}
+
+// Options selects the period the report covers and how it is grouped. From
+// and To are inclusive days; an entry belongs to the local day it started on.
+type Options struct {
+	From time.Time
+	To   time.Time
+	By   Grouping
+}
+
+type Report struct {
+	From time.Time
+	To   time.Time
+	By   Grouping
+	Rows []Row
+	// Total is the time logged in the period. With ByLabel it can be less
+	// than the sum of the rows, as time on a todo with several labels counts
+	// towards each of them.
+	Total   time.Duration
+	Entries int
+}
+
+// Row is the time logged on one todo, label, file or day. File and TodoID
+// are only set when grouping by todo.
+type Row struct {
+	Name     string
+	File     string
+	TodoID   string
+	Entries  int
+	Duration time.Duration
+}
+
+// Compute builds the report for the time logged on the todos of files.
+func Compute(files []service.FileTodos, opts Options) *Report {
+	r := &Report{From: opts.From, To: opts.To, By: opts.By}
+	from, to := opts.From.Format("2006-01-02"), opts.To.Format("2006-01-02")
+
+	rows := make(map[string]*Row)
+	add := func(key string, row Row, entry types.TimeEntry) {
+		existing, ok := rows[key]
+		if !ok {
+			existing = &row
+			rows[key] = existing
+		}
+		existing.Entries++
+		existing.Duration += entry.Duration()
+	}
+
+	for _, file := range files {
+		for _, todo := range file.Todos {
+			for _, entry := range todo.TimeEntries {
+				day := entry.Start.In(time.Local).Format("2006-01-02")
+				if day < from || day > to {
+					continue
+				}
+				r.Entries++
+				r.Total += entry.Duration()
+
+				switch opts.By {
+				case ByLabel:
+					if len(todo.Labels) == 0 {
+						add(noLabel, Row{Name: noLabel}, entry)
+					}
+					for _, label := range todo.Labels {
+						add("label|"+utils.NormalizeLabel(label), Row{Name: label}, entry)
+					}
+				case ByFile:
+					add(file.File, Row{Name: file.File}, entry)
+				case ByDay:
+					add(day, Row{Name: day}, entry)
+				default:
+					add(file.File+"|"+todo.ID, Row{Name: todo.Task, File: file.File, TodoID: todo.ID}, entry)
+				}
+			}
+		}
+	}
+
+	r.Rows = make([]Row, 0, len(rows))
+	for _, row := range rows {
+		r.Rows = append(r.Rows, *row)
+	}
+	sort.Slice(r.Rows, func(i, j int) bool {
+		a, b := r.Rows[i], r.Rows[j]
+		if opts.By != ByDay && a.Duration != b.Duration {
+			return a.Duration > b.Duration
+		}
+		return a.Name < b.Name
+	})
+	return r
+}
+
+// Hours returns d in hours rounded to two decimals, as billed.
+func Hours(d time.Duration) string {
+	return strconv.FormatFloat(d.Hours(), 'f', 2, 64)
+}
+
+// WriteCSV writes the rows of the report as CSV with a header line. Time is
+// given in hours with two decimals.
+func (r *Report) WriteCSV(w io.Writer) error {
+	out := csv.NewWriter(w)
+
+	header := []string{string(r.By), "entries", "hours"}
+	if r.By == ByTodo {
+		header = []string{"file", "id", "task", "entries", "hours"}
+	}
+	if err := out.Write(header); err != nil {
+		return fmt.Errorf("failed to write csv: %w", err)
+	}
+
+	for _, row := range r.Rows {
+		record := []string{row.Name, strconv.Itoa(row.Entries), Hours(row.Duration)}
+		if r.By == ByTodo {
+			record = append([]string{row.File, row.TodoID}, record...)
+		}
+		if err := out.Write(record); err != nil {
+			return fmt.Errorf("failed to write csv: %w", err)
+		}
+	}
+
+	out.Flush()
+	if err := out.Error(); err != nil {
+		return fmt.Errorf("failed to write csv: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package types
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Estimate is the expected size of a todo, either as time in whole minutes
+// or in story points. Exactly one of the two is set.
+type Estimate struct {
+	Minutes int     `json:"minutes,omitempty"`
+	Points  float64 `json:"points,omitempty"`
+}
+
+// IsPoints reports whether the estimate is in story points.
+func (e Estimate) IsPoints() bool {
+	return e.Points > 0
+}
+
+// Duration returns a time estimate as a duration; it is zero for points.
+func (e Estimate) Duration() time.Duration {
+	return time.Duration(e.Minutes) * time.Minute
+}
+
+// String renders the estimate the way it is entered, e.g. "1h30m", "45m" or
+// "3pt".
+func (e Estimate) String() string {
+	if e.IsPoints() {
+		return strconv.FormatFloat(e.Points, 'f', -1, 64) + "pt"
+	}
+	return FormatMinutes(e.Minutes)
+}
+
+// FormatMinutes renders minutes as hours and minutes, e.g. "2h", "1h30m" or
+// "45m".
+func FormatMinutes(minutes int) string {
+	h, m := minutes/60, minutes%60
+	switch {
+	case h == 0:
+		return fmt.Sprintf("%dm", m)
+	case m == 0:
+		return fmt.Sprintf("%dh", h)
+	default:
+		return fmt.Sprintf("%dh%dm", h, m)
+	}
+}
+
+// SumEstimates adds up the estimates of todos, separately for time and
+// points.
+func SumEstimates(todos []Todo) (minutes int, points float64) {
+	for _, todo := range todos {
+		if todo.Estimate == nil {
+			continue
+		}
+		minutes += todo.Estimate.Minutes
+		points += todo.Estimate.Points
+	}
+	return minutes, points
+}
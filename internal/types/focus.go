@@ -0,0 +1,16 @@
+package types
+
+import "time"
+
+// FocusSession is a focus work interval spent on a todo. Interrupted
+// sessions were stopped before the interval was over.
+type FocusSession struct {
+	Start       time.Time `json:"start"`
+	End         time.Time `json:"end"`
+	Interrupted bool      `json:"interrupted,omitempty"`
+}
+
+// Duration returns how long the session lasted.
+func (s FocusSession) Duration() time.Duration {
+	return s.End.Sub(s.Start)
+}
@@ -0,0 +1,18 @@
+package types
+
+// LinkKind says what a link points to.
+type LinkKind string
+
+const (
+	LinkURL  LinkKind = "url"
+	LinkFile LinkKind = "file"
+	// LinkTodo points to another todo in the same file, by ID.
+	LinkTodo LinkKind = "todo"
+)
+
+// Link attaches a URL, a file path or another todo to a todo.
+type Link struct {
+	Kind   LinkKind `json:"kind"`
+	Target string   `json:"target"`
+	Title  string   `json:"title,omitempty"`
+}
@@ -0,0 +1,79 @@
+package types
+
+import (
+	"regexp"
+	"strings"
+)
+
+// placeholder matches a template variable such as {{version}}.
+var placeholder = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_-]*)\s*\}\}`)
+
+// Template is a named bundle of todos that are created together, such as a
+// release or onboarding checklist. Tasks, labels, notes and checklist items
+// may contain placeholders like {{version}} that are filled in when the
+// template is applied.
+type Template struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// Variables declares placeholders with a prompt or a default value.
+	// Placeholders that aren't declared are still asked for.
+	Variables []TemplateVariable `json:"variables,omitempty"`
+	Todos     []TemplateTodo     `json:"todos"`
+}
+
+type TemplateVariable struct {
+	Name    string `json:"name"`
+	Prompt  string `json:"prompt,omitempty"`
+	Default string `json:"default,omitempty"`
+}
+
+// TemplateTodo is a todo of a template. Due is an offset from the day the
+// template is applied, e.g. "+3d" or "-1w"; empty means that day.
+type TemplateTodo struct {
+	Task     string   `json:"task"`
+	Due      string   `json:"due,omitempty"`
+	Priority Priority `json:"priority,omitempty"`
+	Labels   []string `json:"labels,omitempty"`
+	Estimate string   `json:"estimate,omitempty"`
+	Notes    string   `json:"notes,omitempty"`
+	// Checklist items are added to the notes as a Markdown task list.
+	Checklist []string `json:"checklist,omitempty"`
+}
+
+// Placeholders returns the declared variables followed by the undeclared
+// placeholders used in the todos, in order of appearance.
+func (t *Template) Placeholders() []TemplateVariable {
+	vars := append([]TemplateVariable(nil), t.Variables...)
+	seen := make(map[string]bool)
+	for _, v := range vars {
+		seen[v.Name] = true
+	}
+
+	find := func(text string) {
+		for _, m := range placeholder.FindAllStringSubmatch(text, -1) {
+			if !seen[m[1]] {
+				seen[m[1]] = true
+				vars = append(vars, TemplateVariable{Name: m[1]})
+			}
+		}
+	}
+	for _, todo := range t.Todos {
+		find(todo.Task)
+		find(strings.Join(todo.Labels, ","))
+		find(todo.Notes)
+		find(strings.Join(todo.Checklist, "\n"))
+	}
+	return vars
+}
+
+// Fill replaces the placeholders of text with their values. Placeholders
+// without a value are left as they are.
+func Fill(text string, values map[string]string) string {
+	return placeholder.ReplaceAllStringFunc(text, func(s string) string {
+		name := placeholder.FindStringSubmatch(s)[1]
+		if value, ok := values[name]; ok {
+			return value
+		}
+		return s
+	})
+}
@@ -0,0 +1,24 @@
+package types
+
+import "time"
+
+// TimeEntry is a span of time worked on a todo.
+type TimeEntry struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	Note  string    `json:"note,omitempty"`
+}
+
+// Duration returns how long the entry lasted.
+func (e TimeEntry) Duration() time.Duration {
+	return e.End.Sub(e.Start)
+}
+
+// Tracked returns the total time logged on the todo.
+func (t Todo) Tracked() time.Duration {
+	var total time.Duration
+	for _, entry := range t.TimeEntries {
+		total += entry.Duration()
+	}
+	return total
+}
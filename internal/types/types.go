@@ -18,14 +18,31 @@ const (
 )
 
 type Todo struct {
-	ID        string    `json:"id"`
-	Task      string    `json:"task"`
-	Labels    []string  `json:"labels"`
+	ID   string `json:"id"`
+	Task string `json:"task"`
+	// Notes is free-form Markdown text giving more context than Task.
+	Notes  string   `json:"notes,omitempty"`
+	Links  []Link   `json:"links,omitempty"`
+	Labels []string `json:"labels"`
+	// Completed is derived from Status: it is true when the status is a
+	// done status of the file's workflow. It is stored as well, so readers
+	// of the file can tell open from done todos without the workflow.
 	Completed bool      `json:"completed"`
+	Status    Status    `json:"status"`
 	DueDate   time.Time `json:"due_date"`
 	Priority  Priority  `json:"priority"`
+	// Estimate is the expected size of the todo, if any.
+	Estimate  *Estimate `json:"estimate,omitempty"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
+	// CompletedAt is when the todo was last marked as completed; it is zero
+	// for open todos.
+	CompletedAt time.Time `json:"completed_at"`
+	// TimeEntries holds the time logged on the todo, oldest first.
+	TimeEntries []TimeEntry `json:"time_entries,omitempty"`
+	// FocusSessions holds the focus sessions spent on the todo, oldest
+	// first.
+	FocusSessions []FocusSession `json:"focus_sessions,omitempty"`
 }
 
 func (p Priority) Validate() error {
@@ -56,3 +73,49 @@ func (t *Todo) Validate() error {
 func (p Priority) Normalize() Priority {
 	return Priority(strings.ToUpper(string(p)))
 }
+
+// Rank orders priorities from LOW (1) to HIGH (3); unknown priorities rank 0.
+func (p Priority) Rank() int {
+	switch p.Normalize() {
+	case High:
+		return 3
+	case Medium:
+		return 2
+	case Low:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// HasDueDate reports whether the todo has a due date set.
+func (t Todo) HasDueDate() bool {
+	return !t.DueDate.IsZero()
+}
+
+// Deadline returns the moment the todo becomes overdue, which is the end of
+// its due day in the local time zone. It returns the zero time when no due
+// date is set.
+func (t Todo) Deadline() time.Time {
+	if !t.HasDueDate() {
+		return time.Time{}
+	}
+	due := t.DueDate
+	return time.Date(due.Year(), due.Month(), due.Day()+1, 0, 0, 0, 0, time.Local)
+}
+
+// DayOf returns the local calendar day of the moment t in the form due dates
+// are stored in, midnight UTC, so that it can be compared with them. Due
+// dates are plain calendar days; "today" is the day on the user's clock.
+func DayOf(t time.Time) time.Time {
+	t = t.In(time.Local)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// IsOverdue reports whether an open todo has passed its deadline at now.
+func (t Todo) IsOverdue(now time.Time) bool {
+	if t.Completed || !t.HasDueDate() {
+		return false
+	}
+	return !now.Before(t.Deadline())
+}
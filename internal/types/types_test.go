@@ -0,0 +1,46 @@
+package types
+
+import (
+	"testing"
+	"time"
+)
+
+// inZone runs the rest of a test with the local time zone set to loc.
+func inZone(t *testing.T, loc *time.Location) {
+	t.Helper()
+	saved := time.Local
+	time.Local = loc
+	t.Cleanup(func() { time.Local = saved })
+}
+
+func TestDueDaysEndAtLocalMidnight(t *testing.T) {
+	inZone(t, time.FixedZone("UTC-5", -5*60*60))
+	todo := Todo{DueDate: time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC)}
+
+	tests := []struct {
+		name    string
+		now     time.Time
+		overdue bool
+		today   string
+	}{
+		{name: "morning", now: time.Date(2026, 3, 5, 8, 0, 0, 0, time.Local), today: "2026-03-05"},
+		{name: "evening, next day in UTC", now: time.Date(2026, 3, 5, 21, 0, 0, 0, time.Local), today: "2026-03-05"},
+		{name: "the same evening in UTC", now: time.Date(2026, 3, 6, 2, 0, 0, 0, time.UTC), today: "2026-03-05"},
+		{name: "local midnight", now: time.Date(2026, 3, 6, 0, 0, 0, 0, time.Local), overdue: true, today: "2026-03-06"},
+		{name: "day before", now: time.Date(2026, 3, 4, 23, 59, 0, 0, time.Local), today: "2026-03-04"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := todo.IsOverdue(tt.now); got != tt.overdue {
+				t.Errorf("IsOverdue(%v) = %v, want %v", tt.now, got, tt.overdue)
+			}
+			if got := DayOf(tt.now).Format("2006-01-02"); got != tt.today {
+				t.Errorf("DayOf(%v) = %s, want %s", tt.now, got, tt.today)
+			}
+		})
+	}
+
+	if want := time.Date(2026, 3, 6, 5, 0, 0, 0, time.UTC); !todo.Deadline().Equal(want) {
+		t.Errorf("Deadline() = %v, want %v", todo.Deadline(), want)
+	}
+}
@@ -0,0 +1,227 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Ng1n3/go-todo/internal/errors"
+)
+
+// Status is a step of a Workflow, e.g. "in_progress".
+type Status string
+
+// Statuses of the default workflow.
+const (
+	StatusTodo       Status = "todo"
+	StatusInProgress Status = "in_progress"
+	StatusBlocked    Status = "blocked"
+	StatusInReview   Status = "in_review"
+	StatusDone       Status = "done"
+)
+
+// ParseStatus normalizes user input such as "In Progress" or "in-review" to
+// a Status.
+func ParseStatus(input string) Status {
+	s := strings.ToLower(strings.TrimSpace(input))
+	s = strings.NewReplacer(" ", "_", "-", "_").Replace(s)
+	return Status(s)
+}
+
+// Label returns the status for display, e.g. "In progress".
+func (s Status) Label() string {
+	label := strings.ReplaceAll(string(s), "_", " ")
+	if label == "" {
+		return label
+	}
+	return strings.ToUpper(label[:1]) + label[1:]
+}
+
+// Workflow lists the statuses a todo can be in and the transitions allowed
+// between them. A todo is completed when its status is one of Done.
+type Workflow struct {
+	// Statuses are in board order.
+	Statuses    []Status            `json:"statuses"`
+	Initial     Status              `json:"initial"`
+	Done        []Status            `json:"done"`
+	Transitions map[Status][]Status `json:"transitions"`
+}
+
+// DefaultWorkflow is used by files that don't define their own:
+// todo -> in progress -> in review -> done, with blocked on the side.
+// Todos can be completed straight from todo and reopened from done.
+func DefaultWorkflow() *Workflow {
+	return &Workflow{
+		Statuses: []Status{StatusTodo, StatusInProgress, StatusBlocked, StatusInReview, StatusDone},
+		Initial:  StatusTodo,
+		Done:     []Status{StatusDone},
+		Transitions: map[Status][]Status{
+			StatusTodo:       {StatusInProgress, StatusBlocked, StatusDone},
+			StatusInProgress: {StatusTodo, StatusBlocked, StatusInReview, StatusDone},
+			StatusBlocked:    {StatusTodo, StatusInProgress},
+			StatusInReview:   {StatusInProgress, StatusDone},
+			StatusDone:       {StatusTodo},
+		},
+	}
+}
+
+func (w *Workflow) Validate() error {
+	if len(w.Statuses) == 0 {
+		return fmt.Errorf("%w: no statuses", errors.ErrInvalidWorkflow)
+	}
+
+	seen := make(map[Status]bool, len(w.Statuses))
+	for _, s := range w.Statuses {
+		if s == "" || s != ParseStatus(string(s)) {
+			return fmt.Errorf("%w: invalid status name %q, use lower case and underscores", errors.ErrInvalidWorkflow, s)
+		}
+		if seen[s] {
+			return fmt.Errorf("%w: status %q is listed twice", errors.ErrInvalidWorkflow, s)
+		}
+		seen[s] = true
+	}
+
+	if !w.Has(w.Initial) {
+		return fmt.Errorf("%w: initial status %q is not one of the statuses", errors.ErrInvalidWorkflow, w.Initial)
+	}
+	if w.IsDone(w.Initial) {
+		return fmt.Errorf("%w: initial status %q can't be a done status", errors.ErrInvalidWorkflow, w.Initial)
+	}
+	if len(w.Done) == 0 {
+		return fmt.Errorf("%w: at least one status must be a done status", errors.ErrInvalidWorkflow)
+	}
+	for _, s := range w.Done {
+		if !w.Has(s) {
+			return fmt.Errorf("%w: done status %q is not one of the statuses", errors.ErrInvalidWorkflow, s)
+		}
+	}
+
+	for from, targets := range w.Transitions {
+		if !w.Has(from) {
+			return fmt.Errorf("%w: transition from unknown status %q", errors.ErrInvalidWorkflow, from)
+		}
+		for _, to := range targets {
+			if !w.Has(to) {
+				return fmt.Errorf("%w: transition from %q to unknown status %q", errors.ErrInvalidWorkflow, from, to)
+			}
+		}
+	}
+	return nil
+}
+
+// Has reports whether s is a status of the workflow.
+func (w *Workflow) Has(s Status) bool {
+	return containsStatus(w.Statuses, s)
+}
+
+// IsDone reports whether todos in status s count as completed.
+func (w *Workflow) IsDone(s Status) bool {
+	return containsStatus(w.Done, s)
+}
+
+// DoneStatus is the status a todo moves to when it is marked completed.
+func (w *Workflow) DoneStatus() Status {
+	return w.Done[0]
+}
+
+// Next returns the statuses a todo in status from may move to: all of them
+// when from is not a status of the workflow.
+func (w *Workflow) Next(from Status) []Status {
+	if !w.Has(from) {
+		return w.Statuses
+	}
+	return w.Transitions[from]
+}
+
+// CanTransition reports whether a todo may move from one status to another.
+// Staying in the same status is always allowed, and so is leaving a status
+// the workflow doesn't have, e.g. after the file was edited by hand.
+func (w *Workflow) CanTransition(from, to Status) bool {
+	return from == to || !w.Has(from) || containsStatus(w.Transitions[from], to)
+}
+
+// Transition moves todo to status to, enforcing the workflow, and keeps
+// Completed and CompletedAt in step with it.
+func (w *Workflow) Transition(todo *Todo, to Status, now time.Time) error {
+	if !w.Has(to) {
+		return fmt.Errorf("%w: unknown status %q, use one of %s", errors.ErrInvalidTransition, to, joinStatuses(w.Statuses))
+	}
+	if !w.CanTransition(todo.Status, to) {
+		next := "none"
+		if len(w.Next(todo.Status)) > 0 {
+			next = joinStatuses(w.Next(todo.Status))
+		}
+		return fmt.Errorf("%w: %s -> %s (allowed: %s)", errors.ErrInvalidTransition, todo.Status, to, next)
+	}
+
+	w.apply(todo, to, now)
+	return nil
+}
+
+// Complete moves todo to a done status when completed is true, or back to
+// the initial status when it is false and the todo is done. It is the
+// workflow-aware way to set Completed and enforces the workflow like
+// Transition: a todo is completed into the first done status it may move
+// to, and fails with ErrInvalidTransition if there is none, e.g. when it is
+// blocked.
+func (w *Workflow) Complete(todo *Todo, completed bool, now time.Time) error {
+	switch {
+	case completed && !w.IsDone(todo.Status):
+		to := w.DoneStatus()
+		for _, s := range w.Done {
+			if w.CanTransition(todo.Status, s) {
+				to = s
+				break
+			}
+		}
+		return w.Transition(todo, to, now)
+	case !completed && w.IsDone(todo.Status):
+		return w.Transition(todo, w.Initial, now)
+	}
+	return nil
+}
+
+// Adopt fits a todo coming from another file, or a new todo, into the
+// workflow without enforcing transitions: an unknown status becomes the done
+// status for completed todos and the initial status otherwise.
+func (w *Workflow) Adopt(todo *Todo, now time.Time) {
+	if w.Has(todo.Status) {
+		todo.Completed = w.IsDone(todo.Status)
+		return
+	}
+
+	to := w.Initial
+	if todo.Completed {
+		to = w.DoneStatus()
+	}
+	w.apply(todo, to, now)
+}
+
+func (w *Workflow) apply(todo *Todo, to Status, now time.Time) {
+	done := w.IsDone(to)
+	switch {
+	case done && (!todo.Completed || todo.CompletedAt.IsZero()):
+		todo.CompletedAt = now
+	case !done:
+		todo.CompletedAt = time.Time{}
+	}
+	todo.Status = to
+	todo.Completed = done
+}
+
+func containsStatus(statuses []Status, s Status) bool {
+	for _, candidate := range statuses {
+		if candidate == s {
+			return true
+		}
+	}
+	return false
+}
+
+func joinStatuses(statuses []Status) string {
+	names := make([]string, len(statuses))
+	for i, s := range statuses {
+		names[i] = string(s)
+	}
+	return strings.Join(names, ", ")
+}
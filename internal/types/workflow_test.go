@@ -0,0 +1,122 @@
+package types
+
+import (
+	stderrors "errors"
+	"testing"
+	"time"
+
+	"github.com/Ng1n3/go-todo/internal/errors"
+)
+
+func TestCompleteEnforcesWorkflow(t *testing.T) {
+	now := time.Date(2026, 3, 5, 12, 0, 0, 0, time.UTC)
+	w := DefaultWorkflow()
+
+	tests := []struct {
+		from    Status
+		wantErr bool
+	}{
+		{from: StatusTodo},
+		{from: StatusInProgress},
+		{from: StatusInReview},
+		{from: StatusDone},
+		{from: "shipped"},
+		{from: StatusBlocked, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.from), func(t *testing.T) {
+			todo := Todo{Status: tt.from}
+			if w.IsDone(tt.from) {
+				todo.Completed, todo.CompletedAt = true, now
+			}
+			err := w.Complete(&todo, true, now)
+			if tt.wantErr {
+				if !stderrors.Is(err, errors.ErrInvalidTransition) {
+					t.Fatalf("Complete(true) = %v, want ErrInvalidTransition", err)
+				}
+				if todo.Status != tt.from || todo.Completed {
+					t.Errorf("rejected Complete(true) changed the todo to %s, completed %v", todo.Status, todo.Completed)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Complete(true): %v", err)
+			}
+			if todo.Status != StatusDone || !todo.Completed || todo.CompletedAt.IsZero() {
+				t.Errorf("after Complete(true): status %s, completed %v, completed at %v", todo.Status, todo.Completed, todo.CompletedAt)
+			}
+
+			if err := w.Complete(&todo, false, now); err != nil {
+				t.Fatalf("Complete(false): %v", err)
+			}
+			if todo.Status != StatusTodo || todo.Completed || !todo.CompletedAt.IsZero() {
+				t.Errorf("after Complete(false): status %s, completed %v, completed at %v", todo.Status, todo.Completed, todo.CompletedAt)
+			}
+		})
+	}
+}
+
+func TestCompletePicksReachableDoneStatus(t *testing.T) {
+	now := time.Date(2026, 3, 5, 12, 0, 0, 0, time.UTC)
+	w := &Workflow{
+		Statuses: []Status{"todo", "doing", "shipped", "dropped"},
+		Initial:  "todo",
+		Done:     []Status{"shipped", "dropped"},
+		Transitions: map[Status][]Status{
+			"todo":    {"doing", "dropped"},
+			"doing":   {"shipped"},
+			"dropped": {"todo"},
+		},
+	}
+
+	todo := Todo{Status: "todo"}
+	if err := w.Complete(&todo, true, now); err != nil {
+		t.Fatal(err)
+	}
+	if todo.Status != "dropped" {
+		t.Errorf("completed todo is %s, want dropped, the done status it may move to", todo.Status)
+	}
+
+	shipped := Todo{Status: "shipped", Completed: true, CompletedAt: now}
+	if err := w.Complete(&shipped, false, now); !stderrors.Is(err, errors.ErrInvalidTransition) {
+		t.Errorf("reopening without a transition back = %v, want ErrInvalidTransition", err)
+	}
+}
+
+func TestTransitionEnforcesWorkflow(t *testing.T) {
+	now := time.Date(2026, 3, 5, 12, 0, 0, 0, time.UTC)
+	w := DefaultWorkflow()
+
+	tests := []struct {
+		from, to Status
+		wantErr  bool
+	}{
+		{from: StatusTodo, to: StatusInProgress},
+		{from: StatusInProgress, to: StatusInReview},
+		{from: StatusBlocked, to: StatusBlocked},
+		{from: StatusBlocked, to: StatusDone, wantErr: true},
+		{from: StatusTodo, to: StatusInReview, wantErr: true},
+		{from: StatusTodo, to: "shipped", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.from)+"->"+string(tt.to), func(t *testing.T) {
+			todo := Todo{Status: tt.from}
+			err := w.Transition(&todo, tt.to, now)
+			if tt.wantErr {
+				if !stderrors.Is(err, errors.ErrInvalidTransition) {
+					t.Fatalf("Transition = %v, want ErrInvalidTransition", err)
+				}
+				if todo.Status != tt.from {
+					t.Errorf("status changed to %s by a rejected transition", todo.Status)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if todo.Status != tt.to {
+				t.Errorf("status = %s, want %s", todo.Status, tt.to)
+			}
+		})
+	}
+}
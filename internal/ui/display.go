@@ -2,58 +2,91 @@ package ui
 
 import (
 	"fmt"
+	"io"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/Ng1n3/go-todo/internal/config"
+	"github.com/Ng1n3/go-todo/internal/focus"
+	"github.com/Ng1n3/go-todo/internal/search"
+	"github.com/Ng1n3/go-todo/internal/service"
+	"github.com/Ng1n3/go-todo/internal/stats"
+	"github.com/Ng1n3/go-todo/internal/store"
+	"github.com/Ng1n3/go-todo/internal/summary"
+	"github.com/Ng1n3/go-todo/internal/timesheet"
 	"github.com/Ng1n3/go-todo/internal/types"
+	"github.com/Ng1n3/go-todo/internal/urgency"
+	"github.com/fatih/color"
 	"github.com/olekukonko/tablewriter"
+	"github.com/olekukonko/tablewriter/tw"
 )
 
-type Display struct{}
+type Display struct {
+	// renderer draws todo listings.
+	renderer Renderer
+	// messages receives errors and status messages. It is stderr when
+	// listings are machine-readable, so they can be piped as they are.
+	messages io.Writer
+}
 
 func NewDisplay() *Display {
-	return &Display{}
+	return &Display{renderer: TableRenderer{}, messages: os.Stdout}
 }
 
-func (d *Display) ShowTodos(todos []types.Todo) {
-	if len(todos) == 0 {
-		fmt.Println("No todos found.")
-		return
+// SetRenderer makes todo listings use r. With anything but a table,
+// messages go to stderr to keep stdout parseable.
+func (d *Display) SetRenderer(r Renderer) {
+	d.renderer = r
+	d.messages = os.Stdout
+	if _, ok := r.(TableRenderer); !ok {
+		d.messages = os.Stderr
 	}
+}
 
-	table := tablewriter.NewWriter(os.Stdout)
-	table.Header([]string{"ID", "Task", "Due Date", "Priority", "Completed", "Labels", "Created", "Updated"})
+func (d *Display) ShowTodos(todos []types.Todo) {
+	if err := d.ShowFileTodos([]service.FileTodos{{Todos: todos}}); err != nil {
+		d.ShowError(err)
+	}
+}
 
-	for _, todo := range todos {
-		labels := strings.Join(todo.Labels, ", ")
-		completed := "No"
-		if todo.Completed {
-			completed = "Yes"
-		}
+// ShowFileTodos lists the todos of one or more files with the display's
+// renderer. The error is returned rather than shown, so that commands can
+// fail when the listing does.
+func (d *Display) ShowFileTodos(files []service.FileTodos) error {
+	return d.renderer.RenderTodos(os.Stdout, files)
+}
 
-		table.Append([]string{
-			todo.ID,
-			todo.Task,
-			todo.DueDate.Format("2006-01-02"),
-			string(todo.Priority),
-			completed,
-			labels,
-			todo.CreatedAt.Format("2006-01-02"),
-			todo.UpdatedAt.Format("2006-01-02"),
-		})
+func formatEstimate(estimate *types.Estimate) string {
+	if estimate == nil {
+		return "-"
 	}
+	return estimate.String()
+}
 
-	table.Render()
+// formatEstimateTotal sums the estimates of todos, e.g. "5h30m + 8pt", or
+// returns "" if none has an estimate.
+func formatEstimateTotal(todos []types.Todo) string {
+	minutes, points := types.SumEstimates(todos)
+	var parts []string
+	if minutes > 0 {
+		parts = append(parts, types.FormatMinutes(minutes))
+	}
+	if points > 0 {
+		parts = append(parts, types.Estimate{Points: points}.String())
+	}
+	return strings.Join(parts, " + ")
 }
 
 func (d *Display) ShowTodo(todo types.Todo) {
 	table := tablewriter.NewWriter(os.Stdout)
-	table.Header([]string{"ID", "Task", "Due Date", "Priority", "Completed", "Labels", "Created", "Updated"})
+	table.Header([]string{"ID", "Task", "Due Date", "Priority", "Status", "Labels", "Estimate", "Tracked", "Created", "Updated"})
 
 	labels := strings.Join(todo.Labels, ", ")
-	completed := "No"
-	if todo.Completed {
-		completed = "Yes"
+	tracked := "-"
+	if len(todo.TimeEntries) > 0 {
+		tracked = formatClock(todo.Tracked())
 	}
 
 	table.Append([]string{
@@ -61,8 +94,10 @@ func (d *Display) ShowTodo(todo types.Todo) {
 		todo.Task,
 		todo.DueDate.Format("2006-01-02"),
 		string(todo.Priority),
-		completed,
+		todo.Status.Label(),
 		labels,
+		formatEstimate(todo.Estimate),
+		tracked,
 		todo.CreatedAt.Format("2006-01-02"),
 		todo.UpdatedAt.Format("2006-01-02"),
 	})
@@ -71,6 +106,43 @@ func (d *Display) ShowTodo(todo types.Todo) {
 
 }
 
+// ShowTodoDetail shows a todo with its numbered links and its notes rendered
+// as Markdown.
+func (d *Display) ShowTodoDetail(todo types.Todo) {
+	d.ShowTodo(todo)
+
+	if len(todo.Links) > 0 {
+		fmt.Println("\nLinks:")
+		table := tablewriter.NewWriter(os.Stdout)
+		table.Header([]string{"#", "Kind", "Target", "Title"})
+		for i, link := range todo.Links {
+			table.Append([]string{strconv.Itoa(i + 1), string(link.Kind), link.Target, link.Title})
+		}
+		table.Render()
+	}
+
+	if todo.Notes == "" {
+		fmt.Println("\nNo notes.")
+		return
+	}
+	fmt.Println("\nNotes:")
+	fmt.Println(RenderMarkdown(todo.Notes))
+}
+
+func (d *Display) ShowBulkChanges(changes []service.BulkChange) {
+	if len(changes) == 0 {
+		fmt.Println("No todos would change.")
+		return
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.Header([]string{"ID", "Task", "Change"})
+	for _, change := range changes {
+		table.Append([]string{change.Before.ID, change.Before.Task, change.Description()})
+	}
+	table.Render()
+}
+
 func (d *Display) ShowFiles(files []os.FileInfo, storageDir string) {
 	if len(files) == 0 {
 		fmt.Println("No todos files found.")
@@ -93,13 +165,704 @@ func (d *Display) ShowFiles(files []os.FileInfo, storageDir string) {
 }
 
 func (d *Display) ShowError(err error) {
-	fmt.Printf("Error: %v\n", err)
+	fmt.Fprintf(d.messages, "Error: %v\n", err)
 }
 
 func (d *Display) ShowSuccess(message string) {
-	fmt.Printf("✓ %s\n", message)
+	fmt.Fprintf(d.messages, "✓ %s\n", message)
 }
 
 func (d *Display) ShowInfo(message string) {
-	fmt.Printf("i %s\n", message)
+	fmt.Fprintf(d.messages, "i %s\n", message)
+}
+
+func (d *Display) ShowSearchResults(hits []search.Hit) {
+	if len(hits) == 0 {
+		fmt.Println("No matching todos found.")
+		return
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.Header([]string{"Score", "File", "ID", "Task", "Labels", "Due Date", "Completed"})
+
+	for _, hit := range hits {
+		completed := "No"
+		if hit.Todo.Completed {
+			completed = "Yes"
+		}
+
+		table.Append([]string{
+			fmt.Sprintf("%.2f", hit.Score),
+			hit.File,
+			hit.Todo.ID,
+			hit.Todo.Task,
+			strings.Join(hit.Todo.Labels, ", "),
+			hit.Todo.DueDate.Format("2006-01-02"),
+			completed,
+		})
+	}
+
+	table.Render()
+}
+
+func (d *Display) ShowSettings(settings []config.Setting) {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.Header([]string{"Setting", "Value", "Source", "Env", "Flag"})
+
+	for _, s := range settings {
+		table.Append([]string{s.Key, s.Value, s.Source, s.Env, "--" + s.Flag})
+	}
+
+	table.Render()
+}
+
+func (d *Display) ShowDashboard(idx *summary.Index, now time.Time) {
+	names := idx.Names()
+	if len(names) == 0 {
+		fmt.Println("No todos files found.")
+		return
+	}
+
+	row := func(name string, fs *summary.FileSummary) []string {
+		next := "-"
+		if due := fs.NextDue(now); !due.IsZero() {
+			next = due.Format("2006-01-02")
+		}
+		labels := strings.Join(fs.Labels, ", ")
+		if fs.Encrypted {
+			labels = "(encrypted)"
+		}
+		return []string{
+			name,
+			strconv.Itoa(fs.Open),
+			strconv.Itoa(fs.Done),
+			strconv.Itoa(fs.Overdue(now)),
+			next,
+			labels,
+		}
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.Header([]string{"File", "Open", "Done", "Overdue", "Next Due", "Labels"})
+
+	for _, name := range names {
+		table.Append(row(name, idx.Files[name]))
+	}
+
+	sum := idx.Totals()
+	totals := row("Total", sum)
+	totals[5] = fmt.Sprintf("%d labels", len(sum.Labels))
+	table.Footer(totals)
+
+	table.Render()
+}
+
+// ShowTransfers reports the result of moving or copying todos between files.
+func (d *Display) ShowTransfers(verb, from, to string, results []service.TransferResult) {
+	done := 0
+	for _, r := range results {
+		switch {
+		case r.Skipped:
+			d.ShowInfo(fmt.Sprintf("Skipped %s: ID already exists in %s", r.OldID, to))
+		case r.OldID != r.Todo.ID:
+			d.ShowInfo(fmt.Sprintf("%s already exists in %s, renamed to %s", r.OldID, to, r.Todo.ID))
+			done++
+		default:
+			done++
+		}
+	}
+
+	past := map[string]string{"move": "Moved", "copy": "Copied"}[verb]
+	d.ShowSuccess(fmt.Sprintf("%s %d todo(s) from %s to %s", past, done, from, to))
+}
+
+func (d *Display) ShowTrash(items []store.TrashItem, retention time.Duration) {
+	if len(items) == 0 {
+		fmt.Println("The trash is empty.")
+		return
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.Header([]string{"ID", "Kind", "Original", "Todo", "Deleted", "Purged"})
+
+	for _, item := range items {
+		todo := "-"
+		if item.Kind == store.TrashTodo {
+			todo = item.TodoID
+			if item.Task != "" {
+				todo += " " + item.Task
+			} else if item.Encrypted {
+				todo += " (encrypted)"
+			}
+		}
+
+		purged := "never"
+		if expires := item.ExpiresAt(retention); !expires.IsZero() {
+			purged = expires.Format("2006-01-02")
+		}
+
+		table.Append([]string{
+			item.ID,
+			string(item.Kind),
+			item.File,
+			todo,
+			item.DeletedAt.Format("2006-01-02 15:04"),
+			purged,
+		})
+	}
+	table.Render()
+}
+
+func (d *Display) ShowStats(r *stats.Report) {
+	fmt.Printf("\n%s to %s, %d file(s)\n", r.From.Format("2006-01-02"), r.To.Format("2006-01-02"), len(r.Files))
+
+	avg := "-"
+	if r.Completed > 0 {
+		avg = formatSpan(time.Duration(r.AvgCompletionHours * float64(time.Hour)))
+	}
+	overdue := "-"
+	if r.Due > 0 {
+		overdue = fmt.Sprintf("%.0f%% (%d of %d)", r.OverdueRate*100, r.Late, r.Due)
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.Header([]string{"Created", "Completed", "Open now", "Avg time to complete", "Overdue rate"})
+	table.Append([]string{strconv.Itoa(r.Created), strconv.Itoa(r.Completed), strconv.Itoa(r.Open), avg, overdue})
+	table.Render()
+
+	fmt.Println("\nPer week")
+	table = tablewriter.NewWriter(os.Stdout)
+	table.Header([]string{"Week of", "Created", "Completed"})
+	for _, w := range r.Weeks {
+		table.Append([]string{w.Start.Format("2006-01-02"), strconv.Itoa(w.Created), strconv.Itoa(w.Completed)})
+	}
+	table.Render()
+
+	breakdown := func(title, column string, rows []stats.Breakdown) {
+		fmt.Printf("\n%s\n", title)
+		if len(rows) == 0 {
+			fmt.Println("No todos created in this period.")
+			return
+		}
+		table := tablewriter.NewWriter(os.Stdout)
+		table.Header([]string{column, "Created", "Completed", "Open", "Overdue"})
+		for _, b := range rows {
+			table.Append([]string{b.Name, strconv.Itoa(b.Created), strconv.Itoa(b.Completed), strconv.Itoa(b.Open), strconv.Itoa(b.Overdue)})
+		}
+		table.Render()
+	}
+	breakdown("By priority", "Priority", r.ByPriority)
+	breakdown("By label", "Label", r.ByLabel)
+
+	fmt.Println("\nOldest open todos")
+	if len(r.OldestOpen) == 0 {
+		fmt.Println("No open todos.")
+		return
+	}
+	table = tablewriter.NewWriter(os.Stdout)
+	table.Header([]string{"File", "ID", "Task", "Created", "Age (days)"})
+	for _, t := range r.OldestOpen {
+		table.Append([]string{t.File, t.ID, t.Task, t.CreatedAt.Format("2006-01-02"), strconv.Itoa(t.AgeDays)})
+	}
+	table.Render()
+}
+
+// formatSpan renders a duration in whole days and hours, e.g. "3d 4h".
+func formatSpan(d time.Duration) string {
+	hours := int(d.Round(time.Hour).Hours())
+	if hours < 24 {
+		return fmt.Sprintf("%dh", hours)
+	}
+	return fmt.Sprintf("%dd %dh", hours/24, hours%24)
+}
+
+// formatClock renders a duration in hours and minutes, e.g. "2h05m".
+func formatClock(d time.Duration) string {
+	minutes := int(d.Round(time.Minute).Minutes())
+	return fmt.Sprintf("%dh%02dm", minutes/60, minutes%60)
+}
+
+var priorityColors = map[types.Priority][]color.Attribute{
+	types.High:   {color.FgRed, color.Bold},
+	types.Medium: {color.FgYellow},
+	types.Low:    {color.FgGreen},
+}
+
+// ShowAgenda prints each non-empty agenda group as a table.
+func (d *Display) ShowAgenda(groups []service.AgendaGroup) {
+	empty := true
+	for _, group := range groups {
+		if len(group.Entries) == 0 {
+			continue
+		}
+		empty = false
+
+		heading := color.New(color.Bold)
+		if group.Name == service.GroupOverdue {
+			heading = color.New(color.FgRed, color.Bold)
+		}
+		heading.Printf("\n%s (%d)\n", group.Name, len(group.Entries))
+
+		table := tablewriter.NewWriter(os.Stdout)
+		table.Header([]string{"File", "ID", "Task", "Due Date", "Priority", "Labels"})
+		for _, entry := range group.Entries {
+			due := "-"
+			if entry.Todo.HasDueDate() {
+				due = entry.Todo.DueDate.Format("Mon 2006-01-02")
+			}
+			table.Append([]string{
+				entry.File,
+				entry.Todo.ID,
+				entry.Todo.Task,
+				due,
+				string(entry.Todo.Priority),
+				strings.Join(entry.Todo.Labels, ", "),
+			})
+		}
+		table.Render()
+	}
+
+	if empty {
+		fmt.Println("Nothing on the agenda.")
+	}
+}
+
+// ShowCalendar prints a month grid in which days with due todos are marked
+// with * and coloured by their highest priority, followed by the todos of
+// the month.
+func (d *Display) ShowCalendar(days []service.CalendarDay, now time.Time) {
+	if len(days) == 0 {
+		return
+	}
+	first := days[0].Date
+	today := types.DayOf(now)
+
+	title := first.Format("January 2006")
+	fmt.Printf("\n%*s\n", 14+len(title)/2, title)
+	fmt.Println(" Mo  Tu  We  Th  Fr  Sa  Su")
+
+	fmt.Print(strings.Repeat("    ", (int(first.Weekday())+6)%7))
+	for _, day := range days {
+		cell := fmt.Sprintf("%3d", day.Date.Day())
+		mark := " "
+		if len(day.Entries) > 0 {
+			mark = "*"
+		}
+
+		c := color.New(priorityColors[day.Highest]...)
+		if day.Date.Equal(today) {
+			c.Add(color.ReverseVideo)
+		}
+		c.Print(cell + mark)
+
+		if day.Date.Weekday() == time.Sunday {
+			fmt.Println()
+		}
+	}
+	if days[len(days)-1].Date.Weekday() != time.Sunday {
+		fmt.Println()
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.Header([]string{"Day", "File", "ID", "Task", "Priority"})
+	rows := 0
+	for _, day := range days {
+		for _, entry := range day.Entries {
+			table.Append([]string{
+				day.Date.Format("Mon 02"),
+				entry.File,
+				entry.Todo.ID,
+				entry.Todo.Task,
+				string(entry.Todo.Priority),
+			})
+			rows++
+		}
+	}
+
+	fmt.Println()
+	if rows == 0 {
+		fmt.Println("No todos due this month.")
+		return
+	}
+	table.Render()
+}
+
+// ShowBoard renders todos as a kanban board with one column per status of
+// the workflow, in workflow order.
+func (d *Display) ShowBoard(workflow *types.Workflow, todos []types.Todo) {
+	// Todos whose status isn't in the workflow, e.g. after it was changed,
+	// go to a last column instead of disappearing from the board.
+	const other types.Status = "\x00other"
+	statuses := workflow.Statuses
+	columns := make(map[types.Status][]types.Todo, len(statuses)+1)
+	rows := 0
+	for _, todo := range todos {
+		status := todo.Status
+		if !workflow.Has(status) {
+			status = other
+		}
+		columns[status] = append(columns[status], todo)
+		if n := len(columns[status]); n > rows {
+			rows = n
+		}
+	}
+	if len(columns[other]) > 0 {
+		statuses = append(statuses[:len(statuses):len(statuses)], other)
+	}
+
+	header := make([]string, len(statuses))
+	for i, status := range statuses {
+		label := status.Label()
+		if status == other {
+			label = "Other"
+		}
+		header[i] = fmt.Sprintf("%s (%d)", label, len(columns[status]))
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.Header(header)
+	for row := 0; row < rows; row++ {
+		cells := make([]string, len(statuses))
+		for i, status := range statuses {
+			if row < len(columns[status]) {
+				todo := columns[status][row]
+				cells[i] = fmt.Sprintf("%s %s", todo.ID, todo.Task)
+				if status == other {
+					cells[i] += fmt.Sprintf(" [%s]", todo.Status)
+				}
+			}
+		}
+		table.Append(cells)
+	}
+
+	totals := make([]string, len(statuses))
+	estimated := false
+	for i, status := range statuses {
+		totals[i] = formatEstimateTotal(columns[status])
+		estimated = estimated || totals[i] != ""
+	}
+	if estimated {
+		table.Footer(totals)
+	}
+	table.Render()
+}
+
+func (d *Display) ShowWorkflow(workflow *types.Workflow) {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.Header([]string{"Status", "Initial", "Done", "Can move to"})
+	for _, status := range workflow.Statuses {
+		initial, done := "", ""
+		if status == workflow.Initial {
+			initial = "yes"
+		}
+		if workflow.IsDone(status) {
+			done = "yes"
+		}
+
+		next := make([]string, 0, len(workflow.Next(status)))
+		for _, to := range workflow.Next(status) {
+			next = append(next, string(to))
+		}
+		table.Append([]string{string(status), initial, done, strings.Join(next, ", ")})
+	}
+	table.Render()
+}
+
+// ShowTimer prints the running timer, or that none is running.
+func (d *Display) ShowTimer(timer *service.Timer, now time.Time) {
+	if timer == nil {
+		fmt.Println("No timer is running.")
+		return
+	}
+
+	task := timer.Task
+	if task == "" {
+		task = "(encrypted)"
+	}
+	table := tablewriter.NewWriter(os.Stdout)
+	table.Header([]string{"File", "ID", "Task", "Started", "Elapsed"})
+	table.Append([]string{
+		timer.File,
+		timer.TodoID,
+		task,
+		timer.Start.Format("2006-01-02 15:04"),
+		formatClock(timer.Elapsed(now)),
+	})
+	table.Render()
+}
+
+func (d *Display) ShowTimesheet(r *timesheet.Report) {
+	fmt.Printf("\n%s to %s, by %s\n", r.From.Format("2006-01-02"), r.To.Format("2006-01-02"), r.By)
+	if len(r.Rows) == 0 {
+		fmt.Println("No time logged in this period.")
+		return
+	}
+
+	header := []string{strings.ToUpper(string(r.By)[:1]) + string(r.By)[1:], "Entries", "Time", "Hours"}
+	if r.By == timesheet.ByTodo {
+		header = []string{"File", "ID", "Task", "Entries", "Time", "Hours"}
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.Header(header)
+	for _, row := range r.Rows {
+		cells := []string{row.Name, strconv.Itoa(row.Entries), formatClock(row.Duration), timesheet.Hours(row.Duration)}
+		if r.By == timesheet.ByTodo {
+			cells = append([]string{row.File, row.TodoID}, cells...)
+		}
+		table.Append(cells)
+	}
+
+	footer := []string{"Total", strconv.Itoa(r.Entries), formatClock(r.Total), timesheet.Hours(r.Total)}
+	if r.By == timesheet.ByTodo {
+		footer = append([]string{"", ""}, footer...)
+	}
+	table.Footer(footer)
+	table.Render()
+}
+
+// ShowFocus prints the focus session in progress, or that there is none.
+func (d *Display) ShowFocus(f *service.Focus, now time.Time) {
+	if f == nil {
+		fmt.Println("No focus session in progress.")
+		return
+	}
+
+	task := f.Task
+	if task == "" {
+		task = "(encrypted)"
+	}
+	rounds := strconv.Itoa(f.Done)
+	if f.Rounds > 0 {
+		rounds += " of " + strconv.Itoa(f.Rounds)
+	}
+	left := "over"
+	if remaining := f.Remaining(now); remaining > 0 {
+		left = formatClock(remaining)
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.Header([]string{"File", "ID", "Task", "Phase", "Done", "Left"})
+	table.Append([]string{f.File, f.TodoID, task, string(f.Phase), rounds, left})
+	table.Render()
+}
+
+func (d *Display) ShowFocusReport(r *focus.Report) {
+	fmt.Printf("\n%s to %s\n", r.From.Format("2006-01-02"), r.To.Format("2006-01-02"))
+
+	column := "Day"
+	if r.By == focus.Weekly {
+		column = "Week of"
+	}
+	table := tablewriter.NewWriter(os.Stdout)
+	table.Header([]string{column, "Completed", "Interrupted", "Focused"})
+	for _, row := range r.Rows {
+		table.Append([]string{
+			row.Start.Format("Mon 2006-01-02"),
+			strconv.Itoa(row.Completed),
+			strconv.Itoa(row.Interrupted),
+			formatClock(row.Focused),
+		})
+	}
+	table.Footer([]string{"Total", strconv.Itoa(r.Total.Completed), strconv.Itoa(r.Total.Interrupted), formatClock(r.Total.Focused)})
+	table.Render()
+
+	if len(r.Todos) == 0 {
+		return
+	}
+	fmt.Println("\nBy todo")
+	table = tablewriter.NewWriter(os.Stdout)
+	table.Header([]string{"File", "ID", "Task", "Completed", "Interrupted", "Focused"})
+	for _, row := range r.Todos {
+		table.Append([]string{
+			row.File,
+			row.ID,
+			row.Task,
+			strconv.Itoa(row.Completed),
+			strconv.Itoa(row.Interrupted),
+			formatClock(row.Focused),
+		})
+	}
+	table.Render()
+}
+
+func (d *Display) ShowEstimates(r *stats.EstimateReport) {
+	fmt.Printf("\n%s to %s, estimates vs %s time\n", r.From.Format("2006-01-02"), r.To.Format("2006-01-02"), r.Actual)
+	if r.Total.Timed+r.Total.Pointed == 0 {
+		fmt.Println("No completed todos with an estimate in this period.")
+		return
+	}
+
+	hours := func(h float64) string {
+		return formatClock(time.Duration(h * float64(time.Hour)))
+	}
+	cells := func(row stats.EstimateRow) []string {
+		timed := []string{"-", "-", "-", "-"}
+		if row.Timed > 0 {
+			timed = []string{strconv.Itoa(row.Timed), hours(row.EstimatedHours), hours(row.ActualHours), fmt.Sprintf("%.2f", row.Ratio)}
+		}
+		pointed := []string{"-", "-", "-"}
+		if row.Pointed > 0 {
+			pointed = []string{strconv.Itoa(row.Pointed), strconv.FormatFloat(row.Points, 'f', -1, 64), hours(row.HoursPerPoint)}
+		}
+		return append(append([]string{row.Label}, timed...), pointed...)
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.Header([]string{"Label", "Todos", "Estimated", "Actual", "Actual/Est", "Todos (pt)", "Points", "Time per pt"})
+	for _, row := range r.Labels {
+		table.Append(cells(row))
+	}
+	table.Footer(cells(r.Total))
+	table.Render()
+}
+
+func (d *Display) ShowTemplates(templates []types.Template) {
+	if len(templates) == 0 {
+		fmt.Println("No templates found.")
+		return
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.Header([]string{"Name", "Todos", "Variables", "Description"})
+	for _, t := range templates {
+		vars := make([]string, 0, len(t.Variables))
+		for _, v := range t.Placeholders() {
+			vars = append(vars, v.Name)
+		}
+		table.Append([]string{t.Name, strconv.Itoa(len(t.Todos)), strings.Join(vars, ", "), t.Description})
+	}
+	table.Render()
+}
+
+func (d *Display) ShowTemplate(t *types.Template) {
+	fmt.Printf("%s", t.Name)
+	if t.Description != "" {
+		fmt.Printf(": %s", t.Description)
+	}
+	fmt.Println()
+
+	if vars := t.Placeholders(); len(vars) > 0 {
+		table := tablewriter.NewWriter(os.Stdout)
+		table.Header([]string{"Variable", "Prompt", "Default"})
+		for _, v := range vars {
+			table.Append([]string{v.Name, v.Prompt, v.Default})
+		}
+		table.Render()
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.Header([]string{"#", "Task", "Due", "Priority", "Labels", "Estimate", "Checklist"})
+	for i, todo := range t.Todos {
+		due := todo.Due
+		if due == "" {
+			due = "+0d"
+		}
+		priority := string(todo.Priority)
+		if priority == "" {
+			priority = string(types.Low)
+		}
+		checklist := ""
+		if len(todo.Checklist) > 0 {
+			checklist = strconv.Itoa(len(todo.Checklist)) + " items"
+		}
+		table.Append([]string{strconv.Itoa(i + 1), todo.Task, due, priority, strings.Join(todo.Labels, ", "), todo.Estimate, checklist})
+	}
+	table.Render()
+}
+
+func (d *Display) ShowLabels(usage []service.LabelUsage) {
+	if len(usage) == 0 {
+		fmt.Println("No labels found.")
+		return
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.Header([]string{"Label", "Open", "Done", "Files", "Also spelled"})
+	for _, u := range usage {
+		var others []string
+		for _, spelling := range u.Spellings {
+			if spelling != u.Label {
+				others = append(others, spelling)
+			}
+		}
+		table.Append([]string{u.Label, strconv.Itoa(u.Open), strconv.Itoa(u.Done), strings.Join(u.Files, ", "), strings.Join(others, ", ")})
+	}
+	table.Render()
+}
+
+func (d *Display) ShowLabelEdits(edits []service.LabelEdit) {
+	if len(edits) == 0 {
+		fmt.Println("No todos changed.")
+		return
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.Header([]string{"File", "Todos"})
+	for _, edit := range edits {
+		table.Append([]string{edit.File, strconv.Itoa(edit.Todos)})
+	}
+	table.Render()
+}
+
+// ShowLabelTree draws the label hierarchy with the open and done counts of
+// each level, which include the levels below it.
+func (d *Display) ShowLabelTree(roots []*service.LabelNode) {
+	if len(roots) == 0 {
+		fmt.Println("No labels found.")
+		return
+	}
+
+	// Keep the indentation of the tree.
+	table := tablewriter.NewTable(os.Stdout, tablewriter.WithTrimSpace(tw.Off))
+	table.Header([]string{"Label", "Open", "Done"})
+
+	row := func(name string, node *service.LabelNode) {
+		table.Append([]string{name, strconv.Itoa(node.Open), strconv.Itoa(node.Done)})
+	}
+	var walk func(nodes []*service.LabelNode, prefix string)
+	walk = func(nodes []*service.LabelNode, prefix string) {
+		for i, node := range nodes {
+			branch, indent := "├─ ", "│  "
+			if i == len(nodes)-1 {
+				branch, indent = "└─ ", "   "
+			}
+			row(prefix+branch+node.Name, node)
+			walk(node.Children, prefix+indent)
+		}
+	}
+	for _, root := range roots {
+		row(root.Name, root)
+		walk(root.Children, "")
+	}
+	table.Render()
+}
+
+// ShowNext lists todos ranked by urgency with the terms of each score.
+func (d *Display) ShowNext(ranked []urgency.Ranked) {
+	if len(ranked) == 0 {
+		fmt.Println("Nothing to do.")
+		return
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.Header([]string{"#", "File", "ID", "Task", "Due Date", "Priority", "Status", "Urgency", "Because"})
+	for i, r := range ranked {
+		due := "-"
+		if r.Todo.HasDueDate() {
+			due = r.Todo.DueDate.Format("2006-01-02")
+		}
+		table.Append([]string{
+			strconv.Itoa(i + 1),
+			r.File,
+			r.Todo.ID,
+			r.Todo.Task,
+			due,
+			string(r.Todo.Priority),
+			r.Todo.Status.Label(),
+			strconv.FormatFloat(r.Score.Value, 'f', 1, 64),
+			r.Score.Explain(),
+		})
+	}
+	table.Render()
 }
@@ -10,9 +10,14 @@ import (
 	"bufio"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 
+	"golang.org/x/term"
+
+	"github.com/Ng1n3/go-todo/internal/config"
+	"github.com/Ng1n3/go-todo/internal/errors"
 	"github.com/Ng1n3/go-todo/internal/types"
 	"github.com/Ng1n3/go-todo/internal/utils"
 )
@@ -87,6 +92,72 @@ func (ir *InputReader) ReadLabels(prompt string) []string {
 	return utils.ValidateLabels(labelsInput)
 }
 
+// ReadPassword reads a line without echoing it when stdin is a terminal.
+func (ir *InputReader) ReadPassword(prompt string) (string, error) {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) || ir.reader.Buffered() > 0 {
+		return ir.ReadString(prompt)
+	}
+
+	fmt.Print(prompt)
+	password, err := term.ReadPassword(fd)
+	fmt.Println()
+	if err != nil {
+		return "", fmt.Errorf("failed to read input: %w", err)
+	}
+	return string(password), nil
+}
+
+// PassphrasePrompt returns a passphrase source for encrypted todo files.
+// It uses $GO_TODO_PASSPHRASE when set, and otherwise prompts once per file,
+// asking again only after a wrong passphrase.
+func (ir *InputReader) PassphrasePrompt() func(file string, attempt int) (string, error) {
+	known := make(map[string]string)
+	return func(file string, attempt int) (string, error) {
+		if env := os.Getenv(config.PassphraseEnv); env != "" {
+			if attempt > 0 {
+				return "", fmt.Errorf("%s does not decrypt %s: %w", config.PassphraseEnv, file, errors.ErrWrongPassphrase)
+			}
+			return env, nil
+		}
+
+		if passphrase, ok := known[file]; ok && attempt == 0 {
+			return passphrase, nil
+		}
+		if attempt > 0 {
+			fmt.Println("Wrong passphrase, try again.")
+		}
+
+		passphrase, err := ir.ReadPassword(fmt.Sprintf("Passphrase for %s: ", filepath.Base(file)))
+		if err != nil {
+			return "", err
+		}
+		known[file] = passphrase
+		return passphrase, nil
+	}
+}
+
+// ReadNewPassphrase asks for a new passphrase twice and checks that both
+// entries match.
+func (ir *InputReader) ReadNewPassphrase(prompt string) (string, error) {
+	first, err := ir.ReadPassword(prompt)
+	if err != nil {
+		return "", err
+	}
+	if first == "" {
+		return "", errors.ErrPassphraseRequired
+	}
+
+	second, err := ir.ReadPassword("Repeat passphrase: ")
+	if err != nil {
+		return "", err
+	}
+	if first != second {
+		return "", fmt.Errorf("passphrases do not match")
+	}
+	return first, nil
+}
+
 func (ir *InputReader) ReadBool(prompt string) (bool, error) {
 	input, err := ir.ReadString(prompt)
 	if err != nil {
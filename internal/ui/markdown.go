@@ -0,0 +1,109 @@
+package ui
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+var (
+	mdHeading  = regexp.MustCompile(`^(#{1,6})\s+(.*)$`)
+	mdRule     = regexp.MustCompile(`^\s*(-(\s*-){2,}|\*(\s*\*){2,}|_(\s*_){2,})\s*$`)
+	mdTask     = regexp.MustCompile(`^(\s*)[-*+]\s+\[([ xX])\]\s+(.*)$`)
+	mdBullet   = regexp.MustCompile(`^(\s*)[-*+]\s+(.*)$`)
+	mdOrdered  = regexp.MustCompile(`^(\s*)(\d+[.)])\s+(.*)$`)
+	mdLink     = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+	mdBold     = regexp.MustCompile(`\*\*([^*]+)\*\*|__([^_]+)__`)
+	mdItalic   = regexp.MustCompile(`\*([^*\s][^*]*)\*|\b_([^_\s][^_]*)_\b`)
+	mdCodeSpan = regexp.MustCompile("`([^`]+)`")
+)
+
+// RenderMarkdown renders the basics of Markdown for the terminal: headings,
+// lists and task lists, block quotes, rules, fenced code, and bold, italic,
+// code and links inside lines. Styles are dropped when colour is disabled,
+// e.g. when the output isn't a terminal.
+func RenderMarkdown(text string) string {
+	heading := color.New(color.Bold, color.FgCyan)
+	faint := color.New(color.Faint)
+	code := color.New(color.FgYellow)
+
+	var out []string
+	fenced := false
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			fenced = !fenced
+			continue
+		}
+		if fenced {
+			out = append(out, "    "+code.Sprint(line))
+			continue
+		}
+
+		switch {
+		case mdHeading.MatchString(line):
+			m := mdHeading.FindStringSubmatch(line)
+			title := renderInline(m[2])
+			if len(m[1]) == 1 {
+				title = strings.ToUpper(title)
+			}
+			out = append(out, heading.Sprint(title))
+		case mdRule.MatchString(line):
+			out = append(out, faint.Sprint(strings.Repeat("─", 40)))
+		case strings.HasPrefix(strings.TrimSpace(line), ">"):
+			quote := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), ">"))
+			out = append(out, faint.Sprint("│ ")+faint.Sprint(renderInline(quote)))
+		case mdTask.MatchString(line):
+			m := mdTask.FindStringSubmatch(line)
+			box := "☐"
+			if m[2] != " " {
+				box = "☑"
+			}
+			out = append(out, m[1]+box+" "+renderInline(m[3]))
+		case mdBullet.MatchString(line):
+			m := mdBullet.FindStringSubmatch(line)
+			out = append(out, m[1]+"• "+renderInline(m[2]))
+		case mdOrdered.MatchString(line):
+			m := mdOrdered.FindStringSubmatch(line)
+			out = append(out, m[1]+m[2]+" "+renderInline(m[3]))
+		default:
+			out = append(out, renderInline(line))
+		}
+	}
+	return strings.Join(out, "\n")
+}
+
+// renderInline styles code spans, links, bold and italic text in a line.
+// Nothing inside a code span is styled further.
+func renderInline(line string) string {
+	code := color.New(color.FgYellow)
+	bold := color.New(color.Bold)
+	italic := color.New(color.Italic)
+	link := color.New(color.Underline, color.FgBlue)
+	faint := color.New(color.Faint)
+
+	var b strings.Builder
+	last := 0
+	for _, span := range mdCodeSpan.FindAllStringSubmatchIndex(line, -1) {
+		b.WriteString(styleText(line[last:span[0]], bold, italic, link, faint))
+		b.WriteString(code.Sprint(line[span[2]:span[3]]))
+		last = span[1]
+	}
+	b.WriteString(styleText(line[last:], bold, italic, link, faint))
+	return b.String()
+}
+
+func styleText(text string, bold, italic, link, faint *color.Color) string {
+	text = mdLink.ReplaceAllStringFunc(text, func(s string) string {
+		m := mdLink.FindStringSubmatch(s)
+		return link.Sprint(m[1]) + faint.Sprint(" ("+m[2]+")")
+	})
+	text = mdBold.ReplaceAllStringFunc(text, func(s string) string {
+		m := mdBold.FindStringSubmatch(s)
+		return bold.Sprint(m[1] + m[2])
+	})
+	return mdItalic.ReplaceAllStringFunc(text, func(s string) string {
+		m := mdItalic.FindStringSubmatch(s)
+		return italic.Sprint(m[1] + m[2])
+	})
+}
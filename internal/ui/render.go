@@ -0,0 +1,411 @@
+package ui
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+
+	"github.com/Ng1n3/go-todo/internal/config"
+	"github.com/Ng1n3/go-todo/internal/errors"
+	"github.com/Ng1n3/go-todo/internal/service"
+	"github.com/Ng1n3/go-todo/internal/types"
+)
+
+// OutputVersion is the version of the JSON, NDJSON and YAML layouts of
+// todo listings. It changes when a field is renamed or removed or changes
+// meaning; fields may be added without changing it.
+const OutputVersion = 1
+
+// Renderer writes todo listings in one output format.
+type Renderer interface {
+	RenderTodos(w io.Writer, files []service.FileTodos) error
+}
+
+// NewRenderer returns the renderer for an output setting, such as "json" or
+// "template={{.ID}} {{.Task}}"; see config.OutputTable.
+func NewRenderer(output string) (Renderer, error) {
+	format, arg, _ := strings.Cut(output, "=")
+	switch format {
+	case config.OutputTable, "":
+		return TableRenderer{}, nil
+	case config.OutputJSON:
+		return JSONRenderer{}, nil
+	case config.OutputNDJSON:
+		return NDJSONRenderer{}, nil
+	case config.OutputCSV:
+		return CSVRenderer{}, nil
+	case config.OutputYAML:
+		return YAMLRenderer{}, nil
+	case config.OutputTemplate:
+		return NewTemplateRenderer(arg)
+	case config.OutputTemplateFile:
+		text, err := os.ReadFile(arg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read output template: %w", err)
+		}
+		return NewTemplateRenderer(string(text))
+	default:
+		return nil, fmt.Errorf("%w: unknown output %q", errors.ErrInvalidInput, output)
+	}
+}
+
+// TodoRecord is a todo as the machine-readable formats write it. Its
+// layout is independent of the storage format and only changes along with
+// OutputVersion. Every field is always present: missing dates and
+// estimates are null and missing lists are empty.
+type TodoRecord struct {
+	File      string   `json:"file"`
+	ID        string   `json:"id"`
+	Task      string   `json:"task"`
+	Status    string   `json:"status"`
+	Completed bool     `json:"completed"`
+	Priority  string   `json:"priority"`
+	Due       *string  `json:"due"`
+	Labels    []string `json:"labels"`
+	Estimate  *string  `json:"estimate"`
+	// TrackedSeconds is the time logged on the todo.
+	TrackedSeconds int64        `json:"tracked_seconds"`
+	Notes          string       `json:"notes"`
+	Links          []LinkRecord `json:"links"`
+	CreatedAt      string       `json:"created_at"`
+	UpdatedAt      string       `json:"updated_at"`
+	CompletedAt    *string      `json:"completed_at"`
+}
+
+type LinkRecord struct {
+	Kind   string `json:"kind"`
+	Target string `json:"target"`
+	Title  string `json:"title"`
+}
+
+// TodoListing is the document written by the JSON and YAML renderers.
+type TodoListing struct {
+	Version int          `json:"version"`
+	Todos   []TodoRecord `json:"todos"`
+}
+
+// NewTodoRecord converts a todo of file for output. Times are RFC 3339 and
+// due dates YYYY-MM-DD.
+func NewTodoRecord(file string, todo types.Todo) TodoRecord {
+	r := TodoRecord{
+		File:           file,
+		ID:             todo.ID,
+		Task:           todo.Task,
+		Status:         string(todo.Status),
+		Completed:      todo.Completed,
+		Priority:       string(todo.Priority.Normalize()),
+		Labels:         append([]string{}, todo.Labels...),
+		TrackedSeconds: int64(todo.Tracked() / time.Second),
+		Notes:          todo.Notes,
+		Links:          []LinkRecord{},
+		CreatedAt:      todo.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:      todo.UpdatedAt.Format(time.RFC3339),
+	}
+	if todo.HasDueDate() {
+		due := todo.DueDate.Format("2006-01-02")
+		r.Due = &due
+	}
+	if todo.Estimate != nil {
+		estimate := todo.Estimate.String()
+		r.Estimate = &estimate
+	}
+	if !todo.CompletedAt.IsZero() {
+		completedAt := todo.CompletedAt.Format(time.RFC3339)
+		r.CompletedAt = &completedAt
+	}
+	for _, link := range todo.Links {
+		r.Links = append(r.Links, LinkRecord{Kind: string(link.Kind), Target: link.Target, Title: link.Title})
+	}
+	return r
+}
+
+func records(files []service.FileTodos) []TodoRecord {
+	out := []TodoRecord{}
+	for _, file := range files {
+		for _, todo := range file.Todos {
+			out = append(out, NewTodoRecord(file.File, todo))
+		}
+	}
+	return out
+}
+
+// TableRenderer draws todos as a table, with a File column when they come
+// from more than one file. Rows are numbered across files, so the numbers
+// can be used to pick a todo.
+type TableRenderer struct{}
+
+func (TableRenderer) RenderTodos(w io.Writer, files []service.FileTodos) error {
+	var all []types.Todo
+	for _, file := range files {
+		all = append(all, file.Todos...)
+	}
+	if len(all) == 0 {
+		_, err := fmt.Fprintln(w, "No todos found.")
+		return err
+	}
+
+	header := []string{"#", "ID", "Task", "Due Date", "Priority", "Status", "Labels", "Estimate", "Created", "Updated"}
+	if len(files) > 1 {
+		header = append([]string{"File"}, header...)
+	}
+	table := tablewriter.NewWriter(w)
+	table.Header(header)
+
+	n := 0
+	for _, file := range files {
+		for _, todo := range file.Todos {
+			n++
+			row := []string{
+				strconv.Itoa(n),
+				todo.ID,
+				todo.Task,
+				todo.DueDate.Format("2006-01-02"),
+				string(todo.Priority),
+				todo.Status.Label(),
+				strings.Join(todo.Labels, ", "),
+				formatEstimate(todo.Estimate),
+				todo.CreatedAt.Format("2006-01-02"),
+				todo.UpdatedAt.Format("2006-01-02"),
+			}
+			if len(files) > 1 {
+				row = append([]string{file.File}, row...)
+			}
+			table.Append(row)
+		}
+	}
+
+	if total := formatEstimateTotal(all); total != "" {
+		footer := make([]string, len(header))
+		footer[len(footer)-4], footer[len(footer)-3] = "Total", total
+		table.Footer(footer)
+	}
+	return table.Render()
+}
+
+// JSONRenderer writes a TodoListing as indented JSON.
+type JSONRenderer struct{}
+
+func (JSONRenderer) RenderTodos(w io.Writer, files []service.FileTodos) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	return enc.Encode(TodoListing{Version: OutputVersion, Todos: records(files)})
+}
+
+// NDJSONRenderer writes one TodoRecord per line, for streaming into tools
+// such as jq. The records are those of the JSON listing.
+type NDJSONRenderer struct{}
+
+func (NDJSONRenderer) RenderTodos(w io.Writer, files []service.FileTodos) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	for _, r := range records(files) {
+		if err := enc.Encode(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// csvHeader names the CSV columns, which hold the TodoRecord fields in the
+// same order.
+var csvHeader = []string{"file", "id", "task", "status", "completed", "priority", "due", "labels", "estimate", "tracked_seconds", "notes", "links", "created_at", "updated_at", "completed_at"}
+
+// CSVRenderer writes a header and a row per todo. Labels are joined with
+// commas and links are written one per line as Markdown links; missing
+// values are empty.
+type CSVRenderer struct{}
+
+func (CSVRenderer) RenderTodos(w io.Writer, files []service.FileTodos) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+
+	optional := func(s *string) string {
+		if s == nil {
+			return ""
+		}
+		return *s
+	}
+	for _, r := range records(files) {
+		links := make([]string, len(r.Links))
+		for i, link := range r.Links {
+			title := link.Title
+			if title == "" {
+				title = link.Target
+			}
+			links[i] = fmt.Sprintf("[%s](%s)", title, link.Target)
+		}
+		if err := cw.Write([]string{
+			r.File,
+			r.ID,
+			r.Task,
+			r.Status,
+			strconv.FormatBool(r.Completed),
+			r.Priority,
+			optional(r.Due),
+			strings.Join(r.Labels, ","),
+			optional(r.Estimate),
+			strconv.FormatInt(r.TrackedSeconds, 10),
+			r.Notes,
+			strings.Join(links, "\n"),
+			r.CreatedAt,
+			r.UpdatedAt,
+			optional(r.CompletedAt),
+		}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// YAMLRenderer writes a TodoListing as YAML, with the keys of the JSON
+// output. Strings are always double-quoted, so no value is mistaken for a
+// number, a boolean or null.
+type YAMLRenderer struct{}
+
+func (YAMLRenderer) RenderTodos(w io.Writer, files []service.FileTodos) error {
+	var b bytes.Buffer
+	if err := writeYAML(&b, reflect.ValueOf(TodoListing{Version: OutputVersion, Todos: records(files)}), 0, false); err != nil {
+		return err
+	}
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// writeYAML writes v in block style at the given indentation. inList is
+// set for the first key of a mapping that is an item of a sequence, which
+// follows the "- " on the same line.
+func writeYAML(b *bytes.Buffer, v reflect.Value, indent int, inList bool) error {
+	pad := strings.Repeat("  ", indent)
+
+	switch v.Kind() {
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			key, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+			if i > 0 || !inList {
+				b.WriteString(pad)
+			}
+			b.WriteString(key + ":")
+
+			field := v.Field(i)
+			if field.Kind() == reflect.Pointer && !field.IsNil() {
+				field = field.Elem()
+			}
+			switch {
+			case field.Kind() == reflect.Slice && field.Len() > 0:
+				b.WriteString("\n")
+				if err := writeYAML(b, field, indent, false); err != nil {
+					return err
+				}
+			case field.Kind() == reflect.Struct:
+				b.WriteString("\n")
+				if err := writeYAML(b, field, indent+1, false); err != nil {
+					return err
+				}
+			default:
+				b.WriteString(" ")
+				if err := writeYAML(b, field, indent, false); err != nil {
+					return err
+				}
+				b.WriteString("\n")
+			}
+		}
+	case reflect.Slice:
+		if v.Len() == 0 {
+			b.WriteString("[]")
+			return nil
+		}
+		for i := 0; i < v.Len(); i++ {
+			b.WriteString(pad + "- ")
+			item := v.Index(i)
+			if item.Kind() == reflect.Struct {
+				if err := writeYAML(b, item, indent+1, true); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := writeYAML(b, item, indent+1, false); err != nil {
+				return err
+			}
+			b.WriteString("\n")
+		}
+	case reflect.Pointer:
+		// Only nil pointers get here.
+		b.WriteString("null")
+	case reflect.String:
+		// JSON string escapes are valid in YAML double-quoted scalars.
+		quoted, err := marshalJSON(v.String())
+		if err != nil {
+			return err
+		}
+		b.WriteString(quoted)
+	case reflect.Bool:
+		b.WriteString(strconv.FormatBool(v.Bool()))
+	case reflect.Int, reflect.Int64:
+		b.WriteString(strconv.FormatInt(v.Int(), 10))
+	default:
+		return fmt.Errorf("cannot write %s as YAML", v.Kind())
+	}
+	return nil
+}
+
+// TemplateRenderer executes a Go text/template for every todo, with the
+// TodoRecord as data, and ends each result with a newline unless it
+// already ends with one. Besides the built-in functions, join joins a list
+// of strings with a separator, as in {{join .Labels ","}}, and json writes a
+// value as JSON.
+type TemplateRenderer struct {
+	tmpl *template.Template
+}
+
+func NewTemplateRenderer(text string) (*TemplateRenderer, error) {
+	tmpl, err := template.New("output").Funcs(template.FuncMap{
+		"join": func(items []string, sep string) string { return strings.Join(items, sep) },
+		"json": marshalJSON,
+	}).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("%w: output template: %v", errors.ErrInvalidInput, err)
+	}
+	return &TemplateRenderer{tmpl: tmpl}, nil
+}
+
+func (t *TemplateRenderer) RenderTodos(w io.Writer, files []service.FileTodos) error {
+	for _, r := range records(files) {
+		var b bytes.Buffer
+		if err := t.tmpl.Execute(&b, r); err != nil {
+			return fmt.Errorf("failed to execute output template: %w", err)
+		}
+		if b.Len() == 0 || b.Bytes()[b.Len()-1] != '\n' {
+			b.WriteByte('\n')
+		}
+		if _, err := w.Write(b.Bytes()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// marshalJSON writes v as JSON without escaping HTML characters such as &.
+func marshalJSON(v any) (string, error) {
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(b.String(), "\n"), nil
+}
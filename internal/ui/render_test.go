@@ -0,0 +1,197 @@
+package ui
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/Ng1n3/go-todo/internal/service"
+	"github.com/Ng1n3/go-todo/internal/types"
+)
+
+// The expected outputs below pin the layouts of OutputVersion 1. A change
+// to them that isn't only an added field needs a new OutputVersion.
+
+func testFiles() []service.FileTodos {
+	created := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	return []service.FileTodos{
+		{File: "work.json", Todos: []types.Todo{
+			{
+				ID:          "abc123",
+				Task:        `Ship "v2" & celebrate`,
+				Notes:       "See the spec.",
+				Links:       []types.Link{{Kind: types.LinkURL, Target: "https://example.com/a?b=1&c=2", Title: "Spec"}},
+				Labels:      []string{"work", "work/release"},
+				Completed:   true,
+				Status:      types.StatusDone,
+				DueDate:     time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC),
+				Priority:    "high",
+				Estimate:    &types.Estimate{Minutes: 90},
+				CreatedAt:   created,
+				UpdatedAt:   created.Add(time.Hour),
+				CompletedAt: created.Add(2 * time.Hour),
+				TimeEntries: []types.TimeEntry{{Start: created, End: created.Add(25 * time.Minute)}},
+			},
+		}},
+		{File: "home.json", Todos: []types.Todo{
+			{
+				ID:        "def456",
+				Task:      "Water plants",
+				Status:    types.StatusTodo,
+				Priority:  types.Low,
+				CreatedAt: created,
+				UpdatedAt: created,
+			},
+		}},
+	}
+}
+
+func render(t *testing.T, output string) string {
+	t.Helper()
+	r, err := NewRenderer(output)
+	if err != nil {
+		t.Fatalf("NewRenderer(%q): %v", output, err)
+	}
+	var b bytes.Buffer
+	if err := r.RenderTodos(&b, testFiles()); err != nil {
+		t.Fatalf("RenderTodos(%q): %v", output, err)
+	}
+	return b.String()
+}
+
+func TestRenderers(t *testing.T) {
+	tests := []struct {
+		output string
+		want   string
+	}{
+		{output: "json", want: `{
+  "version": 1,
+  "todos": [
+    {
+      "file": "work.json",
+      "id": "abc123",
+      "task": "Ship \"v2\" & celebrate",
+      "status": "done",
+      "completed": true,
+      "priority": "HIGH",
+      "due": "2026-03-05",
+      "labels": [
+        "work",
+        "work/release"
+      ],
+      "estimate": "1h30m",
+      "tracked_seconds": 1500,
+      "notes": "See the spec.",
+      "links": [
+        {
+          "kind": "url",
+          "target": "https://example.com/a?b=1&c=2",
+          "title": "Spec"
+        }
+      ],
+      "created_at": "2026-03-01T09:00:00Z",
+      "updated_at": "2026-03-01T10:00:00Z",
+      "completed_at": "2026-03-01T11:00:00Z"
+    },
+    {
+      "file": "home.json",
+      "id": "def456",
+      "task": "Water plants",
+      "status": "todo",
+      "completed": false,
+      "priority": "LOW",
+      "due": null,
+      "labels": [],
+      "estimate": null,
+      "tracked_seconds": 0,
+      "notes": "",
+      "links": [],
+      "created_at": "2026-03-01T09:00:00Z",
+      "updated_at": "2026-03-01T09:00:00Z",
+      "completed_at": null
+    }
+  ]
+}
+`},
+		{output: "ndjson", want: `{"file":"work.json","id":"abc123","task":"Ship \"v2\" & celebrate","status":"done","completed":true,"priority":"HIGH","due":"2026-03-05","labels":["work","work/release"],"estimate":"1h30m","tracked_seconds":1500,"notes":"See the spec.","links":[{"kind":"url","target":"https://example.com/a?b=1&c=2","title":"Spec"}],"created_at":"2026-03-01T09:00:00Z","updated_at":"2026-03-01T10:00:00Z","completed_at":"2026-03-01T11:00:00Z"}
+{"file":"home.json","id":"def456","task":"Water plants","status":"todo","completed":false,"priority":"LOW","due":null,"labels":[],"estimate":null,"tracked_seconds":0,"notes":"","links":[],"created_at":"2026-03-01T09:00:00Z","updated_at":"2026-03-01T09:00:00Z","completed_at":null}
+`},
+		{output: "csv", want: `file,id,task,status,completed,priority,due,labels,estimate,tracked_seconds,notes,links,created_at,updated_at,completed_at
+work.json,abc123,"Ship ""v2"" & celebrate",done,true,HIGH,2026-03-05,"work,work/release",1h30m,1500,See the spec.,[Spec](https://example.com/a?b=1&c=2),2026-03-01T09:00:00Z,2026-03-01T10:00:00Z,2026-03-01T11:00:00Z
+home.json,def456,Water plants,todo,false,LOW,,,,0,,,2026-03-01T09:00:00Z,2026-03-01T09:00:00Z,
+`},
+		{output: "yaml", want: `version: 1
+todos:
+- file: "work.json"
+  id: "abc123"
+  task: "Ship \"v2\" & celebrate"
+  status: "done"
+  completed: true
+  priority: "HIGH"
+  due: "2026-03-05"
+  labels:
+  - "work"
+  - "work/release"
+  estimate: "1h30m"
+  tracked_seconds: 1500
+  notes: "See the spec."
+  links:
+  - kind: "url"
+    target: "https://example.com/a?b=1&c=2"
+    title: "Spec"
+  created_at: "2026-03-01T09:00:00Z"
+  updated_at: "2026-03-01T10:00:00Z"
+  completed_at: "2026-03-01T11:00:00Z"
+- file: "home.json"
+  id: "def456"
+  task: "Water plants"
+  status: "todo"
+  completed: false
+  priority: "LOW"
+  due: null
+  labels: []
+  estimate: null
+  tracked_seconds: 0
+  notes: ""
+  links: []
+  created_at: "2026-03-01T09:00:00Z"
+  updated_at: "2026-03-01T09:00:00Z"
+  completed_at: null
+`},
+		{
+			output: `template={{.ID}} {{.Task}}{{with .Due}} ({{.}}){{end}} [{{join .Labels ","}}]`,
+			want:   "abc123 Ship \"v2\" & celebrate (2026-03-05) [work,work/release]\ndef456 Water plants []\n",
+		},
+		{
+			output: "template={{json .Links}}\n",
+			want:   "[{\"kind\":\"url\",\"target\":\"https://example.com/a?b=1&c=2\",\"title\":\"Spec\"}]\n[]\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.output, func(t *testing.T) {
+			if got := render(t, tt.output); got != tt.want {
+				t.Errorf("output %s:\ngot:\n%s\nwant:\n%s", tt.output, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTemplateRendererErrors(t *testing.T) {
+	if _, err := NewRenderer("template={{.ID"); err == nil {
+		t.Error("NewRenderer accepted an unclosed action")
+	}
+
+	r, err := NewRenderer("template={{.Missing}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.RenderTodos(&bytes.Buffer{}, testFiles()); err == nil {
+		t.Error("RenderTodos succeeded with an unknown field")
+	}
+}
+
+func TestNewRendererRejectsUnknownOutput(t *testing.T) {
+	if _, err := NewRenderer("xml"); err == nil {
+		t.Error(`NewRenderer("xml") succeeded`)
+	}
+}
@@ -0,0 +1,188 @@
+// Package urgency scores open todos by how urgent they are, in the spirit
+// of Taskwarrior: priority, how close the due date is, age, blocked state
+// and label weights each add a term, weighed by the configured
+// coefficients.
+package urgency
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Ng1n3/go-todo/internal/config"
+	"github.com/Ng1n3/go-todo/internal/service"
+	"github.com/Ng1n3/go-todo/internal/types"
+	"github.com/Ng1n3/go-todo/internal/utils"
+)
+
+// Term is one part of an urgency score: Factor, between 0 and 1 for due
+// dates and age and 1 otherwise, times Coefficient.
+type Term struct {
+	Name        string
+	Factor      float64
+	Coefficient float64
+}
+
+func (t Term) Value() float64 {
+	return t.Factor * t.Coefficient
+}
+
+// String explains the term, e.g. "due in 3d 0.69×12 = +8.2".
+func (t Term) String() string {
+	if t.Factor == 1 {
+		return fmt.Sprintf("%s %+.1f", t.Name, t.Value())
+	}
+	return fmt.Sprintf("%s %.2f×%g = %+.1f", t.Name, t.Factor, t.Coefficient, t.Value())
+}
+
+// Score is the urgency of a todo and the terms it is made of, in the order
+// priority, due date, age, blocked, labels. Terms that add nothing are left
+// out.
+type Score struct {
+	Value float64
+	Terms []Term
+}
+
+// Explain lists the terms of the score that add or take away at least
+// 0.1 once rounded.
+func (s Score) Explain() string {
+	terms := make([]string, 0, len(s.Terms))
+	for _, term := range s.Terms {
+		// A brand-new todo's age adds next to nothing; don't list it.
+		if math.Abs(term.Value()) < 0.05 {
+			continue
+		}
+		terms = append(terms, term.String())
+	}
+	if len(terms) == 0 {
+		return "nothing adds urgency"
+	}
+	return strings.Join(terms, ", ")
+}
+
+// Compute scores a todo at now with the coefficients c.
+func Compute(todo types.Todo, c config.Urgency, now time.Time) Score {
+	var s Score
+	add := func(name string, factor, coefficient float64) {
+		if factor == 0 || coefficient == 0 {
+			return
+		}
+		s.Terms = append(s.Terms, Term{Name: name, Factor: factor, Coefficient: coefficient})
+		s.Value += factor * coefficient
+	}
+
+	switch todo.Priority.Normalize() {
+	case types.High:
+		add("priority high", 1, c.PriorityHigh)
+	case types.Medium:
+		add("priority medium", 1, c.PriorityMedium)
+	case types.Low:
+		add("priority low", 1, c.PriorityLow)
+	}
+
+	if todo.HasDueDate() {
+		name, factor := dueFactor(todo.Deadline(), now)
+		add(name, factor, c.Due)
+	}
+
+	if c.AgeMax > 0 && now.After(todo.CreatedAt) && !todo.CreatedAt.IsZero() {
+		age := now.Sub(todo.CreatedAt)
+		factor := float64(age) / float64(c.AgeMax)
+		if factor > 1 {
+			factor = 1
+		}
+		add(fmt.Sprintf("age %dd", int(age.Hours()/24)), factor, c.Age)
+	}
+
+	if slices.Contains(c.BlockedStatuses, todo.Status) {
+		add("blocked", 1, c.Blocked)
+	}
+
+	// Each weighted label counts once, however many of the todo's labels
+	// fall below it.
+	weighted := make([]string, 0, len(c.Labels))
+	for label := range c.Labels {
+		weighted = append(weighted, label)
+	}
+	sort.Strings(weighted)
+	for _, label := range weighted {
+		for _, have := range todo.Labels {
+			if utils.LabelWithin(have, label) {
+				add("label "+label, 1, c.Labels[label])
+				break
+			}
+		}
+	}
+	return s
+}
+
+// dueFactor grows linearly from 0.2 two weeks or more before deadline to 1
+// a week or more after it, and names the term after how far off it is.
+func dueFactor(deadline, now time.Time) (string, float64) {
+	days := now.Sub(deadline).Hours() / 24
+
+	name := "due in " + formatDays(-days)
+	if days >= 0 {
+		name = "overdue " + formatDays(days)
+	}
+
+	switch {
+	case days >= 7:
+		return name, 1
+	case days >= -14:
+		return name, 0.2 + (days+14)*0.8/21
+	default:
+		return name, 0.2
+	}
+}
+
+// formatDays formats a number of days, rounded up, e.g. "3d".
+func formatDays(days float64) string {
+	n := int(days)
+	if float64(n) < days {
+		n++
+	}
+	if n < 1 {
+		return "<1d"
+	}
+	return fmt.Sprintf("%dd", n)
+}
+
+// Ranked is an open todo of a file with its urgency.
+type Ranked struct {
+	File  string
+	Todo  types.Todo
+	Score Score
+}
+
+// Next ranks the open todos of files matching filter by urgency, most
+// urgent first, and returns the first n, or all of them when n is zero.
+// Todos in one of the blocked statuses can't be acted on and are left out
+// unless blocked is true; then they are ranked too, and the blocked
+// coefficient usually sinks them.
+func Next(files []service.FileTodos, filter service.Filter, c config.Urgency, n int, blocked bool, now time.Time) []Ranked {
+	var ranked []Ranked
+	for _, file := range files {
+		for _, todo := range filter.Apply(file.Todos, now) {
+			if todo.Completed || (!blocked && slices.Contains(c.BlockedStatuses, todo.Status)) {
+				continue
+			}
+			ranked = append(ranked, Ranked{File: file.File, Todo: todo, Score: Compute(todo, c, now)})
+		}
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		a, b := ranked[i], ranked[j]
+		if a.Score.Value != b.Score.Value {
+			return a.Score.Value > b.Score.Value
+		}
+		return a.Todo.CreatedAt.Before(b.Todo.CreatedAt)
+	})
+	if n > 0 && len(ranked) > n {
+		ranked = ranked[:n]
+	}
+	return ranked
+}
@@ -0,0 +1,64 @@
+package urgency
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/Ng1n3/go-todo/internal/config"
+	"github.com/Ng1n3/go-todo/internal/service"
+	"github.com/Ng1n3/go-todo/internal/types"
+)
+
+func TestBlockedStatuses(t *testing.T) {
+	now := time.Date(2026, 3, 5, 12, 0, 0, 0, time.UTC)
+	c := config.Default().Urgency
+	c.BlockedStatuses = []types.Status{types.StatusBlocked, "waiting"}
+
+	open := Compute(types.Todo{Priority: types.Medium, Status: types.StatusTodo, CreatedAt: now}, c, now).Value
+	tests := []struct {
+		status types.Status
+		want   float64
+	}{
+		{status: types.StatusTodo, want: open},
+		{status: types.StatusBlocked, want: open + c.Blocked},
+		{status: "waiting", want: open + c.Blocked},
+		{status: "doing", want: open},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.status), func(t *testing.T) {
+			todo := types.Todo{Priority: types.Medium, Status: tt.status, CreatedAt: now}
+			if got := Compute(todo, c, now).Value; got != tt.want {
+				t.Errorf("Compute = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNextLeavesOutBlocked(t *testing.T) {
+	now := time.Date(2026, 3, 5, 12, 0, 0, 0, time.UTC)
+	c := config.Default().Urgency
+	overdue := now.AddDate(0, 0, -10)
+	files := []service.FileTodos{{File: "work.json", Todos: []types.Todo{
+		{ID: "blocked", Task: "urgent but blocked", Priority: types.High, Status: types.StatusBlocked, DueDate: overdue, CreatedAt: now},
+		{ID: "open", Task: "can be done", Priority: types.Low, Status: types.StatusTodo, CreatedAt: now},
+		{ID: "done", Task: "finished", Priority: types.High, Status: types.StatusDone, Completed: true, CreatedAt: now},
+	}}}
+
+	tests := []struct {
+		blocked bool
+		want    []string
+	}{
+		{blocked: false, want: []string{"open"}},
+		{blocked: true, want: []string{"blocked", "open"}},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, r := range Next(files, service.Filter{}, c, 0, tt.blocked, now) {
+			got = append(got, r.Todo.ID)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("Next(blocked=%v) = %v, want %v", tt.blocked, got, tt.want)
+		}
+	}
+}
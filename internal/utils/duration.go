@@ -0,0 +1,78 @@
+package utils
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Ng1n3/go-todo/internal/errors"
+)
+
+var longUnits = map[byte]time.Duration{
+	'd': 24 * time.Hour,
+	'w': 7 * 24 * time.Hour,
+}
+
+// ParseDuration parses a duration like time.ParseDuration, but also accepts
+// day ("3d") and week ("2w") units, optionally combined ("1w2d", "1d12h").
+func ParseDuration(input string) (time.Duration, error) {
+	input = strings.TrimSpace(strings.ToLower(input))
+	if input == "" {
+		return 0, errors.ErrInvalidDuration
+	}
+
+	negative := false
+	switch input[0] {
+	case '-':
+		negative = true
+		input = input[1:]
+	case '+':
+		input = input[1:]
+	}
+
+	var total time.Duration
+	for input != "" {
+		i := 0
+		for i < len(input) && input[i] >= '0' && input[i] <= '9' {
+			i++
+		}
+		if i == 0 {
+			return 0, errors.ErrInvalidDuration
+		}
+
+		if i < len(input) {
+			if size, ok := longUnits[input[i]]; ok {
+				n, err := strconv.Atoi(input[:i])
+				if err != nil {
+					return 0, errors.ErrInvalidDuration
+				}
+				total += time.Duration(n) * size
+				input = input[i+1:]
+				continue
+			}
+		}
+
+		// Hand the remainder to the standard parser once no day or week units are left.
+		rest, err := time.ParseDuration(input)
+		if err != nil {
+			return 0, errors.ErrInvalidDuration
+		}
+		total += rest
+		break
+	}
+
+	if negative {
+		total = -total
+	}
+	return total, nil
+}
+
+// FormatDuration renders a duration using the largest whole day unit when
+// possible, so "24h0m0s" reads as "1d".
+func FormatDuration(d time.Duration) string {
+	day := 24 * time.Hour
+	if d != 0 && d%day == 0 {
+		return strconv.Itoa(int(d/day)) + "d"
+	}
+	return d.String()
+}
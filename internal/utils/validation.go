@@ -1,10 +1,16 @@
 package utils
 
 import (
+	"fmt"
+	"math"
+	"net/url"
+	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/Ng1n3/go-todo/internal/errors"
+	"github.com/Ng1n3/go-todo/internal/types"
 )
 
 func ValidateTask(task string) (string, error) {
@@ -30,6 +36,9 @@ func ValidateDate(dateStr string) (time.Time, error) {
 	return parsedDate, nil
 }
 
+// ValidateLabels splits comma-separated labels, tidies their whitespace and
+// slashes and drops empty ones and repeats of the same label, keeping the
+// first spelling.
 func ValidateLabels(labelsInput string) []string {
 	if labelsInput == "" {
 		return []string{}
@@ -39,9 +48,11 @@ func ValidateLabels(labelsInput string) []string {
 	labels := strings.Split(labelsInput, ",")
 
 	var cleanLabel []string
+	seen := make(map[string]bool)
 	for _, label := range labels {
-		label = strings.TrimSpace(label)
-		if label != "" {
+		label = tidyLabel(label)
+		if label != "" && !seen[NormalizeLabel(label)] {
+			seen[NormalizeLabel(label)] = true
 			cleanLabel = append(cleanLabel, label)
 		}
 	}
@@ -49,6 +60,50 @@ func ValidateLabels(labelsInput string) []string {
 	return cleanLabel
 }
 
+// tidyLabel collapses runs of whitespace and drops empty levels of a
+// hierarchical label, so " clients / acme/ " becomes "clients/acme".
+func tidyLabel(label string) string {
+	var levels []string
+	for _, level := range strings.Split(label, "/") {
+		if level = strings.Join(strings.Fields(level), " "); level != "" {
+			levels = append(levels, level)
+		}
+	}
+	return strings.Join(levels, "/")
+}
+
+// NormalizeLabel returns the form labels are compared in: tidied and in
+// lower case, so "Work", "work" and " work " are the same label.
+func NormalizeLabel(label string) string {
+	return strings.ToLower(tidyLabel(label))
+}
+
+// SameLabel reports whether two labels are the same once normalized.
+func SameLabel(a, b string) bool {
+	return NormalizeLabel(a) == NormalizeLabel(b)
+}
+
+// LabelWithin reports whether label is ancestor or one of its descendants
+// in the label hierarchy: "clients/acme/billing" is within "clients/acme"
+// and "clients", but not within "client".
+func LabelWithin(label, ancestor string) bool {
+	label, ancestor = NormalizeLabel(label), NormalizeLabel(ancestor)
+	return ancestor != "" && (label == ancestor || strings.HasPrefix(label, ancestor+"/"))
+}
+
+// ReparentLabel moves label from under ancestor to under replacement,
+// keeping the levels below: "clients/acme/billing" moved from
+// "clients/acme" to "customers/acme" is "customers/acme/billing". label
+// must be within ancestor.
+func ReparentLabel(label, ancestor, replacement string) string {
+	depth := strings.Count(tidyLabel(ancestor), "/") + 1
+	levels := strings.Split(tidyLabel(label), "/")
+	if depth > len(levels) {
+		depth = len(levels)
+	}
+	return strings.Join(append([]string{tidyLabel(replacement)}, levels[depth:]...), "/")
+}
+
 func ValidateCompleted(completedInput string) (bool, error) {
 	completedInput = strings.TrimSpace(strings.ToLower(completedInput))
 
@@ -63,3 +118,84 @@ func ValidateCompleted(completedInput string) (bool, error) {
 		return false, errors.ErrInvalidCompletedValue
 	}
 }
+
+// ValidateEstimate parses an estimate such as "90m", "2h", "1h30m" or
+// "3pt". Time estimates are rounded to whole minutes. An empty input means no
+// estimate and yields nil.
+func ValidateEstimate(input string) (*types.Estimate, error) {
+	input = strings.TrimSpace(strings.ToLower(input))
+	if input == "" {
+		return nil, nil
+	}
+
+	for _, unit := range []string{"points", "pts", "pt"} {
+		if value, ok := strings.CutSuffix(input, unit); ok {
+			points, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil || CheckEstimate(types.Estimate{Points: points}) != nil {
+				return nil, fmt.Errorf("%w: %q", errors.ErrInvalidEstimate, input)
+			}
+			return &types.Estimate{Points: points}, nil
+		}
+	}
+
+	duration, err := ParseDuration(input)
+	if err != nil || duration < time.Minute {
+		return nil, fmt.Errorf("%w: %q, use e.g. 90m, 2h or 3pt", errors.ErrInvalidEstimate, input)
+	}
+	return &types.Estimate{Minutes: int(duration.Round(time.Minute) / time.Minute)}, nil
+}
+
+// CheckEstimate checks an estimate that was not parsed from text: exactly one
+// of minutes and points must be set, to a positive, finite value.
+func CheckEstimate(e types.Estimate) error {
+	switch {
+	case math.IsNaN(e.Points) || math.IsInf(e.Points, 0):
+		return fmt.Errorf("%w: points must be a number", errors.ErrInvalidEstimate)
+	case e.Minutes < 0 || e.Points < 0:
+		return fmt.Errorf("%w: it can't be negative", errors.ErrInvalidEstimate)
+	case (e.Minutes > 0) == (e.Points > 0):
+		return fmt.Errorf("%w: set either minutes or points", errors.ErrInvalidEstimate)
+	}
+	return nil
+}
+
+// ValidateLink trims a link and checks that its target fits its kind: URLs
+// need a scheme and a host, e.g. https://example.com.
+func ValidateLink(link types.Link) (types.Link, error) {
+	link.Target = strings.TrimSpace(link.Target)
+	link.Title = strings.TrimSpace(link.Title)
+	if link.Target == "" {
+		return types.Link{}, fmt.Errorf("%w: the target is empty", errors.ErrInvalidLink)
+	}
+
+	switch link.Kind {
+	case types.LinkURL:
+		u, err := url.Parse(link.Target)
+		if err != nil || u.Scheme == "" || (u.Host == "" && u.Opaque == "") {
+			return types.Link{}, fmt.Errorf("%w: %q is not a URL", errors.ErrInvalidLink, link.Target)
+		}
+	case types.LinkFile, types.LinkTodo:
+	default:
+		return types.Link{}, fmt.Errorf("%w: unknown kind %q, use url, file or todo", errors.ErrInvalidLink, link.Kind)
+	}
+	return link, nil
+}
+
+// NormalizeFileName turns user input such as "work" or "work.json" into the
+// base name of a todo file, "work.json".
+func NormalizeFileName(input string) (string, error) {
+	name := strings.TrimSpace(input)
+	if name == "" {
+		return "", errors.ErrInvalidInput
+	}
+
+	name = strings.TrimSuffix(name, ".json")
+
+	name = filepath.Base(name)
+
+	if name == "" || name == "." {
+		return "", errors.ErrInvalidInput
+	}
+
+	return name + ".json", nil
+}
@@ -0,0 +1,93 @@
+package utils
+
+import (
+	stderrors "errors"
+	"math"
+	"testing"
+
+	"github.com/Ng1n3/go-todo/internal/errors"
+	"github.com/Ng1n3/go-todo/internal/types"
+)
+
+func TestValidateEstimate(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    *types.Estimate
+		wantErr bool
+	}{
+		{input: "", want: nil},
+		{input: "90m", want: &types.Estimate{Minutes: 90}},
+		{input: "1h30m", want: &types.Estimate{Minutes: 90}},
+		{input: "3pt", want: &types.Estimate{Points: 3}},
+		{input: "0.5 points", want: &types.Estimate{Points: 0.5}},
+		{input: "nanpt", wantErr: true},
+		{input: "NaN pts", wantErr: true},
+		{input: "infpt", wantErr: true},
+		{input: "-2pt", wantErr: true},
+		{input: "0pt", wantErr: true},
+		{input: "30s", wantErr: true},
+		{input: "soon", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ValidateEstimate(tt.input)
+			if tt.wantErr {
+				if !stderrors.Is(err, errors.ErrInvalidEstimate) {
+					t.Fatalf("ValidateEstimate(%q) = %v, %v; want ErrInvalidEstimate", tt.input, got, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ValidateEstimate(%q): %v", tt.input, err)
+			}
+			if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+				t.Errorf("ValidateEstimate(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckEstimate(t *testing.T) {
+	tests := []struct {
+		name     string
+		estimate types.Estimate
+		wantErr  bool
+	}{
+		{name: "minutes", estimate: types.Estimate{Minutes: 45}},
+		{name: "points", estimate: types.Estimate{Points: 2}},
+		{name: "empty", estimate: types.Estimate{}, wantErr: true},
+		{name: "both", estimate: types.Estimate{Minutes: 45, Points: 2}, wantErr: true},
+		{name: "negative minutes", estimate: types.Estimate{Minutes: -5}, wantErr: true},
+		{name: "negative points", estimate: types.Estimate{Points: -1}, wantErr: true},
+		{name: "nan", estimate: types.Estimate{Points: math.NaN()}, wantErr: true},
+		{name: "inf", estimate: types.Estimate{Points: math.Inf(1)}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckEstimate(tt.estimate)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CheckEstimate(%+v) = %v, want error %v", tt.estimate, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestReparentLabel(t *testing.T) {
+	tests := []struct {
+		label, ancestor, replacement, want string
+	}{
+		{"clients/acme/billing", "clients/acme", "customers/acme", "customers/acme/billing"},
+		{"clients/acme", "clients/acme", "customers", "customers"},
+		{"Clients/Acme/Billing", "clients", "work", "work/Acme/Billing"},
+		{" clients / acme ", "clients", "customers", "customers/acme"},
+		{"a", "a", "a/b", "a/b"},
+		{"a/b", "a", "a/b", "a/b/b"},
+		{"a/c", "a", "a/b", "a/b/c"},
+		{"a/b/c", "a/b", "a", "a/c"},
+	}
+	for _, tt := range tests {
+		if got := ReparentLabel(tt.label, tt.ancestor, tt.replacement); got != tt.want {
+			t.Errorf("ReparentLabel(%q, %q, %q) = %q, want %q", tt.label, tt.ancestor, tt.replacement, got, tt.want)
+		}
+	}
+}